@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// runAdmin handles `apiodactyl admin bootstrap`, which creates the first admin
+// account or promotes an existing user, since the API only ever registers
+// normal users.
+func runAdmin(cfg *config.Config, args []string) error {
+	if len(args) == 0 || args[0] != "bootstrap" {
+		return fmt.Errorf("usage: apiodactyl admin bootstrap -username NAME [-email EMAIL] [-password PASSWORD]")
+	}
+
+	fs := flag.NewFlagSet("admin bootstrap", flag.ContinueOnError)
+	username := fs.String("username", "", "username of the admin account")
+	email := fs.String("email", "", "email for a newly created account")
+	password := fs.String("password", os.Getenv("APIODACTYL_ADMIN_PASSWORD"), "password for a newly created account (defaults to $APIODACTYL_ADMIN_PASSWORD)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if *username == "" {
+		return fmt.Errorf("-username is required")
+	}
+
+	db, err := database.InitDB(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	var userID int64
+	err = db.QueryRowContext(ctx, `SELECT id FROM users WHERE username = ?`, *username).Scan(&userID)
+	if err == nil {
+		_, err = db.ExecContext(ctx, `
+			UPDATE users SET role = ?, disabled = 0, updated_at = CURRENT_TIMESTAMP WHERE id = ?
+		`, models.RoleAdmin, userID)
+		if err != nil {
+			return fmt.Errorf("failed to promote %s: %w", *username, err)
+		}
+		fmt.Printf("promoted existing user %s (id %d) to admin\n", *username, userID)
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to look up %s: %w", *username, err)
+	}
+
+	if *email == "" || len(*password) < 8 {
+		return fmt.Errorf("creating a new admin requires -email and a -password of at least 8 characters")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO users (username, email, password_hash, role) VALUES (?, ?, ?, ?) RETURNING id
+	`, *username, *email, string(hashedPassword), models.RoleAdmin).Scan(&userID)
+	if err != nil {
+		return fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	fmt.Printf("created admin %s (id %d)\n", *username, userID)
+	return nil
+}
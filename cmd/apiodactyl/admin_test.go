@@ -0,0 +1,59 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func TestBootstrapPromotesExistingUser(t *testing.T) {
+	cfg := &config.Config{Database: config.DatabaseConfig{Path: filepath.Join(t.TempDir(), "test.db")}}
+
+	db, err := database.InitDB(cfg.Database.Path)
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	defer db.Close()
+	for _, username := range []string{"alice", "bob"} {
+		_, err := db.Exec(`INSERT INTO users (username, email, password_hash, role, disabled) VALUES (?, ?, 'x', ?, 1)`,
+			username, username+"@example.com", models.RoleNormal)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// No email or password is needed to promote someone who already exists.
+	if err := runAdmin(cfg, []string{"bootstrap", "-username", "alice"}); err != nil {
+		t.Fatalf("bootstrap: %v", err)
+	}
+
+	check := func(username, wantRole string, wantDisabled bool) {
+		t.Helper()
+
+		var role, passwordHash string
+		var disabled bool
+		err := db.QueryRow(`SELECT role, disabled, password_hash FROM users WHERE username = ?`, username).
+			Scan(&role, &disabled, &passwordHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if role != wantRole || disabled != wantDisabled || passwordHash != "x" {
+			t.Errorf("%s: role %q, disabled %v, password hash %q", username, role, disabled, passwordHash)
+		}
+	}
+	check("alice", models.RoleAdmin, false)
+	check("bob", models.RoleNormal, true)
+
+	var users int
+	db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&users)
+	if users != 2 {
+		t.Errorf("%d users, want 2", users)
+	}
+
+	if err := runAdmin(cfg, []string{"bootstrap", "-username", "carol"}); err == nil {
+		t.Error("creating an admin without an email or password succeeded")
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+)
+
+func runCommand(cfg *config.Config, name string, args []string) error {
+	switch name {
+	case "serve":
+		serve(cfg)
+		return nil
+	case "migrate":
+		return runMigrate(cfg, args)
+	case "admin":
+		return runAdmin(cfg, args)
+	case "import":
+		return runImport(cfg, args)
+	default:
+		return fmt.Errorf("unknown command %q (expected serve, migrate, admin or import)", name)
+	}
+}
+
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: apiodactyl migrate <status|up|down> [flags]")
+	}
+
+	db, err := database.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	switch args[0] {
+	case "status":
+		current, err := migrator.CurrentVersion(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("database version: %d\nlatest version:   %d\n", current, migrator.Latest())
+
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		printMigrations("pending", pending)
+		return nil
+
+	case "up":
+		fs := flag.NewFlagSet("migrate up", flag.ContinueOnError)
+		dryRun := fs.Bool("dry-run", false, "list pending migrations without applying them")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		if *dryRun {
+			pending, err := migrator.Pending(ctx)
+			if err != nil {
+				return err
+			}
+			printMigrations("would apply", pending)
+			return nil
+		}
+
+		applied, err := migrator.Up(ctx)
+		printMigrations("applied", applied)
+		return err
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		rolledBack, err := migrator.Down(ctx, *steps)
+		printMigrations("rolled back", rolledBack)
+		return err
+
+	default:
+		return fmt.Errorf("unknown migrate subcommand %q", args[0])
+	}
+}
+
+func printMigrations(label string, migrations []database.Migration) {
+	if len(migrations) == 0 {
+		fmt.Printf("%s: none\n", label)
+		return
+	}
+
+	fmt.Printf("%s:\n", label)
+	for _, m := range migrations {
+		fmt.Printf("  %04d_%s\n", m.Version, m.Name)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/fetcher"
+	"github.com/thebearodactyl/apiodactyl/internal/handlers"
+	"github.com/thebearodactyl/apiodactyl/internal/importer"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/storage"
+)
+
+// runImport handles `apiodactyl import`, the command-line side of
+// POST /api/v1/import, for exports too large to upload comfortably.
+func runImport(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	sourceName := fs.String("source", "", "export format: "+importer.Names())
+	path := fs.String("file", "", "export file to import")
+	username := fs.String("user", "", "user who will own the entries")
+	defaults := fs.String("defaults", "", "JSON object of fields for rows that leave them empty, or @FILE to read it from a file")
+	dryRun := fs.Bool("dry-run", false, "check every row without importing anything")
+	skipInvalid := fs.Bool("skip-invalid", false, "import the valid rows even when others are invalid")
+	verbose := fs.Bool("v", false, "list every row, not just the ones that aren't imported")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *sourceName == "" || *path == "" || *username == "" {
+		return fmt.Errorf("usage: apiodactyl import -source SOURCE -file FILE -user NAME [-defaults JSON|@FILE] [-dry-run] [-skip-invalid] [-v]")
+	}
+
+	source, ok := importer.Lookup(*sourceName)
+	if !ok {
+		return fmt.Errorf("unknown source %q (expected one of %s)", *sourceName, importer.Names())
+	}
+
+	opts := handlers.ImportOptions{DryRun: *dryRun, SkipInvalid: *skipInvalid}
+	if *defaults != "" {
+		data := []byte(*defaults)
+		if name, ok := strings.CutPrefix(*defaults, "@"); ok {
+			var err error
+			if data, err = os.ReadFile(name); err != nil {
+				return err
+			}
+		}
+		if err := json.Unmarshal(data, &opts.Defaults); err != nil {
+			return fmt.Errorf("invalid -defaults: %w", err)
+		}
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	rows, err := source.Parse(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source.Label, err)
+	}
+
+	db, err := database.InitDB(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	store, err := storage.New(cfg.Storage, cfg.App.FilesDir)
+	if err != nil {
+		return err
+	}
+	pipeline := covers.New(store, fetcher.New(cfg.Fetch), cfg.Storage.PublicBaseURL)
+
+	ctx := context.Background()
+
+	err = db.QueryRowContext(ctx, `SELECT id FROM users WHERE username = ?`, *username).Scan(&opts.UserID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("no user named %s", *username)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up %s: %w", *username, err)
+	}
+
+	response, err := handlers.RunImport(ctx, db, pipeline, source, rows, opts)
+	if errors.Is(err, handlers.ErrInvalidRows) {
+		printImport(response, *verbose)
+		return errors.New(response.Message)
+	}
+	if err != nil {
+		return err
+	}
+
+	printImport(response, *verbose)
+	fmt.Println(response.Message)
+	return nil
+}
+
+func printImport(response *models.ImportResponse, verbose bool) {
+	for _, row := range response.Rows {
+		switch {
+		case row.Result == models.ImportInvalid:
+			fmt.Printf("line %d: %s: invalid: %s\n", row.Line, row.Title, strings.Join(row.Errors, "; "))
+		case row.Result == models.ImportDuplicate && row.DuplicateOf != nil:
+			fmt.Printf("line %d: %s: duplicate of %s %d\n", row.Line, row.Title, response.ItemType, *row.DuplicateOf)
+		case row.Result == models.ImportDuplicate:
+			fmt.Printf("line %d: %s: duplicate of an earlier row\n", row.Line, row.Title)
+		case verbose && row.ID != nil:
+			fmt.Printf("line %d: %s: created %s %d\n", row.Line, row.Title, response.ItemType, *row.ID)
+		case verbose:
+			fmt.Printf("line %d: %s: %s\n", row.Line, row.Title, row.Result)
+		}
+	}
+
+	fmt.Printf("%d rows: %d valid, %d created, %d duplicates, %d invalid\n",
+		response.Total, response.Valid, response.Created, response.Duplicates, response.Invalid)
+}
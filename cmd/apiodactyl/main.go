@@ -12,11 +12,17 @@ import (
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
 	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
 	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/fetcher"
 	"github.com/thebearodactyl/apiodactyl/internal/handlers"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/metadata"
 	"github.com/thebearodactyl/apiodactyl/internal/middleware"
-	"github.com/thebearodactyl/apiodactyl/internal/utils"
+	"github.com/thebearodactyl/apiodactyl/internal/storage"
+	"github.com/thebearodactyl/apiodactyl/internal/trash"
 )
 
 func main() {
@@ -25,6 +31,17 @@ func main() {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	if len(os.Args) > 1 {
+		if err := runCommand(cfg, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("%s: %v", os.Args[1], err)
+		}
+		return
+	}
+
+	serve(cfg)
+}
+
+func serve(cfg *config.Config) {
 	if cfg.IsProduction() {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -35,7 +52,16 @@ func main() {
 	}
 	defer db.Close()
 
-	router := setupRouter(db, cfg)
+	store, err := storage.New(cfg.Storage, cfg.App.FilesDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go trash.Run(purgeCtx, db, cfg.Trash)
+
+	router := setupRouter(db, store, cfg)
 	router.MaxMultipartMemory = 16 << 20
 
 	server := &http.Server{
@@ -67,11 +93,12 @@ func main() {
 	log.Println("Server exited")
 }
 
-func setupRouter(db *database.DB, cfg *config.Config) *gin.Engine {
+func setupRouter(db *database.DB, store storage.Store, cfg *config.Config) *gin.Engine {
 	router := gin.Default()
 
 	router.Use(middleware.RequestLogger())
 	router.Use(gin.Recovery())
+	router.Use(audit.Middleware(db))
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"https://*.bearodactyl.dev", "http://localhost:5173"},
@@ -96,13 +123,26 @@ func setupRouter(db *database.DB, cfg *config.Config) *gin.Engine {
 	router.NoRoute(handlers.NotFound)
 
 	h := handlers.NewHandler(db)
-	authHandler := handlers.NewAuthHandler(db, cfg.JWT.Secret, cfg.JWT.ExpirationHours)
-	gamesHandler := handlers.NewGameHandler(db)
-	booksHandler := handlers.NewBookHandler(db)
+	authHandler := handlers.NewAuthHandler(db, cfg.JWT.Secret, cfg.JWT.AccessTokenTTL(), cfg.JWT.RefreshTokenTTL())
+	coverPipeline := covers.New(store, fetcher.New(cfg.Fetch), cfg.Storage.PublicBaseURL)
+	metadataProviders := metadata.New(cfg.Metadata)
+
 	commentsHandler := handlers.NewCommentHandler(db)
-	routeHandler := handlers.NewRouteHandler()
+	collectionsHandler := handlers.NewCollectionHandler(db)
+	seriesHandler := handlers.NewSeriesHandler(db)
+	usersHandler := handlers.NewUserHandler(db)
+	trashHandler := handlers.NewTrashHandler(db, cfg.Trash.Retention())
+	auditHandler := handlers.NewAuditHandler(db)
+	readingSessionHandler := handlers.NewReadingSessionHandler(db)
+	playSessionHandler := handlers.NewPlaySessionHandler(db)
+	filesHandler := handlers.NewFileHandler(store, coverPipeline)
+	importHandler := handlers.NewImportHandler(db, coverPipeline)
+	exportHandler := handlers.NewExportHandler(db, coverPipeline)
+	metadataHandler := handlers.NewMetadataHandler(metadataProviders)
+	routeHandler := handlers.NewRouteHandler(router, cfg.Storage.PublicBaseURL)
 
-	router.Static("/files", "./files")
+	router.GET("/files/*key", filesHandler.ServeFile)
+	router.HEAD("/files/*key", filesHandler.ServeFile)
 
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -112,22 +152,31 @@ func setupRouter(db *database.DB, cfg *config.Config) *gin.Engine {
 	})
 
 	router.GET("/api/v1/routes", routeHandler.GetAllRoutes)
+	router.GET("/api/v1/openapi.json", routeHandler.GetOpenAPI)
+	router.GET("/api/v1/statuses", handlers.GetStatuses)
 
 	public := router.Group("/api/v1")
 	{
 		public.POST("/auth/register", authHandler.Register)
 		public.POST("/auth/login", authHandler.Login)
+		public.POST("/auth/refresh", authHandler.Refresh)
+		public.POST("/auth/password-reset", authHandler.ResetPassword)
 	}
 
 	protected := router.Group("/api/v1")
-	protected.Use(middleware.JWTAuth(cfg.JWT.Secret))
+	protected.Use(middleware.JWTAuth(cfg.JWT.Secret, db))
 	{
 		protected.GET("/me", authHandler.GetProfile)
-		protected.POST("/upload", middleware.RequireAdmin(), utils.UploadFile)
+		protected.POST("/auth/logout", authHandler.Logout)
+		protected.POST("/auth/logout-all", authHandler.LogoutAll)
+		protected.POST("/upload", middleware.RequireAdmin(), filesHandler.Upload)
+		protected.POST("/import", middleware.RequireAdmin(), importHandler.Import)
+		protected.POST("/import/catalog", middleware.RequireAdmin(), importHandler.ImportCatalog)
+		protected.GET("/export", exportHandler.Export)
 
 		resources := protected.Group("/resources")
 		{
-			resources.GET("/routes", routeHandler.GetResourcesRoutes)
+			resources.GET("/routes", routeHandler.GroupRoutes("resources"))
 			resources.GET("", h.GetResources)
 			resources.GET("/:id", h.GetResource)
 			resources.POST("", middleware.RequireAdmin(), h.CreateResource)
@@ -135,31 +184,122 @@ func setupRouter(db *database.DB, cfg *config.Config) *gin.Engine {
 			resources.DELETE("/:id", middleware.RequireAdmin(), h.DeleteResource)
 		}
 
+		for _, mediaType := range media.Types {
+			mediaHandler := handlers.NewMediaHandler(db, coverPipeline, metadataProviders, mediaType)
+			items := protected.Group("/" + mediaType.Plural)
+			{
+				items.GET("/routes", routeHandler.GroupRoutes(mediaType.Plural))
+				items.GET("", mediaHandler.List)
+				items.GET("/search", mediaHandler.Search)
+				items.GET("/:id", mediaHandler.Get)
+				items.POST("", middleware.RequireAdmin(), mediaHandler.Create)
+				items.PUT("/:id", middleware.RequireAdmin(), mediaHandler.Update)
+				items.DELETE("/:id", middleware.RequireAdmin(), mediaHandler.Delete)
+				items.GET("/:id/revisions", mediaHandler.ListRevisions)
+				items.GET("/:id/revisions/:rev", mediaHandler.GetRevision)
+				items.GET("/:id/diff", mediaHandler.DiffRevisions)
+				items.GET("/:id/status-history", mediaHandler.StatusHistory)
+				items.GET("/:id/related", mediaHandler.Related)
+				items.POST("/:id/related", middleware.RequireAdmin(), mediaHandler.CreateRelation)
+				items.DELETE("/:id/related/:relation", middleware.RequireAdmin(), mediaHandler.DeleteRelation)
+				items.POST("/:id/revisions/:rev/restore", middleware.RequireAdmin(), mediaHandler.RestoreRevision)
+				if len(mediaType.Counters) > 0 {
+					items.POST("/:id/progress", middleware.RequireAdmin(), mediaHandler.UpdateProgress)
+				}
+				if len(metadataProviders.Providers(mediaType.Name)) > 0 {
+					items.POST("/from-metadata", middleware.RequireAdmin(), mediaHandler.CreateFromMetadata)
+				}
+			}
+		}
+
+		for _, taxonomy := range media.Taxonomies {
+			taxonomyHandler := handlers.NewTaxonomyHandler(db, taxonomy)
+			terms := protected.Group("/" + taxonomy.Plural)
+			{
+				terms.GET("/routes", routeHandler.GroupRoutes(taxonomy.Plural))
+				terms.GET("", taxonomyHandler.List)
+				terms.GET("/autocomplete", taxonomyHandler.Autocomplete)
+				terms.POST("/merge", middleware.RequireAdmin(), taxonomyHandler.Merge)
+				terms.PUT("/:id", middleware.RequireAdmin(), taxonomyHandler.Rename)
+				terms.DELETE("/:id", middleware.RequireAdmin(), taxonomyHandler.Delete)
+			}
+		}
+
+		metadataGroup := protected.Group("/metadata")
+		{
+			metadataGroup.GET("/routes", routeHandler.GroupRoutes("metadata"))
+			metadataGroup.GET("/providers", metadataHandler.ListProviders)
+			metadataGroup.GET("/search", metadataHandler.Search)
+		}
+
 		games := protected.Group("/games")
 		{
-			games.GET("/routes", routeHandler.GetGamesRoutes)
-			games.GET("", gamesHandler.GetGames)
-			games.GET("/search", gamesHandler.SearchGames)
-			games.GET("/:id", gamesHandler.GetGame)
-			games.POST("", middleware.RequireAdmin(), gamesHandler.CreateGame)
-			games.PUT("/:id", middleware.RequireAdmin(), gamesHandler.UpdateGame)
-			games.DELETE("/:id", middleware.RequireAdmin(), gamesHandler.DeleteGame)
+			games.GET("/:id/sessions", playSessionHandler.ListSessions)
+			games.POST("/:id/sessions", middleware.RequireAdmin(), playSessionHandler.CreateSession)
+			games.POST("/:id/sessions/start", middleware.RequireAdmin(), playSessionHandler.StartSession)
+			games.POST("/:id/sessions/stop", middleware.RequireAdmin(), playSessionHandler.StopSession)
 		}
 
 		books := protected.Group("/books")
 		{
-			books.GET("/routes", routeHandler.GetBooksRoutes)
-			books.GET("", booksHandler.GetBooks)
-			books.GET("/search", booksHandler.SearchBooks)
-			books.GET("/:id", booksHandler.GetBook)
-			books.POST("", middleware.RequireAdmin(), booksHandler.CreateBook)
-			books.PUT("/:id", middleware.RequireAdmin(), booksHandler.UpdateBook)
-			books.DELETE("/:id", middleware.RequireAdmin(), booksHandler.DeleteBook)
+			books.GET("/:id/sessions", readingSessionHandler.ListSessions)
+			books.POST("/:id/sessions", middleware.RequireAdmin(), readingSessionHandler.CreateSession)
+		}
+
+		trashBin := protected.Group("/trash")
+		{
+			trashBin.GET("/routes", routeHandler.GroupRoutes("trash"))
+			trashBin.GET("", trashHandler.GetTrash)
+			trashBin.DELETE("", middleware.RequireAdmin(), trashHandler.EmptyTrash)
+			trashBin.POST("/:type/:id/restore", middleware.RequireAdmin(), trashHandler.RestoreTrashItem)
+			trashBin.DELETE("/:type/:id", middleware.RequireAdmin(), trashHandler.PurgeTrashItem)
+		}
+
+		admin := protected.Group("/admin", middleware.RequireAdmin())
+		{
+			admin.GET("/users", usersHandler.ListUsers)
+			admin.GET("/users/:id", usersHandler.GetUser)
+			admin.PUT("/users/:id/role", usersHandler.UpdateUserRole)
+			admin.POST("/users/:id/disable", usersHandler.DisableUser)
+			admin.POST("/users/:id/enable", usersHandler.EnableUser)
+			admin.POST("/users/:id/password-reset", usersHandler.ForcePasswordReset)
+			admin.DELETE("/users/:id", usersHandler.DeleteUser)
+			admin.GET("/audit", auditHandler.ListAudit)
+			admin.GET("/audit/export", auditHandler.ExportAudit)
+		}
+
+		series := protected.Group("/series")
+		{
+			series.GET("/routes", routeHandler.GroupRoutes("series"))
+			series.GET("", seriesHandler.ListSeries)
+			series.GET("/:id", seriesHandler.GetSeries)
+			series.POST("", middleware.RequireAdmin(), seriesHandler.CreateSeries)
+			series.PUT("/:id", middleware.RequireAdmin(), seriesHandler.UpdateSeries)
+			series.DELETE("/:id", middleware.RequireAdmin(), seriesHandler.DeleteSeries)
+			series.POST("/:id/entries", middleware.RequireAdmin(), seriesHandler.AddEntry)
+			series.PUT("/:id/entries/:entry", middleware.RequireAdmin(), seriesHandler.UpdateEntry)
+			series.DELETE("/:id/entries/:entry", middleware.RequireAdmin(), seriesHandler.RemoveEntry)
+		}
+
+		collections := protected.Group("/collections")
+		{
+			collections.GET("/routes", routeHandler.GroupRoutes("collections"))
+			collections.GET("", collectionsHandler.ListCollections)
+			collections.GET("/public", collectionsHandler.ListPublicCollections)
+			collections.GET("/shared/:token", collectionsHandler.GetSharedCollection)
+			collections.GET("/:id", collectionsHandler.GetCollection)
+			collections.POST("", collectionsHandler.CreateCollection)
+			collections.PUT("/:id", collectionsHandler.UpdateCollection)
+			collections.DELETE("/:id", collectionsHandler.DeleteCollection)
+			collections.POST("/:id/items", collectionsHandler.AddItems)
+			collections.POST("/:id/items/remove", collectionsHandler.RemoveItems)
+			collections.PUT("/:id/items/order", collectionsHandler.ReorderItems)
+			collections.PUT("/:id/items/:item", collectionsHandler.UpdateItem)
 		}
 
 		comments := protected.Group("/comments")
 		{
-			comments.GET("/routes", routeHandler.GetCommentsRoutes)
+			comments.GET("/routes", routeHandler.GroupRoutes("comments"))
 			comments.POST("", commentsHandler.CreateComment)
 			comments.GET("", commentsHandler.GetComments)
 			comments.PUT("/:id", commentsHandler.UpdateComment)
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/middleware"
+	"github.com/thebearodactyl/apiodactyl/internal/storage"
+)
+
+const testSecret = "test-secret"
+
+// newTestRouter builds the full router with every metadata provider
+// enabled, so every optional route is registered.
+func newTestRouter(t *testing.T) (*gin.Engine, *database.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	db, err := database.InitDB(filepath.Join(dir, "test.db"))
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	store, err := storage.NewLocalStore(filepath.Join(dir, "files"), "/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{
+		JWT:     config.JWTConfig{Secret: testSecret, AccessTokenMinutes: 15, RefreshTokenHours: 1},
+		Storage: config.StorageConfig{Backend: "local", PublicBaseURL: "https://api.example.com"},
+		Trash:   config.TrashConfig{RetentionDays: 30, PurgeIntervalMinutes: 60},
+		Metadata: config.MetadataConfig{
+			TimeoutSeconds: 5,
+			OpenLibrary:    config.OpenLibraryConfig{Enabled: true},
+			IGDB:           config.IGDBConfig{ClientID: "id", ClientSecret: "secret"},
+		},
+	}
+	return setupRouter(db, store, cfg), db
+}
+
+func openAPIDocument(t *testing.T, router *gin.Engine) map[string]any {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/v1/openapi.json: %d", w.Code)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("the document is not JSON: %v", err)
+	}
+	return doc
+}
+
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	router, _ := newTestRouter(t)
+	doc := openAPIDocument(t, router)
+
+	paths, _ := doc["paths"].(map[string]any)
+	tags := map[string]string{}
+	for _, tag := range doc["tags"].([]any) {
+		tag := tag.(map[string]any)
+		tags[tag["name"].(string)], _ = tag["description"].(string)
+	}
+
+	count := 0
+	for _, route := range router.Routes() {
+		name := route.Method + " " + route.Path
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+
+		op, ok := paths[path].(map[string]any)[strings.ToLower(route.Method)].(map[string]any)
+		if !ok {
+			t.Errorf("%s is missing from the document", name)
+			continue
+		}
+		count++
+
+		// Undocumented routes are still listed, but without a summary and
+		// in a group guessed from their path.
+		if summary, _ := op["summary"].(string); summary == "" {
+			t.Errorf("%s has no documentation", name)
+		}
+		for _, tag := range op["tags"].([]any) {
+			if tags[tag.(string)] == "" {
+				t.Errorf("%s is in group %s, which is not registered", name, tag)
+			}
+		}
+	}
+
+	documented := 0
+	for _, methods := range paths {
+		documented += len(methods.(map[string]any))
+	}
+	if documented != count {
+		t.Errorf("the document has %d operations for %d routes", documented, count)
+	}
+}
+
+// TestOpenAPIDocumentIsValid checks the document against the parts of
+// OpenAPI 3.1 the generator uses.
+func TestOpenAPIDocumentIsValid(t *testing.T) {
+	router, _ := newTestRouter(t)
+	doc := openAPIDocument(t, router)
+
+	if doc["openapi"] != "3.1.0" {
+		t.Errorf("openapi = %v, want 3.1.0", doc["openapi"])
+	}
+	info, _ := doc["info"].(map[string]any)
+	if info["title"] == "" || info["version"] == "" {
+		t.Errorf("info = %v", info)
+	}
+	servers, _ := doc["servers"].([]any)
+	if len(servers) != 1 || servers[0].(map[string]any)["url"] != "https://api.example.com" {
+		t.Errorf("servers = %v", servers)
+	}
+
+	components, _ := doc["components"].(map[string]any)
+	schemes, _ := components["securitySchemes"].(map[string]any)
+	tags := map[string]bool{}
+	for _, tag := range doc["tags"].([]any) {
+		name := tag.(map[string]any)["name"].(string)
+		if tags[name] {
+			t.Errorf("tag %s is listed twice", name)
+		}
+		tags[name] = true
+	}
+
+	for name, schema := range components["schemas"].(map[string]any) {
+		checkSchema(t, doc, "#/components/schemas/"+name, schema)
+	}
+
+	operationIDs := map[string]string{}
+	templateParam := regexp.MustCompile(`\{([^}]+)\}`)
+	for path, methods := range doc["paths"].(map[string]any) {
+		if !strings.HasPrefix(path, "/") {
+			t.Errorf("path %s does not start with /", path)
+		}
+		var inPath []string
+		for _, match := range templateParam.FindAllStringSubmatch(path, -1) {
+			inPath = append(inPath, match[1])
+		}
+
+		for method, op := range methods.(map[string]any) {
+			name := strings.ToUpper(method) + " " + path
+			if !slices.Contains([]string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}, method) {
+				t.Errorf("%s: unknown method", name)
+			}
+			op := op.(map[string]any)
+
+			id, _ := op["operationId"].(string)
+			if id == "" {
+				t.Errorf("%s has no operationId", name)
+			} else if other, ok := operationIDs[id]; ok {
+				t.Errorf("%s and %s share operationId %s", name, other, id)
+			}
+			operationIDs[id] = name
+
+			for _, tag := range op["tags"].([]any) {
+				if !tags[tag.(string)] {
+					t.Errorf("%s uses tag %s, which is not listed", name, tag)
+				}
+			}
+
+			var declared []string
+			seen := map[string]bool{}
+			params, _ := op["parameters"].([]any)
+			for _, p := range params {
+				p := p.(map[string]any)
+				paramName, _ := p["name"].(string)
+				in, _ := p["in"].(string)
+				if seen[in+" "+paramName] {
+					t.Errorf("%s declares %s parameter %s twice", name, in, paramName)
+				}
+				seen[in+" "+paramName] = true
+				if !slices.Contains([]string{"query", "header", "path", "cookie"}, in) {
+					t.Errorf("%s: parameter %s is in %q", name, paramName, in)
+				}
+				if in == "path" {
+					declared = append(declared, paramName)
+					if p["required"] != true {
+						t.Errorf("%s: path parameter %s is not required", name, paramName)
+					}
+				}
+				checkSchema(t, doc, name+" parameter "+paramName, p["schema"])
+			}
+			slices.Sort(inPath)
+			slices.Sort(declared)
+			if !slices.Equal(inPath, declared) {
+				t.Errorf("%s declares path parameters %v, want %v", name, declared, inPath)
+			}
+
+			if body, ok := op["requestBody"].(map[string]any); ok {
+				checkContent(t, doc, name+" request", body["content"])
+			}
+
+			responses, _ := op["responses"].(map[string]any)
+			if len(responses) == 0 {
+				t.Errorf("%s has no responses", name)
+			}
+			for status, response := range responses {
+				if code, err := strconv.Atoi(status); err != nil || code < 100 || code > 599 {
+					t.Errorf("%s: response status %s", name, status)
+				}
+				response := response.(map[string]any)
+				if description, _ := response["description"].(string); description == "" {
+					t.Errorf("%s: response %s has no description", name, status)
+				}
+				if content, ok := response["content"]; ok {
+					checkContent(t, doc, name+" response "+status, content)
+				}
+			}
+
+			security, _ := op["security"].([]any)
+			for _, requirement := range security {
+				for scheme := range requirement.(map[string]any) {
+					if schemes[scheme] == nil {
+						t.Errorf("%s uses security scheme %s, which is not defined", name, scheme)
+					}
+				}
+			}
+		}
+	}
+}
+
+func checkContent(t *testing.T, doc map[string]any, name string, content any) {
+	t.Helper()
+
+	types, ok := content.(map[string]any)
+	if !ok || len(types) == 0 {
+		t.Errorf("%s has no content", name)
+		return
+	}
+	for mediaType, value := range types {
+		if !strings.Contains(mediaType, "/") {
+			t.Errorf("%s: media type %s", name, mediaType)
+		}
+		if schema, ok := value.(map[string]any)["schema"]; ok {
+			checkSchema(t, doc, name+" "+mediaType, schema)
+		}
+	}
+}
+
+// checkSchema checks a JSON Schema 2020-12 schema, following its
+// subschemas and making sure references resolve.
+func checkSchema(t *testing.T, doc map[string]any, name string, value any) {
+	t.Helper()
+
+	schema, ok := value.(map[string]any)
+	if !ok {
+		t.Errorf("%s: schema is %T", name, value)
+		return
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		if resolve(doc, ref) == nil {
+			t.Errorf("%s: $ref %s does not resolve", name, ref)
+		}
+	}
+
+	validTypes := []string{"null", "boolean", "object", "array", "number", "string", "integer"}
+	switch typ := schema["type"].(type) {
+	case nil:
+	case string:
+		if !slices.Contains(validTypes, typ) {
+			t.Errorf("%s: type %s", name, typ)
+		}
+	case []any:
+		for _, typ := range typ {
+			if s, _ := typ.(string); !slices.Contains(validTypes, s) {
+				t.Errorf("%s: type %v", name, typ)
+			}
+		}
+	default:
+		t.Errorf("%s: type is %T", name, typ)
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	for property, subschema := range properties {
+		checkSchema(t, doc, name+"."+property, subschema)
+	}
+	if required, ok := schema["required"].([]any); ok {
+		for _, property := range required {
+			if _, ok := properties[property.(string)]; !ok {
+				t.Errorf("%s requires %s, which it does not define", name, property)
+			}
+		}
+	}
+	for _, key := range []string{"items", "additionalProperties"} {
+		if subschema, ok := schema[key]; ok {
+			checkSchema(t, doc, name+"."+key, subschema)
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		for i, subschema := range anyOf {
+			checkSchema(t, doc, fmt.Sprintf("%s.anyOf[%d]", name, i), subschema)
+		}
+	}
+	if enum, ok := schema["enum"]; ok {
+		if values, _ := enum.([]any); len(values) == 0 {
+			t.Errorf("%s: empty enum", name)
+		}
+	}
+	for _, bounds := range [][2]string{{"minimum", "maximum"}, {"minLength", "maxLength"}, {"minItems", "maxItems"}} {
+		low, hasLow := schema[bounds[0]].(float64)
+		high, hasHigh := schema[bounds[1]].(float64)
+		if hasLow && hasHigh && low > high {
+			t.Errorf("%s: %s %v is above %s %v", name, bounds[0], low, bounds[1], high)
+		}
+	}
+}
+
+// resolve follows a local JSON pointer such as #/components/schemas/Game.
+func resolve(doc map[string]any, ref string) any {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return nil
+	}
+
+	var node any = doc
+	for _, segment := range strings.Split(pointer, "/") {
+		segment = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil
+		}
+		node = object[segment]
+	}
+	return node
+}
+
+// TestDocumentedAccess checks the access each operation documents against
+// what the router does with a request at that level.
+func TestDocumentedAccess(t *testing.T) {
+	router, db := newTestRouter(t)
+	doc := openAPIDocument(t, router)
+
+	for _, stmt := range []string{
+		`INSERT INTO users (username, email, password_hash, role) VALUES ('normal', 'normal@example.com', 'x', 'normal')`,
+		`INSERT INTO sessions (id, user_id, expires_at) VALUES ('session', 1, datetime('now', '+1 hour'))`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	token, _, err := middleware.GenerateToken(1, "normal", "normal", "session", testSecret, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, methods := range doc["paths"].(map[string]any) {
+		target := regexp.MustCompile(`\{[^}]+\}`).ReplaceAllString(path, "1")
+		for method, op := range methods.(map[string]any) {
+			op := op.(map[string]any)
+			name := strings.ToUpper(method) + " " + path
+			_, protected := op["security"]
+			admin := op["x-required-role"] == "admin"
+
+			send := func(authorization string) int {
+				req := httptest.NewRequest(strings.ToUpper(method), target, strings.NewReader("{}"))
+				req.Header.Set("Content-Type", "application/json")
+				if authorization != "" {
+					req.Header.Set("Authorization", authorization)
+				}
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+				return w.Code
+			}
+
+			if code := send(""); (code == http.StatusUnauthorized) != protected {
+				t.Errorf("%s without a token: %d, documented as protected: %v", name, code, protected)
+			}
+			if !protected {
+				continue
+			}
+			// The logout routes revoke the session, so it is revived for
+			// every request.
+			if _, err := db.Exec(`UPDATE sessions SET revoked_at = NULL`); err != nil {
+				t.Fatal(err)
+			}
+			if code := send("Bearer " + token); (code == http.StatusForbidden) != admin {
+				t.Errorf("%s as a normal user: %d, documented as admin only: %v", name, code, admin)
+			}
+		}
+	}
+}
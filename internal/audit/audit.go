@@ -0,0 +1,152 @@
+// Package audit records every mutating API call in the audit_log table.
+// The middleware writes one entry per request once the handler has run;
+// handlers enrich it with the entity they touched and, for catalog entries,
+// its state before and after the change.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+)
+
+const entryKey = "audit_entry"
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+	ActionImport  = "import"
+	ActionLogin   = "login"
+	ActionLogout  = "logout"
+	ActionRefresh = "refresh"
+
+	ActionPasswordReset = "password_reset"
+)
+
+type entry struct {
+	action     string
+	entityType string
+	entityID   string
+	before     any
+	after      any
+}
+
+// Middleware audits POST, PUT, PATCH and DELETE requests to registered
+// routes, whatever their outcome. Register it before authentication so
+// rejected requests are recorded too.
+func Middleware(db *database.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		action := defaultAction(c.Request.Method)
+		if action == "" || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		e := &entry{action: action}
+		c.Set(entryKey, e)
+
+		c.Next()
+
+		if err := write(db, c, e); err != nil {
+			log.Printf("Failed to write audit entry for %s %s: %v", c.Request.Method, c.FullPath(), err)
+		}
+	}
+}
+
+// Target names the entity a request acted on.
+func Target(c *gin.Context, entityType string, id any) {
+	if e := current(c); e != nil {
+		e.entityType = entityType
+		e.entityID = fmt.Sprint(id)
+	}
+}
+
+// Action overrides the action derived from the HTTP method, for requests
+// such as login or restore that are not plain creates, updates or deletes.
+func Action(c *gin.Context, action string) {
+	if e := current(c); e != nil {
+		e.action = action
+	}
+}
+
+// Change attaches the entity's state before and after the request. Either
+// side may be nil.
+func Change(c *gin.Context, before, after any) {
+	if e := current(c); e != nil {
+		e.before = before
+		e.after = after
+	}
+}
+
+func current(c *gin.Context) *entry {
+	value, ok := c.Get(entryKey)
+	if !ok {
+		return nil
+	}
+	e, _ := value.(*entry)
+	return e
+}
+
+func defaultAction(method string) string {
+	switch method {
+	case http.MethodPost:
+		return ActionCreate
+	case http.MethodPut, http.MethodPatch:
+		return ActionUpdate
+	case http.MethodDelete:
+		return ActionDelete
+	}
+	return ""
+}
+
+func write(db *database.DB, c *gin.Context, e *entry) error {
+	before, err := encode(e.before)
+	if err != nil {
+		return err
+	}
+	after, err := encode(e.after)
+	if err != nil {
+		return err
+	}
+
+	// The user_id, username and user_role keys are set by JWTAuth and are
+	// absent on public routes.
+	actorID, _ := c.Get("user_id")
+	actorName, _ := c.Get("username")
+	actorRole, _ := c.Get("user_role")
+
+	// The request context is done once the response is written.
+	_, err = db.ExecContext(context.Background(), `
+		INSERT INTO audit_log (actor_id, actor_username, actor_role, ip, method, route, path,
+		                       action, entity_type, entity_id, before, after, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, actorID, nullable(actorName), nullable(actorRole), c.ClientIP(), c.Request.Method, c.FullPath(),
+		c.Request.URL.Path, e.action, nullable(e.entityType), nullable(e.entityID), before, after, c.Writer.Status())
+	return err
+}
+
+func encode(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func nullable(v any) any {
+	if s, ok := v.(string); ok && s != "" {
+		return s
+	}
+	return nil
+}
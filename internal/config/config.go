@@ -2,8 +2,10 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -12,6 +14,10 @@ type Config struct {
 	App      AppConfig
 	JWT      JWTConfig
 	Database DatabaseConfig
+	Storage  StorageConfig
+	Fetch    FetchConfig
+	Metadata MetadataConfig
+	Trash    TrashConfig
 	Logging  LoggingConfig
 }
 
@@ -22,14 +28,69 @@ type AppConfig struct {
 }
 
 type JWTConfig struct {
-	Secret          string
-	ExpirationHours int
+	Secret             string
+	AccessTokenMinutes int
+	RefreshTokenHours  int
 }
 
 type DatabaseConfig struct {
 	Path string
 }
 
+type StorageConfig struct {
+	Backend       string
+	PublicBaseURL string
+	S3            S3Config
+}
+
+type S3Config struct {
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+	PublicURL string
+}
+
+// FetchConfig limits server-side downloads of remote URLs such as
+// cover_image_url.
+type FetchConfig struct {
+	MaxBytes       int64
+	TimeoutSeconds int
+	AllowPrivate   bool
+}
+
+// MetadataConfig configures the external metadata providers. Open Library
+// needs no account and is on by default; IGDB is only enabled once it has a
+// Twitch client ID and secret. The URLs can point at local stand-ins.
+type MetadataConfig struct {
+	TimeoutSeconds int
+	OpenLibrary    OpenLibraryConfig
+	IGDB           IGDBConfig
+}
+
+type OpenLibraryConfig struct {
+	Enabled   bool
+	URL       string
+	CoversURL string
+}
+
+type IGDBConfig struct {
+	ClientID     string
+	ClientSecret string
+	URL          string
+	TokenURL     string
+	ImagesURL    string
+}
+
+// TrashConfig controls how long soft-deleted entries are kept before the
+// background purge removes them for good.
+type TrashConfig struct {
+	RetentionDays        int
+	PurgeIntervalMinutes int
+}
+
 type LoggingConfig struct {
 	Level string
 }
@@ -44,12 +105,50 @@ func Load() (*Config, error) {
 			FilesDir:    getEnv("FILES_DIR", "./files"),
 		},
 		JWT: JWTConfig{
-			Secret:          getEnv("JWT_SECRET", ""),
-			ExpirationHours: getEnvAsInt("JWT_EXPIRATION_HOURS", 24),
+			Secret:             getEnv("JWT_SECRET", ""),
+			AccessTokenMinutes: accessTokenMinutes(),
+			RefreshTokenHours:  getEnvAsInt("JWT_REFRESH_TOKEN_HOURS", 720),
 		},
 		Database: DatabaseConfig{
 			Path: getEnv("DB_PATH", "./data.db"),
 		},
+		Storage: StorageConfig{
+			Backend:       getEnv("STORAGE_BACKEND", "local"),
+			PublicBaseURL: getEnv("PUBLIC_BASE_URL", "http://localhost:"+getEnv("PORT", "8080")),
+			S3: S3Config{
+				Endpoint:  getEnv("S3_ENDPOINT", ""),
+				Bucket:    getEnv("S3_BUCKET", ""),
+				Region:    getEnv("S3_REGION", "us-east-1"),
+				AccessKey: getEnv("S3_ACCESS_KEY", ""),
+				SecretKey: getEnv("S3_SECRET_KEY", ""),
+				PathStyle: getEnvAsBool("S3_PATH_STYLE", true),
+				PublicURL: getEnv("S3_PUBLIC_URL", ""),
+			},
+		},
+		Fetch: FetchConfig{
+			MaxBytes:       int64(getEnvAsInt("FETCH_MAX_BYTES", 10<<20)),
+			TimeoutSeconds: getEnvAsInt("FETCH_TIMEOUT_SECONDS", 15),
+			AllowPrivate:   getEnvAsBool("FETCH_ALLOW_PRIVATE", false),
+		},
+		Metadata: MetadataConfig{
+			TimeoutSeconds: getEnvAsInt("METADATA_TIMEOUT_SECONDS", 10),
+			OpenLibrary: OpenLibraryConfig{
+				Enabled:   getEnvAsBool("OPENLIBRARY_ENABLED", true),
+				URL:       getEnv("OPENLIBRARY_URL", "https://openlibrary.org"),
+				CoversURL: getEnv("OPENLIBRARY_COVERS_URL", "https://covers.openlibrary.org"),
+			},
+			IGDB: IGDBConfig{
+				ClientID:     getEnv("IGDB_CLIENT_ID", ""),
+				ClientSecret: getEnv("IGDB_CLIENT_SECRET", ""),
+				URL:          getEnv("IGDB_URL", "https://api.igdb.com/v4"),
+				TokenURL:     getEnv("IGDB_TOKEN_URL", "https://id.twitch.tv/oauth2/token"),
+				ImagesURL:    getEnv("IGDB_IMAGES_URL", "https://images.igdb.com/igdb/image/upload"),
+			},
+		},
+		Trash: TrashConfig{
+			RetentionDays:        getEnvAsInt("TRASH_RETENTION_DAYS", 30),
+			PurgeIntervalMinutes: getEnvAsInt("TRASH_PURGE_INTERVAL_MINUTES", 60),
+		},
 		Logging: LoggingConfig{
 			Level: getEnv("LOG_LEVEL", "info"),
 		},
@@ -71,6 +170,37 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("JWT_SECRET must be at least 32 characters long")
 	}
 
+	if c.JWT.AccessTokenMinutes <= 0 || c.JWT.RefreshTokenHours <= 0 {
+		return fmt.Errorf("JWT_ACCESS_TOKEN_MINUTES and JWT_REFRESH_TOKEN_HOURS must be positive")
+	}
+
+	if c.Fetch.MaxBytes <= 0 || c.Fetch.TimeoutSeconds <= 0 {
+		return fmt.Errorf("FETCH_MAX_BYTES and FETCH_TIMEOUT_SECONDS must be positive")
+	}
+
+	if c.Metadata.TimeoutSeconds <= 0 {
+		return fmt.Errorf("METADATA_TIMEOUT_SECONDS must be positive")
+	}
+
+	if (c.Metadata.IGDB.ClientID == "") != (c.Metadata.IGDB.ClientSecret == "") {
+		return fmt.Errorf("IGDB_CLIENT_ID and IGDB_CLIENT_SECRET must be set together")
+	}
+
+	if c.Trash.RetentionDays <= 0 || c.Trash.PurgeIntervalMinutes <= 0 {
+		return fmt.Errorf("TRASH_RETENTION_DAYS and TRASH_PURGE_INTERVAL_MINUTES must be positive")
+	}
+
+	switch c.Storage.Backend {
+	case "local":
+	case "s3":
+		if c.Storage.S3.Endpoint == "" || c.Storage.S3.Bucket == "" {
+			return fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required when STORAGE_BACKEND=s3")
+		}
+		return nil
+	default:
+		return fmt.Errorf("STORAGE_BACKEND must be local or s3, got %q", c.Storage.Backend)
+	}
+
 	filesDir, err := os.Open(c.App.FilesDir)
 	if err != nil {
 		return fmt.Errorf("%v does not exist: %w", c.App.FilesDir, err)
@@ -88,6 +218,30 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+func (c *JWTConfig) AccessTokenTTL() time.Duration {
+	return time.Duration(c.AccessTokenMinutes) * time.Minute
+}
+
+func (c *JWTConfig) RefreshTokenTTL() time.Duration {
+	return time.Duration(c.RefreshTokenHours) * time.Hour
+}
+
+func (c *FetchConfig) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+func (c *MetadataConfig) Timeout() time.Duration {
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+func (c *TrashConfig) Retention() time.Duration {
+	return time.Duration(c.RetentionDays) * 24 * time.Hour
+}
+
+func (c *TrashConfig) PurgeInterval() time.Duration {
+	return time.Duration(c.PurgeIntervalMinutes) * time.Minute
+}
+
 func (c *Config) IsDevelopment() bool {
 	return c.App.Environment == "development"
 }
@@ -96,6 +250,17 @@ func (c *Config) IsProduction() bool {
 	return c.App.Environment == "production"
 }
 
+// accessTokenMinutes reads JWT_ACCESS_TOKEN_MINUTES, falling back to the
+// token lifetime older deployments set with JWT_EXPIRATION_HOURS.
+func accessTokenMinutes() int {
+	if os.Getenv("JWT_ACCESS_TOKEN_MINUTES") == "" && os.Getenv("JWT_EXPIRATION_HOURS") != "" {
+		hours := getEnvAsInt("JWT_EXPIRATION_HOURS", 0)
+		log.Printf("JWT_EXPIRATION_HOURS is deprecated: set JWT_ACCESS_TOKEN_MINUTES (and JWT_REFRESH_TOKEN_HOURS) instead; using %d minutes for access tokens", hours*60)
+		return hours * 60
+	}
+	return getEnvAsInt("JWT_ACCESS_TOKEN_MINUTES", 15)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -116,3 +281,17 @@ func getEnvAsInt(key string, defaultValue int) int {
 
 	return value
 }
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
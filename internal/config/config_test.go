@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAccessTokenMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		minutes string
+		hours   string
+		want    int
+	}{
+		{name: "default", want: 15},
+		{name: "minutes", minutes: "30", want: 30},
+		{name: "deprecated hours", hours: "2", want: 120},
+		{name: "minutes win over hours", minutes: "5", hours: "24", want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("JWT_ACCESS_TOKEN_MINUTES", tt.minutes)
+			t.Setenv("JWT_EXPIRATION_HOURS", tt.hours)
+
+			got := accessTokenMinutes()
+			if got != tt.want {
+				t.Errorf("accessTokenMinutes() = %d, want %d", got, tt.want)
+			}
+
+			jwt := JWTConfig{AccessTokenMinutes: got, RefreshTokenHours: 720}
+			if ttl := jwt.AccessTokenTTL(); ttl != time.Duration(tt.want)*time.Minute {
+				t.Errorf("AccessTokenTTL() = %v", ttl)
+			}
+			if ttl := jwt.RefreshTokenTTL(); ttl != 30*24*time.Hour {
+				t.Errorf("RefreshTokenTTL() = %v", ttl)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package covers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"log"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/thebearodactyl/apiodactyl/internal/fetcher"
+	"github.com/thebearodactyl/apiodactyl/internal/imaging"
+	"github.com/thebearodactyl/apiodactyl/internal/storage"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// resizableExtensions are the originals imaging can decode.
+var resizableExtensions = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+}
+
+// paletteSize is how many colours are extracted from each cover.
+const paletteSize = 5
+
+// Cover is a stored cover image along with the colour information derived
+// from it. Palette and BlurHash are empty for covers we could not decode.
+type Cover struct {
+	URL      string
+	Palette  []string
+	BlurHash string
+}
+
+// Color returns the cover's dominant colour, or "" if it is unknown.
+func (c *Cover) Color() string {
+	if len(c.Palette) == 0 {
+		return ""
+	}
+	return c.Palette[0]
+}
+
+// Pipeline stores cover images and manages their resized variants.
+type Pipeline struct {
+	store   storage.Store
+	fetcher *fetcher.Fetcher
+	baseURL string
+}
+
+func New(store storage.Store, fetcher *fetcher.Fetcher, publicBaseURL string) *Pipeline {
+	return &Pipeline{
+		store:   store,
+		fetcher: fetcher,
+		baseURL: strings.TrimRight(publicBaseURL, "/"),
+	}
+}
+
+// Save stores a cover under its content hash, pre-generates the default
+// variants and extracts its palette and BlurHash.
+func (p *Pipeline) Save(ctx context.Context, r io.ReadSeeker, size int64, ext string, contentType string) (*Cover, error) {
+	key, existed, err := storage.PutContentAddressed(ctx, p.store, r, size, ext, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	cover := &Cover{URL: p.store.URL(key), Palette: []string{}}
+	if !resizableExtensions[strings.ToLower(ext)] {
+		return cover, nil
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	img, err := imaging.Decode(r)
+	if err != nil {
+		log.Printf("failed to decode cover %s: %v", key, err)
+		return cover, nil
+	}
+
+	if !existed {
+		p.generateVariants(ctx, key, img)
+	}
+
+	analysis := imaging.Analyze(img, paletteSize)
+	cover.Palette = analysis.Palette
+	cover.BlurHash = analysis.BlurHash
+
+	return cover, nil
+}
+
+// Describe analyzes a cover referenced by URL. Only covers held in our own
+// store are analyzed; anything else comes back with just its URL.
+func (p *Pipeline) Describe(ctx context.Context, coverURL string) *Cover {
+	cover := &Cover{URL: coverURL, Palette: []string{}}
+
+	key, ok := p.Key(coverURL)
+	if !ok || !resizableExtensions[strings.ToLower(path.Ext(key))] {
+		return cover
+	}
+
+	body, _, err := p.store.Get(ctx, key)
+	if err != nil {
+		log.Printf("failed to read cover %s: %v", key, err)
+		return cover
+	}
+	defer body.Close()
+
+	img, err := imaging.Decode(body)
+	if err != nil {
+		log.Printf("failed to decode cover %s: %v", key, err)
+		return cover
+	}
+
+	analysis := imaging.Analyze(img, paletteSize)
+	cover.Palette = analysis.Palette
+	cover.BlurHash = analysis.BlurHash
+
+	return cover
+}
+
+func (p *Pipeline) SaveUpload(ctx context.Context, fileHeader *multipart.FileHeader) (*Cover, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("open error: %v", err)
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	return p.Save(ctx, file, fileHeader.Size, ext, utils.AllowedMimeTypes[ext])
+}
+
+// SaveFromURL downloads a remote cover through the SSRF-safe fetcher and
+// saves it. Download failures are returned as *fetcher.Error.
+func (p *Pipeline) SaveFromURL(ctx context.Context, url string) (*Cover, error) {
+	result, err := p.fetcher.Fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.Save(ctx, bytes.NewReader(result.Body), int64(len(result.Body)), result.Ext, result.ContentType)
+}
+
+// Key returns the storage key behind a cover URL we issued, or false for
+// covers hosted elsewhere.
+func (p *Pipeline) Key(coverURL string) (string, bool) {
+	prefix := p.store.URL("")
+	if !strings.HasPrefix(coverURL, prefix) {
+		return "", false
+	}
+
+	key := strings.TrimPrefix(coverURL, prefix)
+	if key == "" || strings.Contains(key, "/") {
+		return "", false
+	}
+
+	return key, true
+}
+
+// Open reads the original file behind a cover URL we issued. Covers hosted
+// elsewhere are reported as storage.ErrNotFound.
+func (p *Pipeline) Open(ctx context.Context, coverURL string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	key, ok := p.Key(coverURL)
+	if !ok {
+		return nil, nil, storage.ErrNotFound
+	}
+	return p.store.Get(ctx, key)
+}
+
+// Variants maps each allowed width to a resize-endpoint URL for coverURL.
+// It returns nil for external or non-image covers.
+func (p *Pipeline) Variants(coverURL string) map[string]string {
+	key, ok := p.Key(coverURL)
+	if !ok || !resizableExtensions[strings.ToLower(path.Ext(key))] {
+		return nil
+	}
+
+	variants := make(map[string]string, len(imaging.VariantWidths))
+	for _, width := range imaging.VariantWidths {
+		variants[strconv.Itoa(width)] = fmt.Sprintf("%s/files/%s?w=%d", p.baseURL, key, width)
+	}
+	return variants
+}
+
+// Variant returns key resized to width in format, generating and caching it
+// in the store on first request. format must already be resolved through
+// imaging.ResolveFormat.
+func (p *Pipeline) Variant(ctx context.Context, key string, width int, format string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	variantKey := variantKey(key, width, format)
+
+	body, info, err := p.store.Get(ctx, variantKey)
+	if err == nil {
+		return body, info, nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, err
+	}
+
+	data, err := p.render(ctx, key, width, format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := p.store.Put(ctx, variantKey, bytes.NewReader(data), int64(len(data)), imaging.ContentType(format)); err != nil {
+		log.Printf("failed to cache variant %s: %v", variantKey, err)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), &storage.ObjectInfo{
+		Key:         variantKey,
+		Size:        int64(len(data)),
+		ContentType: imaging.ContentType(format),
+	}, nil
+}
+
+func (p *Pipeline) render(ctx context.Context, key string, width int, format string) ([]byte, error) {
+	original, _, err := p.store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer original.Close()
+
+	return imaging.Variant(original, width, format)
+}
+
+// generateVariants renders every allowed width in the default format. Failures
+// are only logged: the resize endpoint will retry lazily.
+func (p *Pipeline) generateVariants(ctx context.Context, key string, img image.Image) {
+	for _, width := range imaging.VariantWidths {
+		data, err := imaging.Thumbnail(img, width, imaging.DefaultFormat)
+		if err == nil {
+			err = p.store.Put(ctx, variantKey(key, width, imaging.DefaultFormat), bytes.NewReader(data), int64(len(data)), imaging.ContentType(imaging.DefaultFormat))
+		}
+		if err != nil {
+			log.Printf("failed to generate %dpx variant of %s: %v", width, key, err)
+			return
+		}
+	}
+}
+
+func variantKey(key string, width int, format string) string {
+	return fmt.Sprintf("variants/%s/%d.%s", key, width, format)
+}
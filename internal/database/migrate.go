@@ -0,0 +1,255 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// ErrSchemaTooNew is returned when the database has been migrated past the
+// newest migration embedded in this binary.
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary")
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Migrator struct {
+	db         *DB
+	migrations []Migration
+}
+
+func NewMigrator(db *DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// loadMigrations reads NNNN_name.up.sql / NNNN_name.down.sql pairs and returns
+// them ordered by version.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		filename := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(filename, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(filename, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s must end in .up.sql or .down.sql", filename)
+		}
+
+		base := strings.TrimSuffix(filename, "."+direction+".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s must be named NNNN_name.%s.sql", filename, direction)
+		}
+
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("migration %s has an invalid version", filename)
+		}
+
+		contents, err := fs.ReadFile(fsys, path.Join("migrations", filename))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", filename, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, m.Name, name)
+		}
+
+		if direction == "up" {
+			m.Up = string(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest returns the version of the newest embedded migration.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	return err
+}
+
+// CurrentVersion returns the highest applied migration version, or 0 for a
+// fresh database.
+func (m *Migrator) CurrentVersion(ctx context.Context) (int, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var version int
+	err := m.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// Pending lists the migrations Up would apply, without applying them.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	current, err := m.CurrentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if current > m.Latest() {
+		return nil, fmt.Errorf("%w: database is at version %d, binary supports up to %d", ErrSchemaTooNew, current, m.Latest())
+	}
+
+	pending := []Migration{}
+	for _, migration := range m.migrations {
+		if migration.Version > current {
+			pending = append(pending, migration)
+		}
+	}
+
+	return pending, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []Migration{}
+	for _, migration := range pending {
+		err := m.apply(ctx, migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (?, ?)`, migration.Version, migration.Name)
+			return err
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		applied = append(applied, migration)
+	}
+
+	return applied, nil
+}
+
+// Down rolls back the newest steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	current, err := m.CurrentVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if current > m.Latest() {
+		return nil, fmt.Errorf("%w: database is at version %d, binary supports up to %d", ErrSchemaTooNew, current, m.Latest())
+	}
+
+	rolledBack := []Migration{}
+	for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+		migration := m.migrations[i]
+		if migration.Version > current {
+			continue
+		}
+
+		err := m.apply(ctx, migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, migration.Version)
+			return err
+		})
+		if err != nil {
+			return rolledBack, fmt.Errorf("rollback of %04d_%s failed: %w", migration.Version, migration.Name, err)
+		}
+		rolledBack = append(rolledBack, migration)
+	}
+
+	return rolledBack, nil
+}
+
+// apply runs script and record in a single transaction. Foreign keys are
+// switched off for the duration so migrations can rebuild tables, and are
+// verified with foreign_key_check before committing.
+func (m *Migrator) apply(ctx context.Context, script string, record func(tx *sql.Tx) error) error {
+	if _, err := m.db.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return err
+	}
+	defer m.db.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+
+	if err := record(tx); err != nil {
+		return err
+	}
+
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return err
+	}
+	violation := rows.Next()
+	rows.Close()
+	if violation {
+		return fmt.Errorf("migration left foreign key violations")
+	}
+
+	return tx.Commit()
+}
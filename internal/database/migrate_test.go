@@ -0,0 +1,268 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    fstest.MapFS
+		versions []int
+		err      string
+	}{
+		{
+			name: "ordered by version",
+			files: fstest.MapFS{
+				"migrations/0002_second.up.sql":   {Data: []byte("SELECT 2;")},
+				"migrations/0002_second.down.sql": {Data: []byte("SELECT 2;")},
+				"migrations/0001_first.up.sql":    {Data: []byte("SELECT 1;")},
+				"migrations/0001_first.down.sql":  {Data: []byte("SELECT 1;")},
+			},
+			versions: []int{1, 2},
+		},
+		{
+			name: "missing down",
+			files: fstest.MapFS{
+				"migrations/0001_first.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			err: "needs both an up and a down file",
+		},
+		{
+			name: "wrong extension",
+			files: fstest.MapFS{
+				"migrations/0001_first.sql": {Data: []byte("SELECT 1;")},
+			},
+			err: "must end in .up.sql or .down.sql",
+		},
+		{
+			name: "no name",
+			files: fstest.MapFS{
+				"migrations/0001.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			err: "must be named NNNN_name.up.sql",
+		},
+		{
+			name: "invalid version",
+			files: fstest.MapFS{
+				"migrations/0000_zero.up.sql": {Data: []byte("SELECT 1;")},
+			},
+			err: "has an invalid version",
+		},
+		{
+			name: "version used twice",
+			files: fstest.MapFS{
+				"migrations/0001_first.up.sql":   {Data: []byte("SELECT 1;")},
+				"migrations/0001_other.down.sql": {Data: []byte("SELECT 1;")},
+			},
+			err: "is used by both",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.files)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadMigrations: %v", err)
+			}
+
+			versions := make([]int, len(migrations))
+			for i, m := range migrations {
+				versions[i] = m.Version
+			}
+			if !slices.Equal(versions, tt.versions) {
+				t.Errorf("versions = %v, want %v", versions, tt.versions)
+			}
+		})
+	}
+}
+
+func TestMigratorUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+
+	// Pending is the dry run: it lists everything and applies nothing.
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != len(migrator.migrations) {
+		t.Fatalf("pending = %d migrations, want %d", len(pending), len(migrator.migrations))
+	}
+	if version, _ := migrator.CurrentVersion(ctx); version != 0 {
+		t.Fatalf("version after dry run = %d, want 0", version)
+	}
+
+	applied, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(applied) != len(migrator.migrations) {
+		t.Errorf("applied %d migrations, want %d", len(applied), len(migrator.migrations))
+	}
+	if version, _ := migrator.CurrentVersion(ctx); version != migrator.Latest() {
+		t.Errorf("version = %d, want %d", version, migrator.Latest())
+	}
+
+	if again, err := migrator.Up(ctx); err != nil || len(again) != 0 {
+		t.Errorf("second Up = %d migrations, %v; want none", len(again), err)
+	}
+
+	// Every down script has to undo its up script cleanly enough for the up
+	// to run again.
+	rolledBack, err := migrator.Down(ctx, len(migrator.migrations))
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if len(rolledBack) != len(migrator.migrations) {
+		t.Errorf("rolled back %d migrations, want %d", len(rolledBack), len(migrator.migrations))
+	}
+	if version, _ := migrator.CurrentVersion(ctx); version != 0 {
+		t.Errorf("version after Down = %d, want 0", version)
+	}
+
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+}
+
+func TestMigratorDownSteps(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	rolledBack, err := migrator.Down(ctx, 2)
+	if err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if len(rolledBack) != 2 || rolledBack[0].Version != migrator.Latest() {
+		t.Fatalf("rolled back %v, want the newest two", rolledBack)
+	}
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Errorf("pending = %d migrations, want 2", len(pending))
+	}
+}
+
+func TestMigratorForeignKeyCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		up   string
+		err  string
+	}{
+		{
+			name: "consistent",
+			up: `CREATE TABLE parents (id INTEGER PRIMARY KEY);
+				CREATE TABLE children (parent_id INTEGER REFERENCES parents(id));
+				INSERT INTO parents (id) VALUES (1);
+				INSERT INTO children (parent_id) VALUES (1);`,
+		},
+		{
+			// Foreign keys are off while a migration runs, so the dangling
+			// row gets in and only the check before commit catches it.
+			name: "dangling reference",
+			up: `CREATE TABLE parents (id INTEGER PRIMARY KEY);
+				CREATE TABLE children (parent_id INTEGER REFERENCES parents(id));
+				INSERT INTO children (parent_id) VALUES (1);`,
+			err: "foreign key violations",
+		},
+		{
+			name: "failing script",
+			up:   `INSERT INTO missing_table VALUES (1);`,
+			err:  "no such table",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			migrator := &Migrator{
+				db:         openTestDB(t),
+				migrations: []Migration{{Version: 1, Name: "test", Up: tt.up, Down: "SELECT 1;"}},
+			}
+
+			_, err := migrator.Up(ctx)
+			if tt.err == "" {
+				if err != nil {
+					t.Fatalf("Up: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.err)
+			}
+
+			// The failed migration is rolled back, not recorded.
+			if version, _ := migrator.CurrentVersion(ctx); version != 0 {
+				t.Errorf("version = %d, want 0", version)
+			}
+			var tables int
+			migrator.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name IN ('parents', 'children')`).Scan(&tables)
+			if tables != 0 {
+				t.Errorf("%d tables left behind", tables)
+			}
+		})
+	}
+}
+
+func TestMigratorSchemaTooNew(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_migrations (version, name) VALUES (?, 'future')`, migrator.Latest()+1); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Pending(ctx); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Pending err = %v, want ErrSchemaTooNew", err)
+	}
+	if _, err := migrator.Down(ctx, 1); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Down err = %v, want ErrSchemaTooNew", err)
+	}
+}
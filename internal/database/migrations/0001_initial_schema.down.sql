@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS book_links;
+DROP TABLE IF EXISTS game_links;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS games;
+DROP TABLE IF EXISTS resources;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,51 @@
+CREATE VIRTUAL TABLE games_fts USING fts5(
+	title, developer, description, my_thoughts,
+	content='games',
+	content_rowid='id',
+	tokenize='porter unicode61 remove_diacritics 2'
+);
+
+INSERT INTO games_fts(games_fts) VALUES ('rebuild');
+
+CREATE TRIGGER games_fts_insert AFTER INSERT ON games BEGIN
+	INSERT INTO games_fts (rowid, title, developer, description, my_thoughts)
+	VALUES (new.id, new.title, new.developer, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER games_fts_delete AFTER DELETE ON games BEGIN
+	INSERT INTO games_fts (games_fts, rowid, title, developer, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.developer, old.description, old.my_thoughts);
+END;
+
+CREATE TRIGGER games_fts_update AFTER UPDATE ON games BEGIN
+	INSERT INTO games_fts (games_fts, rowid, title, developer, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.developer, old.description, old.my_thoughts);
+	INSERT INTO games_fts (rowid, title, developer, description, my_thoughts)
+	VALUES (new.id, new.title, new.developer, new.description, new.my_thoughts);
+END;
+
+CREATE VIRTUAL TABLE books_fts USING fts5(
+	title, author, description, my_thoughts,
+	content='books',
+	content_rowid='id',
+	tokenize='porter unicode61 remove_diacritics 2'
+);
+
+INSERT INTO books_fts(books_fts) VALUES ('rebuild');
+
+CREATE TRIGGER books_fts_insert AFTER INSERT ON books BEGIN
+	INSERT INTO books_fts (rowid, title, author, description, my_thoughts)
+	VALUES (new.id, new.title, new.author, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER books_fts_delete AFTER DELETE ON books BEGIN
+	INSERT INTO books_fts (books_fts, rowid, title, author, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.author, old.description, old.my_thoughts);
+END;
+
+CREATE TRIGGER books_fts_update AFTER UPDATE ON books BEGIN
+	INSERT INTO books_fts (books_fts, rowid, title, author, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.author, old.description, old.my_thoughts);
+	INSERT INTO books_fts (rowid, title, author, description, my_thoughts)
+	VALUES (new.id, new.title, new.author, new.description, new.my_thoughts);
+END;
@@ -0,0 +1,5 @@
+ALTER TABLE books DROP COLUMN blurhash;
+ALTER TABLE books DROP COLUMN palette;
+
+ALTER TABLE games DROP COLUMN blurhash;
+ALTER TABLE games DROP COLUMN palette;
@@ -0,0 +1,5 @@
+ALTER TABLE games ADD COLUMN palette TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE games ADD COLUMN blurhash TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE books ADD COLUMN palette TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE books ADD COLUMN blurhash TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,15 @@
+CREATE TABLE revisions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_type TEXT NOT NULL CHECK(item_type IN ('game', 'book')),
+	item_id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL,
+	author_id INTEGER,
+	action TEXT NOT NULL CHECK(action IN ('create', 'update', 'delete', 'restore')),
+	snapshot TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_revisions_item ON revisions(item_type, item_id, id);
+CREATE INDEX idx_revisions_user_id ON revisions(user_id);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_resources_deleted_at;
+DROP INDEX IF EXISTS idx_books_deleted_at;
+DROP INDEX IF EXISTS idx_games_deleted_at;
+
+DELETE FROM resources WHERE deleted_at IS NOT NULL;
+DELETE FROM books WHERE deleted_at IS NOT NULL;
+DELETE FROM games WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE resources DROP COLUMN deleted_at;
+ALTER TABLE books DROP COLUMN deleted_at;
+ALTER TABLE games DROP COLUMN deleted_at;
@@ -0,0 +1,7 @@
+ALTER TABLE games ADD COLUMN deleted_at DATETIME;
+ALTER TABLE books ADD COLUMN deleted_at DATETIME;
+ALTER TABLE resources ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX idx_games_deleted_at ON games(deleted_at);
+CREATE INDEX idx_books_deleted_at ON books(deleted_at);
+CREATE INDEX idx_resources_deleted_at ON resources(deleted_at);
@@ -0,0 +1,22 @@
+CREATE TABLE audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	actor_id INTEGER,
+	actor_username TEXT,
+	actor_role TEXT,
+	ip TEXT NOT NULL,
+	method TEXT NOT NULL,
+	route TEXT NOT NULL,
+	path TEXT NOT NULL,
+	action TEXT NOT NULL,
+	entity_type TEXT,
+	entity_id TEXT,
+	before TEXT,
+	after TEXT,
+	status INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX idx_audit_log_actor_id ON audit_log(actor_id);
+CREATE INDEX idx_audit_log_entity ON audit_log(entity_type, entity_id);
@@ -0,0 +1,50 @@
+CREATE TABLE revisions_old (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_type TEXT NOT NULL CHECK(item_type IN ('game', 'book')),
+	item_id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL,
+	author_id INTEGER,
+	action TEXT NOT NULL CHECK(action IN ('create', 'update', 'delete', 'restore')),
+	snapshot TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO revisions_old
+	SELECT id, item_type, item_id, user_id, author_id, action, snapshot, created_at FROM revisions
+	WHERE item_type IN ('game', 'book');
+DROP TABLE revisions;
+ALTER TABLE revisions_old RENAME TO revisions;
+
+CREATE INDEX idx_revisions_item ON revisions(item_type, item_id, id);
+CREATE INDEX idx_revisions_user_id ON revisions(user_id);
+
+DROP TRIGGER IF EXISTS games_links_delete;
+DROP TRIGGER IF EXISTS books_links_delete;
+
+CREATE TABLE game_links (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	game_id INTEGER NOT NULL,
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE
+);
+
+CREATE TABLE book_links (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL,
+	book_id INTEGER NOT NULL,
+	FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE
+);
+
+INSERT INTO game_links (key, value, game_id)
+	SELECT key, value, item_id FROM media_links WHERE item_type = 'game' ORDER BY id;
+INSERT INTO book_links (key, value, book_id)
+	SELECT key, value, item_id FROM media_links WHERE item_type = 'book' ORDER BY id;
+
+CREATE INDEX idx_game_links_game_id ON game_links(game_id);
+CREATE INDEX idx_book_links_book_id ON book_links(book_id);
+
+DROP TABLE media_links;
@@ -0,0 +1,48 @@
+CREATE TABLE media_links (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_type TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	key TEXT NOT NULL,
+	value TEXT NOT NULL
+);
+
+CREATE INDEX idx_media_links_item ON media_links(item_type, item_id);
+
+INSERT INTO media_links (item_type, item_id, key, value)
+	SELECT 'game', game_id, key, value FROM game_links ORDER BY id;
+INSERT INTO media_links (item_type, item_id, key, value)
+	SELECT 'book', book_id, key, value FROM book_links ORDER BY id;
+
+DROP TABLE game_links;
+DROP TABLE book_links;
+
+-- media_links has no foreign key to cascade through, so every media table
+-- gets a trigger that removes the links of purged entries.
+CREATE TRIGGER games_links_delete AFTER DELETE ON games BEGIN
+	DELETE FROM media_links WHERE item_type = 'game' AND item_id = old.id;
+END;
+
+CREATE TRIGGER books_links_delete AFTER DELETE ON books BEGIN
+	DELETE FROM media_links WHERE item_type = 'book' AND item_id = old.id;
+END;
+
+-- Revisions are no longer limited to games and books.
+CREATE TABLE revisions_new (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_type TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL,
+	author_id INTEGER,
+	action TEXT NOT NULL CHECK(action IN ('create', 'update', 'delete', 'restore')),
+	snapshot TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+INSERT INTO revisions_new SELECT id, item_type, item_id, user_id, author_id, action, snapshot, created_at FROM revisions;
+DROP TABLE revisions;
+ALTER TABLE revisions_new RENAME TO revisions;
+
+CREATE INDEX idx_revisions_item ON revisions(item_type, item_id, id);
+CREATE INDEX idx_revisions_user_id ON revisions(user_id);
@@ -0,0 +1,38 @@
+DROP TRIGGER IF EXISTS games_comments_delete;
+DROP TRIGGER IF EXISTS books_comments_delete;
+
+DELETE FROM media_links WHERE item_type IN ('movie', 'show');
+DELETE FROM revisions WHERE item_type IN ('movie', 'show');
+
+DROP TABLE IF EXISTS shows_fts;
+DROP TABLE IF EXISTS shows;
+DROP TABLE IF EXISTS movies_fts;
+DROP TABLE IF EXISTS movies;
+
+CREATE TABLE comments_old (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	content TEXT NOT NULL,
+	game_id INTEGER,
+	book_id INTEGER,
+	user_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+	FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	CHECK ((game_id IS NOT NULL AND book_id IS NULL) OR (game_id IS NULL AND book_id IS NOT NULL))
+);
+
+INSERT INTO comments_old (id, content, game_id, book_id, user_id, created_at, updated_at)
+	SELECT id, content,
+	       CASE WHEN item_type = 'game' THEN item_id END,
+	       CASE WHEN item_type = 'book' THEN item_id END,
+	       user_id, created_at, updated_at
+	FROM comments
+	WHERE item_type IN ('game', 'book');
+DROP TABLE comments;
+ALTER TABLE comments_old RENAME TO comments;
+
+CREATE INDEX idx_comments_game_id ON comments(game_id);
+CREATE INDEX idx_comments_book_id ON comments(book_id);
+CREATE INDEX idx_comments_user_id ON comments(user_id);
@@ -0,0 +1,157 @@
+CREATE TABLE movies (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL,
+	genres TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	rating INTEGER NOT NULL CHECK(rating >= 1 AND rating <= 5),
+	status TEXT NOT NULL,
+	description TEXT NOT NULL,
+	my_thoughts TEXT NOT NULL,
+	cover_image TEXT NOT NULL,
+	explicit INTEGER NOT NULL DEFAULT 0,
+	color TEXT NOT NULL,
+	palette TEXT NOT NULL DEFAULT '[]',
+	blurhash TEXT NOT NULL DEFAULT '',
+	director TEXT NOT NULL,
+	runtime INTEGER NOT NULL DEFAULT 0,
+	release_year INTEGER,
+	user_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	deleted_at DATETIME,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_movies_title ON movies(title);
+CREATE INDEX idx_movies_status ON movies(status);
+CREATE INDEX idx_movies_rating ON movies(rating);
+CREATE INDEX idx_movies_user_id ON movies(user_id);
+CREATE INDEX idx_movies_deleted_at ON movies(deleted_at);
+
+CREATE VIRTUAL TABLE movies_fts USING fts5(
+	title, director, description, my_thoughts,
+	content='movies',
+	content_rowid='id',
+	tokenize='porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER movies_fts_insert AFTER INSERT ON movies BEGIN
+	INSERT INTO movies_fts (rowid, title, director, description, my_thoughts)
+	VALUES (new.id, new.title, new.director, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER movies_fts_delete AFTER DELETE ON movies BEGIN
+	INSERT INTO movies_fts (movies_fts, rowid, title, director, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.director, old.description, old.my_thoughts);
+END;
+
+CREATE TRIGGER movies_fts_update AFTER UPDATE ON movies BEGIN
+	INSERT INTO movies_fts (movies_fts, rowid, title, director, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.director, old.description, old.my_thoughts);
+	INSERT INTO movies_fts (rowid, title, director, description, my_thoughts)
+	VALUES (new.id, new.title, new.director, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER movies_links_delete AFTER DELETE ON movies BEGIN
+	DELETE FROM media_links WHERE item_type = 'movie' AND item_id = old.id;
+END;
+
+CREATE TABLE shows (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL,
+	genres TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	rating INTEGER NOT NULL CHECK(rating >= 1 AND rating <= 5),
+	status TEXT NOT NULL,
+	description TEXT NOT NULL,
+	my_thoughts TEXT NOT NULL,
+	cover_image TEXT NOT NULL,
+	explicit INTEGER NOT NULL DEFAULT 0,
+	color TEXT NOT NULL,
+	palette TEXT NOT NULL DEFAULT '[]',
+	blurhash TEXT NOT NULL DEFAULT '',
+	creator TEXT NOT NULL,
+	seasons INTEGER NOT NULL DEFAULT 0,
+	episodes_watched INTEGER NOT NULL DEFAULT 0,
+	season_progress TEXT NOT NULL DEFAULT '[]',
+	user_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	deleted_at DATETIME,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_shows_title ON shows(title);
+CREATE INDEX idx_shows_status ON shows(status);
+CREATE INDEX idx_shows_rating ON shows(rating);
+CREATE INDEX idx_shows_user_id ON shows(user_id);
+CREATE INDEX idx_shows_deleted_at ON shows(deleted_at);
+
+CREATE VIRTUAL TABLE shows_fts USING fts5(
+	title, creator, description, my_thoughts,
+	content='shows',
+	content_rowid='id',
+	tokenize='porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER shows_fts_insert AFTER INSERT ON shows BEGIN
+	INSERT INTO shows_fts (rowid, title, creator, description, my_thoughts)
+	VALUES (new.id, new.title, new.creator, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER shows_fts_delete AFTER DELETE ON shows BEGIN
+	INSERT INTO shows_fts (shows_fts, rowid, title, creator, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.creator, old.description, old.my_thoughts);
+END;
+
+CREATE TRIGGER shows_fts_update AFTER UPDATE ON shows BEGIN
+	INSERT INTO shows_fts (shows_fts, rowid, title, creator, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.creator, old.description, old.my_thoughts);
+	INSERT INTO shows_fts (rowid, title, creator, description, my_thoughts)
+	VALUES (new.id, new.title, new.creator, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER shows_links_delete AFTER DELETE ON shows BEGIN
+	DELETE FROM media_links WHERE item_type = 'show' AND item_id = old.id;
+END;
+
+-- Comments move from one foreign key per media table to the same item_type
+-- and item_id pair media_links uses, so new media types need no new columns.
+CREATE TABLE comments_new (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	content TEXT NOT NULL,
+	item_type TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+INSERT INTO comments_new (id, content, item_type, item_id, user_id, created_at, updated_at)
+	SELECT id, content,
+	       CASE WHEN game_id IS NOT NULL THEN 'game' ELSE 'book' END,
+	       COALESCE(game_id, book_id),
+	       user_id, created_at, updated_at
+	FROM comments;
+DROP TABLE comments;
+ALTER TABLE comments_new RENAME TO comments;
+
+CREATE INDEX idx_comments_item ON comments(item_type, item_id);
+CREATE INDEX idx_comments_user_id ON comments(user_id);
+
+CREATE TRIGGER games_comments_delete AFTER DELETE ON games BEGIN
+	DELETE FROM comments WHERE item_type = 'game' AND item_id = old.id;
+END;
+
+CREATE TRIGGER books_comments_delete AFTER DELETE ON books BEGIN
+	DELETE FROM comments WHERE item_type = 'book' AND item_id = old.id;
+END;
+
+CREATE TRIGGER movies_comments_delete AFTER DELETE ON movies BEGIN
+	DELETE FROM comments WHERE item_type = 'movie' AND item_id = old.id;
+END;
+
+CREATE TRIGGER shows_comments_delete AFTER DELETE ON shows BEGIN
+	DELETE FROM comments WHERE item_type = 'show' AND item_id = old.id;
+END;
@@ -0,0 +1,6 @@
+DELETE FROM media_links WHERE item_type = 'album';
+DELETE FROM comments WHERE item_type = 'album';
+DELETE FROM revisions WHERE item_type = 'album';
+
+DROP TABLE IF EXISTS albums_fts;
+DROP TABLE IF EXISTS albums;
@@ -0,0 +1,61 @@
+CREATE TABLE albums (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL,
+	genres TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	rating INTEGER NOT NULL CHECK(rating >= 1 AND rating <= 5),
+	status TEXT NOT NULL,
+	description TEXT NOT NULL,
+	my_thoughts TEXT NOT NULL,
+	cover_image TEXT NOT NULL,
+	explicit INTEGER NOT NULL DEFAULT 0,
+	color TEXT NOT NULL,
+	palette TEXT NOT NULL DEFAULT '[]',
+	blurhash TEXT NOT NULL DEFAULT '',
+	artist TEXT NOT NULL,
+	release_year INTEGER,
+	tracklist TEXT NOT NULL DEFAULT '[]',
+	user_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	deleted_at DATETIME,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_albums_title ON albums(title);
+CREATE INDEX idx_albums_status ON albums(status);
+CREATE INDEX idx_albums_rating ON albums(rating);
+CREATE INDEX idx_albums_user_id ON albums(user_id);
+CREATE INDEX idx_albums_deleted_at ON albums(deleted_at);
+
+CREATE VIRTUAL TABLE albums_fts USING fts5(
+	title, artist, description, my_thoughts,
+	content='albums',
+	content_rowid='id',
+	tokenize='porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER albums_fts_insert AFTER INSERT ON albums BEGIN
+	INSERT INTO albums_fts (rowid, title, artist, description, my_thoughts)
+	VALUES (new.id, new.title, new.artist, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER albums_fts_delete AFTER DELETE ON albums BEGIN
+	INSERT INTO albums_fts (albums_fts, rowid, title, artist, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.artist, old.description, old.my_thoughts);
+END;
+
+CREATE TRIGGER albums_fts_update AFTER UPDATE ON albums BEGIN
+	INSERT INTO albums_fts (albums_fts, rowid, title, artist, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.artist, old.description, old.my_thoughts);
+	INSERT INTO albums_fts (rowid, title, artist, description, my_thoughts)
+	VALUES (new.id, new.title, new.artist, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER albums_links_delete AFTER DELETE ON albums BEGIN
+	DELETE FROM media_links WHERE item_type = 'album' AND item_id = old.id;
+END;
+
+CREATE TRIGGER albums_comments_delete AFTER DELETE ON albums BEGIN
+	DELETE FROM comments WHERE item_type = 'album' AND item_id = old.id;
+END;
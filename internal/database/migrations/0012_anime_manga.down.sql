@@ -0,0 +1,8 @@
+DELETE FROM media_links WHERE item_type IN ('anime', 'manga');
+DELETE FROM comments WHERE item_type IN ('anime', 'manga');
+DELETE FROM revisions WHERE item_type IN ('anime', 'manga');
+
+DROP TABLE IF EXISTS manga_fts;
+DROP TABLE IF EXISTS manga;
+DROP TABLE IF EXISTS anime_fts;
+DROP TABLE IF EXISTS anime;
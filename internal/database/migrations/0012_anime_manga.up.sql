@@ -0,0 +1,127 @@
+CREATE TABLE anime (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL,
+	genres TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	rating INTEGER NOT NULL CHECK(rating >= 1 AND rating <= 5),
+	status TEXT NOT NULL,
+	description TEXT NOT NULL,
+	my_thoughts TEXT NOT NULL,
+	cover_image TEXT NOT NULL,
+	explicit INTEGER NOT NULL DEFAULT 0,
+	color TEXT NOT NULL,
+	palette TEXT NOT NULL DEFAULT '[]',
+	blurhash TEXT NOT NULL DEFAULT '',
+	studio TEXT NOT NULL,
+	episodes_total INTEGER,
+	episodes_watched INTEGER NOT NULL DEFAULT 0,
+	airing_status TEXT NOT NULL DEFAULT '',
+	user_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	deleted_at DATETIME,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_anime_title ON anime(title);
+CREATE INDEX idx_anime_status ON anime(status);
+CREATE INDEX idx_anime_rating ON anime(rating);
+CREATE INDEX idx_anime_user_id ON anime(user_id);
+CREATE INDEX idx_anime_deleted_at ON anime(deleted_at);
+
+CREATE VIRTUAL TABLE anime_fts USING fts5(
+	title, studio, description, my_thoughts,
+	content='anime',
+	content_rowid='id',
+	tokenize='porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER anime_fts_insert AFTER INSERT ON anime BEGIN
+	INSERT INTO anime_fts (rowid, title, studio, description, my_thoughts)
+	VALUES (new.id, new.title, new.studio, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER anime_fts_delete AFTER DELETE ON anime BEGIN
+	INSERT INTO anime_fts (anime_fts, rowid, title, studio, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.studio, old.description, old.my_thoughts);
+END;
+
+CREATE TRIGGER anime_fts_update AFTER UPDATE ON anime BEGIN
+	INSERT INTO anime_fts (anime_fts, rowid, title, studio, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.studio, old.description, old.my_thoughts);
+	INSERT INTO anime_fts (rowid, title, studio, description, my_thoughts)
+	VALUES (new.id, new.title, new.studio, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER anime_links_delete AFTER DELETE ON anime BEGIN
+	DELETE FROM media_links WHERE item_type = 'anime' AND item_id = old.id;
+END;
+
+CREATE TRIGGER anime_comments_delete AFTER DELETE ON anime BEGIN
+	DELETE FROM comments WHERE item_type = 'anime' AND item_id = old.id;
+END;
+
+CREATE TABLE manga (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title TEXT NOT NULL,
+	genres TEXT NOT NULL,
+	tags TEXT NOT NULL,
+	rating INTEGER NOT NULL CHECK(rating >= 1 AND rating <= 5),
+	status TEXT NOT NULL,
+	description TEXT NOT NULL,
+	my_thoughts TEXT NOT NULL,
+	cover_image TEXT NOT NULL,
+	explicit INTEGER NOT NULL DEFAULT 0,
+	color TEXT NOT NULL,
+	palette TEXT NOT NULL DEFAULT '[]',
+	blurhash TEXT NOT NULL DEFAULT '',
+	author TEXT NOT NULL,
+	chapters_total INTEGER,
+	chapters_read INTEGER NOT NULL DEFAULT 0,
+	volumes_total INTEGER,
+	volumes_read INTEGER NOT NULL DEFAULT 0,
+	publishing_status TEXT NOT NULL DEFAULT '',
+	user_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	deleted_at DATETIME,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_manga_title ON manga(title);
+CREATE INDEX idx_manga_status ON manga(status);
+CREATE INDEX idx_manga_rating ON manga(rating);
+CREATE INDEX idx_manga_user_id ON manga(user_id);
+CREATE INDEX idx_manga_deleted_at ON manga(deleted_at);
+
+CREATE VIRTUAL TABLE manga_fts USING fts5(
+	title, author, description, my_thoughts,
+	content='manga',
+	content_rowid='id',
+	tokenize='porter unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER manga_fts_insert AFTER INSERT ON manga BEGIN
+	INSERT INTO manga_fts (rowid, title, author, description, my_thoughts)
+	VALUES (new.id, new.title, new.author, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER manga_fts_delete AFTER DELETE ON manga BEGIN
+	INSERT INTO manga_fts (manga_fts, rowid, title, author, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.author, old.description, old.my_thoughts);
+END;
+
+CREATE TRIGGER manga_fts_update AFTER UPDATE ON manga BEGIN
+	INSERT INTO manga_fts (manga_fts, rowid, title, author, description, my_thoughts)
+	VALUES ('delete', old.id, old.title, old.author, old.description, old.my_thoughts);
+	INSERT INTO manga_fts (rowid, title, author, description, my_thoughts)
+	VALUES (new.id, new.title, new.author, new.description, new.my_thoughts);
+END;
+
+CREATE TRIGGER manga_links_delete AFTER DELETE ON manga BEGIN
+	DELETE FROM media_links WHERE item_type = 'manga' AND item_id = old.id;
+END;
+
+CREATE TRIGGER manga_comments_delete AFTER DELETE ON manga BEGIN
+	DELETE FROM comments WHERE item_type = 'manga' AND item_id = old.id;
+END;
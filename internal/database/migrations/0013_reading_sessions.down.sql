@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS reading_sessions;
+
+ALTER TABLE books DROP COLUMN current_page;
+ALTER TABLE books DROP COLUMN page_count;
@@ -0,0 +1,19 @@
+ALTER TABLE books ADD COLUMN page_count INTEGER;
+ALTER TABLE books ADD COLUMN current_page INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE reading_sessions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	book_id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL,
+	start_page INTEGER NOT NULL,
+	end_page INTEGER NOT NULL,
+	started_at DATETIME NOT NULL,
+	ended_at DATETIME,
+	notes TEXT NOT NULL DEFAULT '',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (book_id) REFERENCES books(id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_reading_sessions_book_id ON reading_sessions(book_id, started_at);
+CREATE INDEX idx_reading_sessions_user_id ON reading_sessions(user_id);
@@ -0,0 +1,18 @@
+CREATE TABLE play_sessions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	game_id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL,
+	platform TEXT NOT NULL DEFAULT '',
+	started_at DATETIME NOT NULL,
+	ended_at DATETIME,
+	notes TEXT NOT NULL DEFAULT '',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (game_id) REFERENCES games(id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_play_sessions_game_id ON play_sessions(game_id, started_at);
+CREATE INDEX idx_play_sessions_user_id ON play_sessions(user_id);
+
+-- A game has at most one session running at a time.
+CREATE UNIQUE INDEX idx_play_sessions_running ON play_sessions(game_id) WHERE ended_at IS NULL;
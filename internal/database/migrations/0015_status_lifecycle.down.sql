@@ -0,0 +1,38 @@
+DROP TRIGGER IF EXISTS games_status_insert;
+DROP TRIGGER IF EXISTS games_status_update;
+DROP TRIGGER IF EXISTS games_status_history_delete;
+DROP TRIGGER IF EXISTS books_status_insert;
+DROP TRIGGER IF EXISTS books_status_update;
+DROP TRIGGER IF EXISTS books_status_history_delete;
+DROP TRIGGER IF EXISTS movies_status_insert;
+DROP TRIGGER IF EXISTS movies_status_update;
+DROP TRIGGER IF EXISTS movies_status_history_delete;
+DROP TRIGGER IF EXISTS shows_status_insert;
+DROP TRIGGER IF EXISTS shows_status_update;
+DROP TRIGGER IF EXISTS shows_status_history_delete;
+DROP TRIGGER IF EXISTS albums_status_insert;
+DROP TRIGGER IF EXISTS albums_status_update;
+DROP TRIGGER IF EXISTS albums_status_history_delete;
+DROP TRIGGER IF EXISTS anime_status_insert;
+DROP TRIGGER IF EXISTS anime_status_update;
+DROP TRIGGER IF EXISTS anime_status_history_delete;
+DROP TRIGGER IF EXISTS manga_status_insert;
+DROP TRIGGER IF EXISTS manga_status_update;
+DROP TRIGGER IF EXISTS manga_status_history_delete;
+
+DROP TABLE IF EXISTS status_history;
+
+ALTER TABLE games DROP COLUMN completed_at;
+ALTER TABLE games DROP COLUMN started_at;
+ALTER TABLE books DROP COLUMN completed_at;
+ALTER TABLE books DROP COLUMN started_at;
+ALTER TABLE movies DROP COLUMN completed_at;
+ALTER TABLE movies DROP COLUMN started_at;
+ALTER TABLE shows DROP COLUMN completed_at;
+ALTER TABLE shows DROP COLUMN started_at;
+ALTER TABLE albums DROP COLUMN completed_at;
+ALTER TABLE albums DROP COLUMN started_at;
+ALTER TABLE anime DROP COLUMN completed_at;
+ALTER TABLE anime DROP COLUMN started_at;
+ALTER TABLE manga DROP COLUMN completed_at;
+ALTER TABLE manga DROP COLUMN started_at;
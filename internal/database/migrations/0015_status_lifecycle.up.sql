@@ -0,0 +1,292 @@
+ALTER TABLE games ADD COLUMN started_at DATETIME;
+ALTER TABLE games ADD COLUMN completed_at DATETIME;
+ALTER TABLE books ADD COLUMN started_at DATETIME;
+ALTER TABLE books ADD COLUMN completed_at DATETIME;
+ALTER TABLE movies ADD COLUMN started_at DATETIME;
+ALTER TABLE movies ADD COLUMN completed_at DATETIME;
+ALTER TABLE shows ADD COLUMN started_at DATETIME;
+ALTER TABLE shows ADD COLUMN completed_at DATETIME;
+ALTER TABLE albums ADD COLUMN started_at DATETIME;
+ALTER TABLE albums ADD COLUMN completed_at DATETIME;
+ALTER TABLE anime ADD COLUMN started_at DATETIME;
+ALTER TABLE anime ADD COLUMN completed_at DATETIME;
+ALTER TABLE manga ADD COLUMN started_at DATETIME;
+ALTER TABLE manga ADD COLUMN completed_at DATETIME;
+
+-- from_status is NULL for the status an entry was created with.
+CREATE TABLE status_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	item_type TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	user_id INTEGER NOT NULL,
+	author_id INTEGER,
+	from_status TEXT,
+	to_status TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+	FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_status_history_item ON status_history(item_type, item_id, id);
+CREATE INDEX idx_status_history_user_id ON status_history(user_id);
+
+-- Free-form statuses are folded onto the vocabulary: case, surrounding
+-- spaces, dashes and underscores are ignored. The original value stays in
+-- the history. started_at and completed_at are left empty, as there is no
+-- telling when those happened.
+CREATE TEMP TABLE status_map (
+	raw TEXT PRIMARY KEY,
+	normalized TEXT NOT NULL
+);
+
+INSERT INTO status_map (raw, normalized) VALUES
+	('planned', 'planned'),
+	('plan', 'planned'),
+	('plan to play', 'planned'),
+	('plan to read', 'planned'),
+	('plan to watch', 'planned'),
+	('plan to listen', 'planned'),
+	('ptw', 'planned'),
+	('ptr', 'planned'),
+	('wishlist', 'planned'),
+	('wishlisted', 'planned'),
+	('backlog', 'planned'),
+	('to read', 'planned'),
+	('want to read', 'planned'),
+	('want to play', 'planned'),
+	('want to watch', 'planned'),
+	('todo', 'planned'),
+	('to do', 'planned'),
+	('queued', 'planned'),
+	('not started', 'planned'),
+	('unplayed', 'planned'),
+	('unread', 'planned'),
+	('unwatched', 'planned'),
+	('new', 'planned'),
+	('in progress', 'in_progress'),
+	('playing', 'in_progress'),
+	('reading', 'in_progress'),
+	('watching', 'in_progress'),
+	('listening', 'in_progress'),
+	('ongoing', 'in_progress'),
+	('started', 'in_progress'),
+	('current', 'in_progress'),
+	('currently reading', 'in_progress'),
+	('currently playing', 'in_progress'),
+	('currently watching', 'in_progress'),
+	('active', 'in_progress'),
+	('wip', 'in_progress'),
+	('paused', 'paused'),
+	('on hold', 'paused'),
+	('hold', 'paused'),
+	('shelved', 'paused'),
+	('suspended', 'paused'),
+	('completed', 'completed'),
+	('complete', 'completed'),
+	('done', 'completed'),
+	('finished', 'completed'),
+	('beaten', 'completed'),
+	('beat', 'completed'),
+	('read', 'completed'),
+	('watched', 'completed'),
+	('played', 'completed'),
+	('listened', 'completed'),
+	('cleared', 'completed'),
+	('100%', 'completed'),
+	('dropped', 'dropped'),
+	('abandoned', 'dropped'),
+	('quit', 'dropped'),
+	('dnf', 'dropped'),
+	('did not finish', 'dropped'),
+	('gave up', 'dropped'),
+	('stopped', 'dropped');
+
+-- Statuses that match nothing stop the migration rather than being guessed
+-- at. The error lists the entries (type, ID and status) to fix by hand
+-- before migrating again.
+CREATE TEMP TABLE unknown_statuses (entries TEXT NOT NULL);
+
+CREATE TEMP TRIGGER unknown_statuses_insert BEFORE INSERT ON unknown_statuses BEGIN
+	SELECT RAISE(ABORT, 'unrecognised statuses, fix these entries and migrate again: ' || new.entries);
+END;
+
+INSERT INTO unknown_statuses (entries)
+	SELECT group_concat(entry, ', ') FROM (
+		SELECT 'game ' || id || ' ' || quote(status) AS entry FROM games
+		WHERE NOT EXISTS (SELECT 1 FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '))
+		UNION ALL
+		SELECT 'book ' || id || ' ' || quote(status) AS entry FROM books
+		WHERE NOT EXISTS (SELECT 1 FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '))
+		UNION ALL
+		SELECT 'movie ' || id || ' ' || quote(status) AS entry FROM movies
+		WHERE NOT EXISTS (SELECT 1 FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '))
+		UNION ALL
+		SELECT 'show ' || id || ' ' || quote(status) AS entry FROM shows
+		WHERE NOT EXISTS (SELECT 1 FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '))
+		UNION ALL
+		SELECT 'album ' || id || ' ' || quote(status) AS entry FROM albums
+		WHERE NOT EXISTS (SELECT 1 FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '))
+		UNION ALL
+		SELECT 'anime ' || id || ' ' || quote(status) AS entry FROM anime
+		WHERE NOT EXISTS (SELECT 1 FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '))
+		UNION ALL
+		SELECT 'manga ' || id || ' ' || quote(status) AS entry FROM manga
+		WHERE NOT EXISTS (SELECT 1 FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '))
+	) HAVING count(*) > 0;
+
+DROP TABLE unknown_statuses;
+
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status, created_at)
+	SELECT 'game', id, user_id, NULL, status, created_at FROM games;
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'game', id, user_id, status, (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' ')) FROM games
+	WHERE status <> (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+UPDATE games SET status = (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status, created_at)
+	SELECT 'book', id, user_id, NULL, status, created_at FROM books;
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'book', id, user_id, status, (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' ')) FROM books
+	WHERE status <> (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+UPDATE books SET status = (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status, created_at)
+	SELECT 'movie', id, user_id, NULL, status, created_at FROM movies;
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'movie', id, user_id, status, (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' ')) FROM movies
+	WHERE status <> (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+UPDATE movies SET status = (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status, created_at)
+	SELECT 'show', id, user_id, NULL, status, created_at FROM shows;
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'show', id, user_id, status, (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' ')) FROM shows
+	WHERE status <> (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+UPDATE shows SET status = (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status, created_at)
+	SELECT 'album', id, user_id, NULL, status, created_at FROM albums;
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'album', id, user_id, status, (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' ')) FROM albums
+	WHERE status <> (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+UPDATE albums SET status = (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status, created_at)
+	SELECT 'anime', id, user_id, NULL, status, created_at FROM anime;
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'anime', id, user_id, status, (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' ')) FROM anime
+	WHERE status <> (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+UPDATE anime SET status = (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status, created_at)
+	SELECT 'manga', id, user_id, NULL, status, created_at FROM manga;
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'manga', id, user_id, status, (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' ')) FROM manga
+	WHERE status <> (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+UPDATE manga SET status = (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(status)), '_', ' '), '-', ' '));
+
+-- Snapshots are rolled back verbatim, so they are normalised too. Old
+-- snapshots whose status matches nothing are left as they were; restoring
+-- one fails on the status triggers below instead of guessing.
+UPDATE revisions SET snapshot = json_set(snapshot, '$.row.status', (SELECT normalized FROM status_map WHERE raw = replace(replace(lower(trim(json_extract(snapshot, '$.row.status'))), '_', ' '), '-', ' ')))
+	WHERE EXISTS (SELECT 1 FROM status_map WHERE raw = replace(replace(lower(trim(json_extract(snapshot, '$.row.status'))), '_', ' '), '-', ' '));
+
+DROP TABLE status_map;
+
+CREATE TRIGGER games_status_insert BEFORE INSERT ON games
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER games_status_update BEFORE UPDATE OF status ON games
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER games_status_history_delete AFTER DELETE ON games BEGIN
+	DELETE FROM status_history WHERE item_type = 'game' AND item_id = old.id;
+END;
+
+CREATE TRIGGER books_status_insert BEFORE INSERT ON books
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER books_status_update BEFORE UPDATE OF status ON books
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER books_status_history_delete AFTER DELETE ON books BEGIN
+	DELETE FROM status_history WHERE item_type = 'book' AND item_id = old.id;
+END;
+
+CREATE TRIGGER movies_status_insert BEFORE INSERT ON movies
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER movies_status_update BEFORE UPDATE OF status ON movies
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER movies_status_history_delete AFTER DELETE ON movies BEGIN
+	DELETE FROM status_history WHERE item_type = 'movie' AND item_id = old.id;
+END;
+
+CREATE TRIGGER shows_status_insert BEFORE INSERT ON shows
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER shows_status_update BEFORE UPDATE OF status ON shows
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER shows_status_history_delete AFTER DELETE ON shows BEGIN
+	DELETE FROM status_history WHERE item_type = 'show' AND item_id = old.id;
+END;
+
+CREATE TRIGGER albums_status_insert BEFORE INSERT ON albums
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER albums_status_update BEFORE UPDATE OF status ON albums
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER albums_status_history_delete AFTER DELETE ON albums BEGIN
+	DELETE FROM status_history WHERE item_type = 'album' AND item_id = old.id;
+END;
+
+CREATE TRIGGER anime_status_insert BEFORE INSERT ON anime
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER anime_status_update BEFORE UPDATE OF status ON anime
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER anime_status_history_delete AFTER DELETE ON anime BEGIN
+	DELETE FROM status_history WHERE item_type = 'anime' AND item_id = old.id;
+END;
+
+CREATE TRIGGER manga_status_insert BEFORE INSERT ON manga
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER manga_status_update BEFORE UPDATE OF status ON manga
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER manga_status_history_delete AFTER DELETE ON manga BEGIN
+	DELETE FROM status_history WHERE item_type = 'manga' AND item_id = old.id;
+END;
@@ -0,0 +1,26 @@
+DROP TRIGGER IF EXISTS games_taxonomy_insert;
+DROP TRIGGER IF EXISTS games_taxonomy_update;
+DROP TRIGGER IF EXISTS games_taxonomy_delete;
+DROP TRIGGER IF EXISTS books_taxonomy_insert;
+DROP TRIGGER IF EXISTS books_taxonomy_update;
+DROP TRIGGER IF EXISTS books_taxonomy_delete;
+DROP TRIGGER IF EXISTS movies_taxonomy_insert;
+DROP TRIGGER IF EXISTS movies_taxonomy_update;
+DROP TRIGGER IF EXISTS movies_taxonomy_delete;
+DROP TRIGGER IF EXISTS shows_taxonomy_insert;
+DROP TRIGGER IF EXISTS shows_taxonomy_update;
+DROP TRIGGER IF EXISTS shows_taxonomy_delete;
+DROP TRIGGER IF EXISTS albums_taxonomy_insert;
+DROP TRIGGER IF EXISTS albums_taxonomy_update;
+DROP TRIGGER IF EXISTS albums_taxonomy_delete;
+DROP TRIGGER IF EXISTS anime_taxonomy_insert;
+DROP TRIGGER IF EXISTS anime_taxonomy_update;
+DROP TRIGGER IF EXISTS anime_taxonomy_delete;
+DROP TRIGGER IF EXISTS manga_taxonomy_insert;
+DROP TRIGGER IF EXISTS manga_taxonomy_update;
+DROP TRIGGER IF EXISTS manga_taxonomy_delete;
+
+DROP TABLE IF EXISTS media_genres;
+DROP TABLE IF EXISTS genres;
+DROP TABLE IF EXISTS media_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,343 @@
+CREATE TABLE tags (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (user_id, name),
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE media_tags (
+	tag_id INTEGER NOT NULL,
+	item_type TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	PRIMARY KEY (tag_id, item_type, item_id),
+	FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_media_tags_item ON media_tags(item_type, item_id);
+
+CREATE TABLE genres (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	name TEXT NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (user_id, name),
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE media_genres (
+	genre_id INTEGER NOT NULL,
+	item_type TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	PRIMARY KEY (genre_id, item_type, item_id),
+	FOREIGN KEY (genre_id) REFERENCES genres(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_media_genres_item ON media_genres(item_type, item_id);
+
+-- The JSON arrays on each entry stay the source of truth; these triggers
+-- keep the join tables in step with them. Trashed entries are left out, so
+-- usage counts only cover live entries.
+
+CREATE TRIGGER games_taxonomy_insert AFTER INSERT ON games BEGIN
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'game', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'game', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER games_taxonomy_update AFTER UPDATE OF genres, tags, user_id, deleted_at ON games BEGIN
+	DELETE FROM media_tags WHERE item_type = 'game' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'game' AND item_id = old.id;
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'game', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'game', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER games_taxonomy_delete AFTER DELETE ON games BEGIN
+	DELETE FROM media_tags WHERE item_type = 'game' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'game' AND item_id = old.id;
+END;
+
+CREATE TRIGGER books_taxonomy_insert AFTER INSERT ON books BEGIN
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'book', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'book', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER books_taxonomy_update AFTER UPDATE OF genres, tags, user_id, deleted_at ON books BEGIN
+	DELETE FROM media_tags WHERE item_type = 'book' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'book' AND item_id = old.id;
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'book', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'book', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER books_taxonomy_delete AFTER DELETE ON books BEGIN
+	DELETE FROM media_tags WHERE item_type = 'book' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'book' AND item_id = old.id;
+END;
+
+CREATE TRIGGER movies_taxonomy_insert AFTER INSERT ON movies BEGIN
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'movie', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'movie', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER movies_taxonomy_update AFTER UPDATE OF genres, tags, user_id, deleted_at ON movies BEGIN
+	DELETE FROM media_tags WHERE item_type = 'movie' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'movie' AND item_id = old.id;
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'movie', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'movie', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER movies_taxonomy_delete AFTER DELETE ON movies BEGIN
+	DELETE FROM media_tags WHERE item_type = 'movie' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'movie' AND item_id = old.id;
+END;
+
+CREATE TRIGGER shows_taxonomy_insert AFTER INSERT ON shows BEGIN
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'show', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'show', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER shows_taxonomy_update AFTER UPDATE OF genres, tags, user_id, deleted_at ON shows BEGIN
+	DELETE FROM media_tags WHERE item_type = 'show' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'show' AND item_id = old.id;
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'show', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'show', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER shows_taxonomy_delete AFTER DELETE ON shows BEGIN
+	DELETE FROM media_tags WHERE item_type = 'show' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'show' AND item_id = old.id;
+END;
+
+CREATE TRIGGER albums_taxonomy_insert AFTER INSERT ON albums BEGIN
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'album', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'album', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER albums_taxonomy_update AFTER UPDATE OF genres, tags, user_id, deleted_at ON albums BEGIN
+	DELETE FROM media_tags WHERE item_type = 'album' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'album' AND item_id = old.id;
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'album', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'album', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER albums_taxonomy_delete AFTER DELETE ON albums BEGIN
+	DELETE FROM media_tags WHERE item_type = 'album' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'album' AND item_id = old.id;
+END;
+
+CREATE TRIGGER anime_taxonomy_insert AFTER INSERT ON anime BEGIN
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'anime', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'anime', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER anime_taxonomy_update AFTER UPDATE OF genres, tags, user_id, deleted_at ON anime BEGIN
+	DELETE FROM media_tags WHERE item_type = 'anime' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'anime' AND item_id = old.id;
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'anime', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'anime', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER anime_taxonomy_delete AFTER DELETE ON anime BEGIN
+	DELETE FROM media_tags WHERE item_type = 'anime' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'anime' AND item_id = old.id;
+END;
+
+CREATE TRIGGER manga_taxonomy_insert AFTER INSERT ON manga BEGIN
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'manga', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'manga', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER manga_taxonomy_update AFTER UPDATE OF genres, tags, user_id, deleted_at ON manga BEGIN
+	DELETE FROM media_tags WHERE item_type = 'manga' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'manga' AND item_id = old.id;
+	INSERT OR IGNORE INTO tags (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.tags) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+		SELECT x.id, 'manga', new.id FROM json_each(new.tags) JOIN tags x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO genres (user_id, name)
+		SELECT new.user_id, value FROM json_each(new.genres) WHERE new.deleted_at IS NULL;
+	INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+		SELECT x.id, 'manga', new.id FROM json_each(new.genres) JOIN genres x ON x.user_id = new.user_id AND x.name = value
+		WHERE new.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER manga_taxonomy_delete AFTER DELETE ON manga BEGIN
+	DELETE FROM media_tags WHERE item_type = 'manga' AND item_id = old.id;
+	DELETE FROM media_genres WHERE item_type = 'manga' AND item_id = old.id;
+END;
+
+INSERT OR IGNORE INTO tags (user_id, name)
+	SELECT games.user_id, value FROM games, json_each(games.tags) WHERE games.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+	SELECT x.id, 'game', games.id FROM games, json_each(games.tags) JOIN tags x ON x.user_id = games.user_id AND x.name = value
+	WHERE games.deleted_at IS NULL;
+INSERT OR IGNORE INTO genres (user_id, name)
+	SELECT games.user_id, value FROM games, json_each(games.genres) WHERE games.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+	SELECT x.id, 'game', games.id FROM games, json_each(games.genres) JOIN genres x ON x.user_id = games.user_id AND x.name = value
+	WHERE games.deleted_at IS NULL;
+INSERT OR IGNORE INTO tags (user_id, name)
+	SELECT books.user_id, value FROM books, json_each(books.tags) WHERE books.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+	SELECT x.id, 'book', books.id FROM books, json_each(books.tags) JOIN tags x ON x.user_id = books.user_id AND x.name = value
+	WHERE books.deleted_at IS NULL;
+INSERT OR IGNORE INTO genres (user_id, name)
+	SELECT books.user_id, value FROM books, json_each(books.genres) WHERE books.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+	SELECT x.id, 'book', books.id FROM books, json_each(books.genres) JOIN genres x ON x.user_id = books.user_id AND x.name = value
+	WHERE books.deleted_at IS NULL;
+INSERT OR IGNORE INTO tags (user_id, name)
+	SELECT movies.user_id, value FROM movies, json_each(movies.tags) WHERE movies.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+	SELECT x.id, 'movie', movies.id FROM movies, json_each(movies.tags) JOIN tags x ON x.user_id = movies.user_id AND x.name = value
+	WHERE movies.deleted_at IS NULL;
+INSERT OR IGNORE INTO genres (user_id, name)
+	SELECT movies.user_id, value FROM movies, json_each(movies.genres) WHERE movies.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+	SELECT x.id, 'movie', movies.id FROM movies, json_each(movies.genres) JOIN genres x ON x.user_id = movies.user_id AND x.name = value
+	WHERE movies.deleted_at IS NULL;
+INSERT OR IGNORE INTO tags (user_id, name)
+	SELECT shows.user_id, value FROM shows, json_each(shows.tags) WHERE shows.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+	SELECT x.id, 'show', shows.id FROM shows, json_each(shows.tags) JOIN tags x ON x.user_id = shows.user_id AND x.name = value
+	WHERE shows.deleted_at IS NULL;
+INSERT OR IGNORE INTO genres (user_id, name)
+	SELECT shows.user_id, value FROM shows, json_each(shows.genres) WHERE shows.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+	SELECT x.id, 'show', shows.id FROM shows, json_each(shows.genres) JOIN genres x ON x.user_id = shows.user_id AND x.name = value
+	WHERE shows.deleted_at IS NULL;
+INSERT OR IGNORE INTO tags (user_id, name)
+	SELECT albums.user_id, value FROM albums, json_each(albums.tags) WHERE albums.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+	SELECT x.id, 'album', albums.id FROM albums, json_each(albums.tags) JOIN tags x ON x.user_id = albums.user_id AND x.name = value
+	WHERE albums.deleted_at IS NULL;
+INSERT OR IGNORE INTO genres (user_id, name)
+	SELECT albums.user_id, value FROM albums, json_each(albums.genres) WHERE albums.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+	SELECT x.id, 'album', albums.id FROM albums, json_each(albums.genres) JOIN genres x ON x.user_id = albums.user_id AND x.name = value
+	WHERE albums.deleted_at IS NULL;
+INSERT OR IGNORE INTO tags (user_id, name)
+	SELECT anime.user_id, value FROM anime, json_each(anime.tags) WHERE anime.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+	SELECT x.id, 'anime', anime.id FROM anime, json_each(anime.tags) JOIN tags x ON x.user_id = anime.user_id AND x.name = value
+	WHERE anime.deleted_at IS NULL;
+INSERT OR IGNORE INTO genres (user_id, name)
+	SELECT anime.user_id, value FROM anime, json_each(anime.genres) WHERE anime.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+	SELECT x.id, 'anime', anime.id FROM anime, json_each(anime.genres) JOIN genres x ON x.user_id = anime.user_id AND x.name = value
+	WHERE anime.deleted_at IS NULL;
+INSERT OR IGNORE INTO tags (user_id, name)
+	SELECT manga.user_id, value FROM manga, json_each(manga.tags) WHERE manga.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_tags (tag_id, item_type, item_id)
+	SELECT x.id, 'manga', manga.id FROM manga, json_each(manga.tags) JOIN tags x ON x.user_id = manga.user_id AND x.name = value
+	WHERE manga.deleted_at IS NULL;
+INSERT OR IGNORE INTO genres (user_id, name)
+	SELECT manga.user_id, value FROM manga, json_each(manga.genres) WHERE manga.deleted_at IS NULL;
+INSERT OR IGNORE INTO media_genres (genre_id, item_type, item_id)
+	SELECT x.id, 'manga', manga.id FROM manga, json_each(manga.genres) JOIN genres x ON x.user_id = manga.user_id AND x.name = value
+	WHERE manga.deleted_at IS NULL;
@@ -0,0 +1,10 @@
+DROP TRIGGER IF EXISTS games_collections_delete;
+DROP TRIGGER IF EXISTS books_collections_delete;
+DROP TRIGGER IF EXISTS movies_collections_delete;
+DROP TRIGGER IF EXISTS shows_collections_delete;
+DROP TRIGGER IF EXISTS albums_collections_delete;
+DROP TRIGGER IF EXISTS anime_collections_delete;
+DROP TRIGGER IF EXISTS manga_collections_delete;
+
+DROP TABLE IF EXISTS collection_items;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,60 @@
+CREATE TABLE collections (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	cover_image TEXT NOT NULL DEFAULT '',
+	visibility TEXT NOT NULL DEFAULT 'private' CHECK(visibility IN ('private', 'unlisted', 'public')),
+	share_token TEXT NOT NULL UNIQUE,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_collections_user_id ON collections(user_id);
+CREATE INDEX idx_collections_visibility ON collections(visibility);
+
+CREATE TABLE collection_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	collection_id INTEGER NOT NULL,
+	item_type TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	position INTEGER NOT NULL,
+	notes TEXT NOT NULL DEFAULT '',
+	added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (collection_id, item_type, item_id),
+	FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_collection_items_collection ON collection_items(collection_id, position);
+CREATE INDEX idx_collection_items_item ON collection_items(item_type, item_id);
+
+-- Membership goes with purged entries; trashed ones are only hidden.
+
+CREATE TRIGGER games_collections_delete AFTER DELETE ON games BEGIN
+	DELETE FROM collection_items WHERE item_type = 'game' AND item_id = old.id;
+END;
+
+CREATE TRIGGER books_collections_delete AFTER DELETE ON books BEGIN
+	DELETE FROM collection_items WHERE item_type = 'book' AND item_id = old.id;
+END;
+
+CREATE TRIGGER movies_collections_delete AFTER DELETE ON movies BEGIN
+	DELETE FROM collection_items WHERE item_type = 'movie' AND item_id = old.id;
+END;
+
+CREATE TRIGGER shows_collections_delete AFTER DELETE ON shows BEGIN
+	DELETE FROM collection_items WHERE item_type = 'show' AND item_id = old.id;
+END;
+
+CREATE TRIGGER albums_collections_delete AFTER DELETE ON albums BEGIN
+	DELETE FROM collection_items WHERE item_type = 'album' AND item_id = old.id;
+END;
+
+CREATE TRIGGER anime_collections_delete AFTER DELETE ON anime BEGIN
+	DELETE FROM collection_items WHERE item_type = 'anime' AND item_id = old.id;
+END;
+
+CREATE TRIGGER manga_collections_delete AFTER DELETE ON manga BEGIN
+	DELETE FROM collection_items WHERE item_type = 'manga' AND item_id = old.id;
+END;
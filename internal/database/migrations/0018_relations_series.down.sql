@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS games_relations_delete;
+DROP TRIGGER IF EXISTS books_relations_delete;
+DROP TRIGGER IF EXISTS movies_relations_delete;
+DROP TRIGGER IF EXISTS shows_relations_delete;
+DROP TRIGGER IF EXISTS albums_relations_delete;
+DROP TRIGGER IF EXISTS anime_relations_delete;
+DROP TRIGGER IF EXISTS manga_relations_delete;
+
+DROP TABLE IF EXISTS series_entries;
+DROP TABLE IF EXISTS series;
+DROP TABLE IF EXISTS media_relations;
@@ -0,0 +1,78 @@
+-- A relation reads "from is <kind> to": a sequel points at its predecessor.
+CREATE TABLE media_relations (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	kind TEXT NOT NULL CHECK(kind IN ('sequel_of', 'prequel_of', 'adaptation_of', 'same_series', 'dlc_of', 'remake_of')),
+	from_type TEXT NOT NULL,
+	from_id INTEGER NOT NULL,
+	to_type TEXT NOT NULL,
+	to_id INTEGER NOT NULL,
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (from_type, from_id, to_type, to_id, kind),
+	CHECK (from_type != to_type OR from_id != to_id),
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_media_relations_from ON media_relations(from_type, from_id);
+CREATE INDEX idx_media_relations_to ON media_relations(to_type, to_id);
+
+CREATE TABLE series (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	title TEXT NOT NULL,
+	description TEXT NOT NULL DEFAULT '',
+	created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_series_user_id ON series(user_id);
+
+CREATE TABLE series_entries (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	series_id INTEGER NOT NULL,
+	item_type TEXT NOT NULL,
+	item_id INTEGER NOT NULL,
+	number REAL NOT NULL CHECK(number >= 0),
+	added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (series_id, item_type, item_id),
+	FOREIGN KEY (series_id) REFERENCES series(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_series_entries_series ON series_entries(series_id, number);
+CREATE INDEX idx_series_entries_item ON series_entries(item_type, item_id);
+
+CREATE TRIGGER games_relations_delete AFTER DELETE ON games BEGIN
+	DELETE FROM media_relations WHERE (from_type = 'game' AND from_id = old.id) OR (to_type = 'game' AND to_id = old.id);
+	DELETE FROM series_entries WHERE item_type = 'game' AND item_id = old.id;
+END;
+
+CREATE TRIGGER books_relations_delete AFTER DELETE ON books BEGIN
+	DELETE FROM media_relations WHERE (from_type = 'book' AND from_id = old.id) OR (to_type = 'book' AND to_id = old.id);
+	DELETE FROM series_entries WHERE item_type = 'book' AND item_id = old.id;
+END;
+
+CREATE TRIGGER movies_relations_delete AFTER DELETE ON movies BEGIN
+	DELETE FROM media_relations WHERE (from_type = 'movie' AND from_id = old.id) OR (to_type = 'movie' AND to_id = old.id);
+	DELETE FROM series_entries WHERE item_type = 'movie' AND item_id = old.id;
+END;
+
+CREATE TRIGGER shows_relations_delete AFTER DELETE ON shows BEGIN
+	DELETE FROM media_relations WHERE (from_type = 'show' AND from_id = old.id) OR (to_type = 'show' AND to_id = old.id);
+	DELETE FROM series_entries WHERE item_type = 'show' AND item_id = old.id;
+END;
+
+CREATE TRIGGER albums_relations_delete AFTER DELETE ON albums BEGIN
+	DELETE FROM media_relations WHERE (from_type = 'album' AND from_id = old.id) OR (to_type = 'album' AND to_id = old.id);
+	DELETE FROM series_entries WHERE item_type = 'album' AND item_id = old.id;
+END;
+
+CREATE TRIGGER anime_relations_delete AFTER DELETE ON anime BEGIN
+	DELETE FROM media_relations WHERE (from_type = 'anime' AND from_id = old.id) OR (to_type = 'anime' AND to_id = old.id);
+	DELETE FROM series_entries WHERE item_type = 'anime' AND item_id = old.id;
+END;
+
+CREATE TRIGGER manga_relations_delete AFTER DELETE ON manga BEGIN
+	DELETE FROM media_relations WHERE (from_type = 'manga' AND from_id = old.id) OR (to_type = 'manga' AND to_id = old.id);
+	DELETE FROM series_entries WHERE item_type = 'manga' AND item_id = old.id;
+END;
@@ -0,0 +1,25 @@
+-- Entries moved out of paused stay in progress.
+DROP TRIGGER IF EXISTS movies_status_insert;
+DROP TRIGGER IF EXISTS movies_status_update;
+DROP TRIGGER IF EXISTS albums_status_insert;
+DROP TRIGGER IF EXISTS albums_status_update;
+
+CREATE TRIGGER movies_status_insert BEFORE INSERT ON movies
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER movies_status_update BEFORE UPDATE OF status ON movies
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER albums_status_insert BEFORE INSERT ON albums
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER albums_status_update BEFORE UPDATE OF status ON albums
+WHEN new.status NOT IN ('planned', 'in_progress', 'paused', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
@@ -0,0 +1,38 @@
+-- Movies and albums have no paused status. Paused ones go back in
+-- progress, with the move added to their history and made in their
+-- snapshots, so restoring an old revision still passes the triggers.
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'movie', id, user_id, 'paused', 'in_progress' FROM movies WHERE status = 'paused';
+UPDATE movies SET status = 'in_progress' WHERE status = 'paused';
+
+INSERT INTO status_history (item_type, item_id, user_id, from_status, to_status)
+	SELECT 'album', id, user_id, 'paused', 'in_progress' FROM albums WHERE status = 'paused';
+UPDATE albums SET status = 'in_progress' WHERE status = 'paused';
+
+UPDATE revisions SET snapshot = json_set(snapshot, '$.row.status', 'in_progress')
+	WHERE item_type IN ('movie', 'album') AND json_extract(snapshot, '$.row.status') = 'paused';
+
+DROP TRIGGER IF EXISTS movies_status_insert;
+DROP TRIGGER IF EXISTS movies_status_update;
+DROP TRIGGER IF EXISTS albums_status_insert;
+DROP TRIGGER IF EXISTS albums_status_update;
+
+CREATE TRIGGER movies_status_insert BEFORE INSERT ON movies
+WHEN new.status NOT IN ('planned', 'in_progress', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER movies_status_update BEFORE UPDATE OF status ON movies
+WHEN new.status NOT IN ('planned', 'in_progress', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER albums_status_insert BEFORE INSERT ON albums
+WHEN new.status NOT IN ('planned', 'in_progress', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
+
+CREATE TRIGGER albums_status_update BEFORE UPDATE OF status ON albums
+WHEN new.status NOT IN ('planned', 'in_progress', 'completed', 'dropped') BEGIN
+	SELECT RAISE(ABORT, 'invalid status');
+END;
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// migrateTo applies the embedded migrations up to and including version.
+func migrateTo(t *testing.T, db *DB, version int) {
+	t.Helper()
+
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	migrator := &Migrator{db: db, migrations: migrations[:version]}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("Up to %d: %v", version, err)
+	}
+}
+
+// TestStatusNormalization seeds the free-form statuses entries had before
+// 0015 and checks where they end up once every migration has run.
+func TestStatusNormalization(t *testing.T) {
+	tests := []struct {
+		name   string
+		table  string
+		status string
+		want   string
+	}{
+		{"exact", "games", "planned", "planned"},
+		{"case and spaces", "games", "  Playing ", "in_progress"},
+		{"dashes", "games", "plan-to-play", "planned"},
+		{"underscores", "games", "ON_HOLD", "paused"},
+		{"synonym", "games", "Beaten", "completed"},
+		{"abbreviation", "games", "DNF", "dropped"},
+		{"book", "books", "currently reading", "in_progress"},
+		{"movie", "movies", "Watched", "completed"},
+		// Movies have no paused status, so 0019 moves them back in progress.
+		{"paused movie", "movies", "on hold", "in_progress"},
+	}
+
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrateTo(t, db, 14)
+
+	if _, err := db.Exec(`INSERT INTO users (username, email, password_hash) VALUES ('owner', 'owner@example.com', 'x')`); err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]int64, len(tests))
+	for i, tt := range tests {
+		query := `INSERT INTO games (title, developer, genres, tags, rating, status, description, my_thoughts, cover_image, color, percent, user_id)
+			VALUES ('Game', 'Studio', '[]', '[]', 3, ?, '', '', '', '#000000', 0, 1)`
+		switch tt.table {
+		case "books":
+			query = `INSERT INTO books (title, author, genres, tags, rating, status, description, my_thoughts, cover_image, color, user_id)
+				VALUES ('Book', 'Author', '[]', '[]', 3, ?, '', '', '', '#000000', 1)`
+		case "movies":
+			query = `INSERT INTO movies (title, director, genres, tags, rating, status, description, my_thoughts, cover_image, color, user_id)
+				VALUES ('Movie', 'Director', '[]', '[]', 3, ?, '', '', '', '#000000', 1)`
+		}
+
+		result, err := db.Exec(query, tt.status)
+		if err != nil {
+			t.Fatalf("seeding %s: %v", tt.name, err)
+		}
+		ids[i], _ = result.LastInsertId()
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status string
+			if err := db.QueryRow(`SELECT status FROM `+tt.table+` WHERE id = ?`, ids[i]).Scan(&status); err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.want {
+				t.Errorf("status = %s, want %s", status, tt.want)
+			}
+
+			// The history starts at the original value, so nothing is lost.
+			var first string
+			err := db.QueryRow(`SELECT to_status FROM status_history WHERE item_type = ? AND item_id = ? AND from_status IS NULL`,
+				strings.TrimSuffix(tt.table, "s"), ids[i]).Scan(&first)
+			if err != nil {
+				t.Fatalf("history: %v", err)
+			}
+			if first != tt.status {
+				t.Errorf("history starts at %q, want %q", first, tt.status)
+			}
+		})
+	}
+}
+
+func TestStatusNormalizationUnknown(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	migrateTo(t, db, 14)
+
+	_, err := db.Exec(`INSERT INTO users (username, email, password_hash) VALUES ('owner', 'owner@example.com', 'x');
+		INSERT INTO games (title, developer, genres, tags, rating, status, description, my_thoughts, cover_image, color, percent, user_id)
+		VALUES ('Known', 'Studio', '[]', '[]', 3, 'playing', '', '', '', '#000000', 0, 1),
+			('Typo', 'Studio', '[]', '[]', 3, 'plyaing', '', '', '', '#000000', 0, 1);
+		INSERT INTO books (title, author, genres, tags, rating, status, description, my_thoughts, cover_image, color, user_id)
+		VALUES ('Blank', 'Author', '[]', '[]', 3, '', '', '', '', '#000000', 1)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = migrator.Up(ctx)
+	if err == nil {
+		t.Fatal("Up succeeded with unrecognised statuses")
+	}
+	for _, entry := range []string{"game 2 'plyaing'", "book 1 ''"} {
+		if !strings.Contains(err.Error(), entry) {
+			t.Errorf("err = %v, want it to list %s", err, entry)
+		}
+	}
+	if strings.Contains(err.Error(), "game 1 ") {
+		t.Errorf("err = %v, lists a recognised status", err)
+	}
+
+	if version, _ := migrator.CurrentVersion(ctx); version != 14 {
+		t.Errorf("version = %d, want 14", version)
+	}
+
+	// Once fixed by hand, the migration goes through.
+	if _, err := db.Exec(`UPDATE games SET status = 'playing' WHERE id = 2; UPDATE books SET status = 'to read'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up after the fix: %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+const (
+	maxRedirects = 5
+	userAgent    = "Mozilla/5.0 (compatible; apiodactyl/1.0)"
+)
+
+// Code identifies why a fetch failed, so handlers can map it onto a status
+// and clients can react without parsing messages.
+type Code string
+
+const (
+	CodeInvalidURL       Code = "invalid_url"
+	CodeBlockedAddress   Code = "blocked_address"
+	CodeTooManyRedirects Code = "too_many_redirects"
+	CodeTimeout          Code = "timeout"
+	CodeRequestFailed    Code = "request_failed"
+	CodeBadStatus        Code = "bad_status"
+	CodeTooLarge         Code = "too_large"
+	CodeUnsupportedType  Code = "unsupported_type"
+)
+
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsClientError reports whether the failure was caused by the URL the caller
+// supplied rather than by the remote server misbehaving.
+func (e *Error) IsClientError() bool {
+	switch e.Code {
+	case CodeInvalidURL, CodeBlockedAddress, CodeTooLarge, CodeUnsupportedType:
+		return true
+	}
+	return false
+}
+
+// Result is a downloaded body whose type has been sniffed against
+// utils.AllowedMimeTypes.
+type Result struct {
+	Body        []byte
+	ContentType string
+	Ext         string
+	FinalURL    string
+}
+
+// Fetcher downloads remote files on behalf of users. Every connection,
+// including those made while following redirects, is checked against the IP
+// it actually dials, so DNS rebinding cannot smuggle in a private address.
+type Fetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+func New(cfg config.FetchConfig) *Fetcher {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+	}
+	if !cfg.AllowPrivate {
+		dialer.Control = blockPrivate
+	}
+
+	transport := &http.Transport{
+		// No Proxy: dialing a proxy would bypass the address check.
+		Proxy:                 nil,
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: cfg.Timeout(),
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	return &Fetcher{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout(),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return &Error{Code: CodeTooManyRedirects, Message: fmt.Sprintf("stopped after %d redirects", maxRedirects)}
+				}
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return &Error{Code: CodeInvalidURL, Message: fmt.Sprintf("redirect to unsupported scheme %q", req.URL.Scheme)}
+				}
+				req.Header.Set("User-Agent", userAgent)
+				return nil
+			},
+		},
+		maxBytes: cfg.MaxBytes,
+	}
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, rawURL string) (*Result, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, &Error{Code: CodeInvalidURL, Message: "only absolute http and https URLs can be fetched"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, &Error{Code: CodeInvalidURL, Message: "failed to create request", Err: err}
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, classify(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &Error{Code: CodeBadStatus, Message: fmt.Sprintf("remote server returned %s", resp.Status)}
+	}
+
+	if resp.ContentLength > f.maxBytes {
+		return nil, f.tooLarge()
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxBytes+1))
+	if err != nil {
+		return nil, classify(err)
+	}
+	if int64(len(body)) > f.maxBytes {
+		return nil, f.tooLarge()
+	}
+
+	contentType := http.DetectContentType(body)
+	ext, ok := utils.ExtensionForMimeType(contentType)
+	if !ok {
+		return nil, &Error{Code: CodeUnsupportedType, Message: fmt.Sprintf("content sniffed as %s, which is not an allowed type", contentType)}
+	}
+
+	return &Result{
+		Body:        body,
+		ContentType: contentType,
+		Ext:         ext,
+		FinalURL:    resp.Request.URL.String(),
+	}, nil
+}
+
+func (f *Fetcher) tooLarge() error {
+	return &Error{Code: CodeTooLarge, Message: fmt.Sprintf("response exceeds the %d byte limit", f.maxBytes)}
+}
+
+func classify(err error) error {
+	var fetchErr *Error
+	if errors.As(err, &fetchErr) {
+		return fetchErr
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return &Error{Code: CodeTimeout, Message: "remote server did not respond in time", Err: err}
+	}
+
+	return &Error{Code: CodeRequestFailed, Message: "request failed", Err: err}
+}
+
+// blockedPrefixes are ranges that are never reachable from the public
+// internet, on top of what netip's Is* helpers already cover.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// blockPrivate is a net.Dialer Control hook; address is the resolved IP the
+// dialer is about to connect to.
+func blockPrivate(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return &Error{Code: CodeBlockedAddress, Message: "unparseable address", Err: err}
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return &Error{Code: CodeBlockedAddress, Message: "unparseable address", Err: err}
+	}
+
+	if !IsPublicAddr(ip) {
+		return &Error{Code: CodeBlockedAddress, Message: fmt.Sprintf("%s is not a public address", ip)}
+	}
+	return nil
+}
+
+func IsPublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,205 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+)
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"127.8.8.8", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"100.64.0.1", false},
+		{"192.0.0.8", false},
+		{"198.18.0.1", false},
+		{"224.0.0.1", false},
+		{"240.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"ff02::1", false},
+		{"2001:db8::1", false},
+		{"64:ff9b::7f00:1", false},
+		// IPv4-mapped IPv6 addresses are judged by the IPv4 address.
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"::ffff:8.8.8.8", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := IsPublicAddr(netip.MustParseAddr(tt.addr)); got != tt.public {
+				t.Errorf("IsPublicAddr(%s) = %v, want %v", tt.addr, got, tt.public)
+			}
+		})
+	}
+}
+
+func TestBlockPrivate(t *testing.T) {
+	tests := []struct {
+		address string
+		blocked bool
+	}{
+		{"8.8.8.8:443", false},
+		{"[2606:4700:4700::1111]:443", false},
+		{"127.0.0.1:80", true},
+		{"[::1]:80", true},
+		{"[::ffff:192.168.0.1]:80", true},
+		{"169.254.169.254:80", true},
+		{"not-an-address", true},
+		{"example.com:80", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := blockPrivate("tcp", tt.address, nil)
+			if !tt.blocked {
+				if err != nil {
+					t.Errorf("blockPrivate(%s) = %v, want nil", tt.address, err)
+				}
+				return
+			}
+
+			var fetchErr *Error
+			if !errors.As(err, &fetchErr) || fetchErr.Code != CodeBlockedAddress {
+				t.Errorf("blockPrivate(%s) = %v, want a %s error", tt.address, err, CodeBlockedAddress)
+			}
+		})
+	}
+}
+
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// TestFetchBlocksPrivateAddresses runs against a loopback server, which the
+// fetcher must refuse to dial however the URL names it.
+func TestFetchBlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(testPNG(t))
+	}))
+	defer server.Close()
+
+	port := server.URL[strings.LastIndex(server.URL, ":"):]
+	f := New(config.FetchConfig{MaxBytes: 1 << 20, TimeoutSeconds: 5})
+
+	for _, rawURL := range []string{
+		server.URL,
+		"http://localhost" + port,
+		"http://[::ffff:127.0.0.1]" + port,
+	} {
+		t.Run(rawURL, func(t *testing.T) {
+			_, err := f.Fetch(context.Background(), rawURL)
+
+			var fetchErr *Error
+			if !errors.As(err, &fetchErr) || fetchErr.Code != CodeBlockedAddress {
+				t.Fatalf("Fetch(%s) = %v, want a %s error", rawURL, err, CodeBlockedAddress)
+			}
+			if !fetchErr.IsClientError() {
+				t.Errorf("blocked addresses should be the client's fault")
+			}
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	cover := testPNG(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cover.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(cover)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><body>not an image</body></html>"))
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(append(cover, make([]byte, 4096)...))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/cover.png", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/ftp", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "ftp://example.com/cover.png", http.StatusFound)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	// AllowPrivate lets the fetcher reach the loopback test server.
+	f := New(config.FetchConfig{MaxBytes: int64(len(cover)) + 1024, TimeoutSeconds: 5, AllowPrivate: true})
+
+	tests := []struct {
+		name string
+		url  string
+		code Code
+	}{
+		{"image", server.URL + "/cover.png", ""},
+		{"redirect", server.URL + "/redirect", ""},
+		{"not found", server.URL + "/missing", CodeBadStatus},
+		{"not an image", server.URL + "/page", CodeUnsupportedType},
+		{"too large", server.URL + "/large", CodeTooLarge},
+		{"redirect loop", server.URL + "/loop", CodeTooManyRedirects},
+		{"redirect to ftp", server.URL + "/ftp", CodeInvalidURL},
+		{"relative URL", "/cover.png", CodeInvalidURL},
+		{"file URL", "file:///etc/passwd", CodeInvalidURL},
+		{"no host", "http:///cover.png", CodeInvalidURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := f.Fetch(context.Background(), tt.url)
+			if tt.code == "" {
+				if err != nil {
+					t.Fatalf("Fetch: %v", err)
+				}
+				if result.ContentType != "image/png" || !bytes.Equal(result.Body, cover) {
+					t.Errorf("got %s with %d bytes, want the PNG", result.ContentType, len(result.Body))
+				}
+				if result.FinalURL != server.URL+"/cover.png" {
+					t.Errorf("FinalURL = %s", result.FinalURL)
+				}
+				return
+			}
+
+			var fetchErr *Error
+			if !errors.As(err, &fetchErr) || fetchErr.Code != tt.code {
+				t.Errorf("Fetch = %v, want a %s error", err, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,853 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/openapi"
+)
+
+// Response shapes that handlers build with gin.H, spelled out for the spec.
+type messageResponse struct {
+	Message string `json:"message"`
+}
+
+type createdResponse struct {
+	ID      int64  `json:"id"`
+	Message string `json:"message"`
+}
+
+type coverCreatedResponse struct {
+	ID         int64    `json:"id"`
+	CoverImage string   `json:"cover_image"`
+	Color      string   `json:"color"`
+	Palette    []string `json:"palette"`
+	BlurHash   string   `json:"blurhash"`
+	Message    string   `json:"message"`
+}
+
+type pageResponse[T any] struct {
+	Results []T `json:"results"`
+	Total   int `json:"total,omitempty"`
+	Limit   int `json:"limit"`
+	Offset  int `json:"offset"`
+	Count   int `json:"count"`
+}
+
+type restoreResponse struct {
+	ID         int64  `json:"id"`
+	RevisionID int64  `json:"revision_id"`
+	Message    string `json:"message"`
+}
+
+// fileQuery lists webp and avif with the formats we encode: they are
+// recognised, and answered with 406 rather than 400.
+type fileQuery struct {
+	Width  int    `form:"w" binding:"omitempty,oneof=160 320 640"`
+	Format string `form:"fmt" binding:"omitempty,oneof=jpeg jpg png webp avif"`
+}
+
+// apiDocs documents the routes registered in setupRouter. Keys are
+// "METHOD <gin path>"; routes missing here are still listed, just without
+// descriptions or schemas.
+var apiDocs = &openapi.Registry{
+	Title:   "Apiodactyl API",
+	Version: "1.0.0",
+	Groups: []openapi.Group{
+		{Key: "system", Name: "System", Description: "Health checks and API description"},
+		{Key: "auth", Name: "Authentication", Description: "User authentication and profile management"},
+		{Key: "resources", Name: "Resources", Description: "Manage generic resources"},
+		{Key: "games", Name: "Games", Description: "Manage game entries and play sessions"},
+		{Key: "books", Name: "Books", Description: "Manage book entries and reading sessions"},
+		{Key: "movies", Name: "Movies", Description: "Manage movie entries"},
+		{Key: "shows", Name: "Shows", Description: "Manage TV show entries"},
+		{Key: "albums", Name: "Albums", Description: "Manage music album entries"},
+		{Key: "anime", Name: "Anime", Description: "Manage anime entries and episode progress"},
+		{Key: "manga", Name: "Manga", Description: "Manage manga entries and chapter/volume progress"},
+		{Key: "tags", Name: "Tags", Description: "List, rename, merge and delete the tags on media entries"},
+		{Key: "genres", Name: "Genres", Description: "List, rename, merge and delete the genres on media entries"},
+		{Key: "series", Name: "Series", Description: "Group media entries of any type into numbered series and track progress through them"},
+		{Key: "collections", Name: "Collections", Description: "Curate ordered lists of media entries of any type"},
+		{Key: "comments", Name: "Comments", Description: "Manage comments on media entries"},
+		{Key: "trash", Name: "Trash", Description: "Restore or permanently delete trashed media entries and resources"},
+		{Key: "admin", Name: "Admin", Description: "User administration and the audit log"},
+		{Key: "files", Name: "Files", Description: "File upload and delivery"},
+		{Key: "import", Name: "Import", Description: "Bulk import from Goodreads, StoryGraph, Steam and Playnite exports"},
+		{Key: "export", Name: "Export", Description: "Export a catalog as JSON, CSV or Markdown and import it back"},
+		{Key: "metadata", Name: "Metadata", Description: "Look up entries in external catalogs such as Open Library and IGDB to fill in new ones"},
+	},
+	Operations: map[string]openapi.Operation{
+		"GET /health": {
+			Group:   "system",
+			Summary: "Health check",
+			Response: struct {
+				Status      string `json:"status"`
+				Environment string `json:"environment"`
+			}{},
+		},
+		"GET /api/v1/routes": {
+			Group:   "system",
+			Summary: "List every route, grouped",
+			Response: struct {
+				RouteGroups []models.RouteGroup `json:"route_groups"`
+				TotalGroups int                 `json:"total_groups"`
+			}{},
+		},
+		"GET /api/v1/openapi.json": {
+			Group:    "system",
+			Summary:  "OpenAPI 3.1 document generated from the live router",
+			Produces: "application/json",
+		},
+		"GET /api/v1/statuses": {
+			Group:       "system",
+			Summary:     "List the media status vocabulary and its allowed transitions",
+			Description: "types lists the statuses each media type allows, with the type's own label for each; an entry can only move to its type's statuses",
+			Response:    models.StatusVocabulary{},
+		},
+
+		"POST /api/v1/auth/register": {
+			Group:    "auth",
+			Summary:  "Register a new user account",
+			Request:  models.RegisterRequest{},
+			Response: models.AuthResponse{},
+			Status:   http.StatusCreated,
+		},
+		"POST /api/v1/auth/login": {
+			Group:    "auth",
+			Summary:  "Login with username and password",
+			Request:  models.LoginRequest{},
+			Response: models.AuthResponse{},
+		},
+		"POST /api/v1/auth/refresh": {
+			Group:    "auth",
+			Summary:  "Exchange a refresh token for a new access token and rotated refresh token",
+			Request:  models.RefreshRequest{},
+			Response: models.AuthResponse{},
+		},
+		"POST /api/v1/auth/password-reset": {
+			Group:    "auth",
+			Summary:  "Set a new password using an admin-issued reset token",
+			Request:  models.PasswordResetRequest{},
+			Response: messageResponse{},
+		},
+		"GET /api/v1/me": {
+			Group:    "auth",
+			Summary:  "Get current user profile",
+			Auth:     openapi.User,
+			Response: models.User{},
+		},
+		"POST /api/v1/auth/logout": {
+			Group:    "auth",
+			Summary:  "Revoke the current session",
+			Auth:     openapi.User,
+			Response: messageResponse{},
+		},
+		"POST /api/v1/auth/logout-all": {
+			Group:   "auth",
+			Summary: "Revoke every session of the current user",
+			Auth:    openapi.User,
+			Response: struct {
+				Message         string `json:"message"`
+				RevokedSessions int64  `json:"revoked_sessions"`
+			}{},
+		},
+
+		"GET /api/v1/resources/routes": {
+			Group:    "resources",
+			Summary:  "List the resource routes",
+			Auth:     openapi.User,
+			Response: models.RouteGroup{},
+		},
+		"GET /api/v1/resources": {
+			Group:    "resources",
+			Summary:  "Get all resources for the current user",
+			Auth:     openapi.User,
+			Response: []models.Resource{},
+		},
+		"GET /api/v1/resources/:id": {
+			Group:    "resources",
+			Summary:  "Get a specific resource by ID",
+			Auth:     openapi.User,
+			Response: models.Resource{},
+		},
+		"POST /api/v1/resources": {
+			Group:    "resources",
+			Summary:  "Create a new resource",
+			Auth:     openapi.Admin,
+			Request:  models.CreateResourceRequest{},
+			Response: createdResponse{},
+			Status:   http.StatusCreated,
+		},
+		"PUT /api/v1/resources/:id": {
+			Group:    "resources",
+			Summary:  "Update a resource by ID",
+			Auth:     openapi.Admin,
+			Request:  models.UpdateResourceRequest{},
+			Response: messageResponse{},
+		},
+		"DELETE /api/v1/resources/:id": {
+			Group:    "resources",
+			Summary:  "Move a resource to the trash",
+			Auth:     openapi.Admin,
+			Response: messageResponse{},
+		},
+
+		"GET /api/v1/series/routes": {
+			Group:    "series",
+			Summary:  "List the series routes",
+			Auth:     openapi.User,
+			Response: models.RouteGroup{},
+		},
+		"GET /api/v1/series": {
+			Group:    "series",
+			Summary:  "List your series by title, each with its progress",
+			Auth:     openapi.User,
+			Response: []models.Series{},
+		},
+		"GET /api/v1/series/:id": {
+			Group:       "series",
+			Summary:     "Get a series with its entries in order",
+			Description: "Get a series with its entries ordered by number and its progress (\"3 of 7 completed\"); trashed entries are left out until restored",
+			Auth:        openapi.User,
+			Response:    models.Series{},
+		},
+		"POST /api/v1/series": {
+			Group:    "series",
+			Summary:  "Create a series",
+			Auth:     openapi.Admin,
+			Request:  models.CreateSeriesRequest{},
+			Response: createdResponse{},
+			Status:   http.StatusCreated,
+		},
+		"PUT /api/v1/series/:id": {
+			Group:    "series",
+			Summary:  "Update a series",
+			Auth:     openapi.Admin,
+			Request:  models.UpdateSeriesRequest{},
+			Response: messageResponse{},
+		},
+		"DELETE /api/v1/series/:id": {
+			Group:    "series",
+			Summary:  "Delete a series (its entries are untouched)",
+			Auth:     openapi.Admin,
+			Response: messageResponse{},
+		},
+		"POST /api/v1/series/:id/entries": {
+			Group:       "series",
+			Summary:     "Add an entry to a series",
+			Description: "Add an entry of any type to a series; number defaults to one past the highest whole number in the series",
+			Auth:        openapi.Admin,
+			Request:     models.AddSeriesEntryRequest{},
+			Response: struct {
+				ID      int64   `json:"id"`
+				Number  float64 `json:"number"`
+				Message string  `json:"message"`
+			}{},
+			Status: http.StatusCreated,
+		},
+		"PUT /api/v1/series/:id/entries/:entry": {
+			Group:    "series",
+			Summary:  "Renumber an entry of a series",
+			Auth:     openapi.Admin,
+			Request:  models.UpdateSeriesEntryRequest{},
+			Response: messageResponse{},
+		},
+		"DELETE /api/v1/series/:id/entries/:entry": {
+			Group:    "series",
+			Summary:  "Remove an entry from a series",
+			Auth:     openapi.Admin,
+			Response: messageResponse{},
+		},
+
+		"GET /api/v1/collections/routes": {
+			Group:    "collections",
+			Summary:  "List the collection routes",
+			Auth:     openapi.User,
+			Response: models.RouteGroup{},
+		},
+		"GET /api/v1/collections": {
+			Group:    "collections",
+			Summary:  "List your collections, most recently updated first",
+			Auth:     openapi.User,
+			Query:    models.CollectionQuery{},
+			Response: pageResponse[models.Collection]{},
+		},
+		"GET /api/v1/collections/public": {
+			Group:    "collections",
+			Summary:  "List everyone's public collections, most recently updated first",
+			Auth:     openapi.User,
+			Query:    models.CollectionQuery{},
+			Response: pageResponse[models.Collection]{},
+		},
+		"GET /api/v1/collections/shared/:token": {
+			Group:       "collections",
+			Summary:     "Get a shared collection by its share token",
+			Description: "Get a public or unlisted collection, with its items, by its share token; private collections are never shared",
+			Auth:        openapi.User,
+			Response:    models.Collection{},
+		},
+		"GET /api/v1/collections/:id": {
+			Group:       "collections",
+			Summary:     "Get a collection with its items",
+			Description: "Get one of your collections, or someone else's public collection, with its items in order; trashed entries are left out until restored",
+			Auth:        openapi.User,
+			Response:    models.Collection{},
+		},
+		"POST /api/v1/collections": {
+			Group:       "collections",
+			Summary:     "Create a collection",
+			Description: "Create a collection; visibility is private (the default), unlisted (anyone with the share token) or public",
+			Auth:        openapi.User,
+			Request:     models.CreateCollectionRequest{},
+			Response: struct {
+				ID         int64  `json:"id"`
+				ShareToken string `json:"share_token"`
+				Message    string `json:"message"`
+			}{},
+			Status: http.StatusCreated,
+		},
+		"PUT /api/v1/collections/:id": {
+			Group:    "collections",
+			Summary:  "Update one of your collections",
+			Auth:     openapi.User,
+			Request:  models.UpdateCollectionRequest{},
+			Response: messageResponse{},
+		},
+		"DELETE /api/v1/collections/:id": {
+			Group:    "collections",
+			Summary:  "Delete one of your collections (its entries are untouched)",
+			Auth:     openapi.User,
+			Response: messageResponse{},
+		},
+		"POST /api/v1/collections/:id/items": {
+			Group:       "collections",
+			Summary:     "Add entries to a collection",
+			Description: "Append up to 100 entries in the order given; entries already in the collection are skipped",
+			Auth:        openapi.User,
+			Request:     models.AddCollectionItemsRequest{},
+			Response:    models.CollectionItemsResponse{},
+		},
+		"POST /api/v1/collections/:id/items/remove": {
+			Group:       "collections",
+			Summary:     "Remove entries from a collection",
+			Description: "Remove up to 100 entries by item_type and item_id; entries not in the collection are skipped",
+			Auth:        openapi.User,
+			Request:     models.RemoveCollectionItemsRequest{},
+			Response:    models.CollectionItemsResponse{},
+		},
+		"PUT /api/v1/collections/:id/items/order": {
+			Group:       "collections",
+			Summary:     "Reorder a collection",
+			Description: "Move the listed item IDs to the front in the order given; items left out keep their relative order after them",
+			Auth:        openapi.User,
+			Request:     models.ReorderCollectionRequest{},
+			Response:    messageResponse{},
+		},
+		"PUT /api/v1/collections/:id/items/:item": {
+			Group:    "collections",
+			Summary:  "Update the notes on a collection item",
+			Auth:     openapi.User,
+			Request:  models.UpdateCollectionItemRequest{},
+			Response: messageResponse{},
+		},
+
+		"GET /api/v1/comments/routes": {
+			Group:    "comments",
+			Summary:  "List the comment routes",
+			Auth:     openapi.User,
+			Response: models.RouteGroup{},
+		},
+		"POST /api/v1/comments": {
+			Group:       "comments",
+			Summary:     "Create a new comment on a media entry",
+			Description: "Create a new comment on the entry named by item_type and item_id (game_id or book_id also work)",
+			Auth:        openapi.User,
+			Request:     models.CreateCommentRequest{},
+			Response: struct {
+				ID        int64     `json:"id"`
+				CreatedAt time.Time `json:"created_at"`
+				UpdatedAt time.Time `json:"updated_at"`
+				Message   string    `json:"message"`
+			}{},
+			Status: http.StatusCreated,
+		},
+		"GET /api/v1/comments": {
+			Group:       "comments",
+			Summary:     "Get comments for a media entry",
+			Description: "Get comments for a media entry (requires item_type and item_id, or game_id or book_id)",
+			Auth:        openapi.User,
+			Query:       models.CommentQuery{},
+			Response:    []models.Comment{},
+		},
+		"PUT /api/v1/comments/:id": {
+			Group:    "comments",
+			Summary:  "Update your own comment (admins can update any comment)",
+			Auth:     openapi.User,
+			Request:  models.UpdateCommentRequest{},
+			Response: messageResponse{},
+		},
+		"DELETE /api/v1/comments/:id": {
+			Group:    "comments",
+			Summary:  "Delete your own comment (admins can delete any comment)",
+			Auth:     openapi.User,
+			Response: messageResponse{},
+		},
+
+		"GET /api/v1/games/:id/sessions": {
+			Group:    "games",
+			Summary:  "List a game's play sessions, most recent first",
+			Auth:     openapi.User,
+			Response: []models.PlaySession{},
+		},
+		"POST /api/v1/games/:id/sessions": {
+			Group:    "games",
+			Summary:  "Log a finished play session",
+			Auth:     openapi.Admin,
+			Request:  models.CreatePlaySessionRequest{},
+			Response: models.PlaySession{},
+			Status:   http.StatusCreated,
+		},
+		"POST /api/v1/games/:id/sessions/start": {
+			Group:       "games",
+			Summary:     "Start a play session",
+			Description: "Start a play session now, or at started_at; fails with 409 while another session of the game is running",
+			Auth:        openapi.Admin,
+			Request:     models.StartPlaySessionRequest{},
+			Response:    models.PlaySession{},
+			Status:      http.StatusCreated,
+		},
+		"POST /api/v1/games/:id/sessions/stop": {
+			Group:       "games",
+			Summary:     "Stop the running play session",
+			Description: "Stop the game's running play session now, or at ended_at; notes, if given, replace the session's",
+			Auth:        openapi.Admin,
+			Request:     models.StopPlaySessionRequest{},
+			Response:    models.PlaySession{},
+		},
+
+		"GET /api/v1/books/:id/sessions": {
+			Group:    "books",
+			Summary:  "List a book's reading sessions, most recent first",
+			Auth:     openapi.User,
+			Response: []models.ReadingSession{},
+		},
+		"POST /api/v1/books/:id/sessions": {
+			Group:       "books",
+			Summary:     "Log a reading session",
+			Description: "Log a reading session; start_page defaults to the book's current page, which moves on to end_page (reaching page_count marks the book completed)",
+			Auth:        openapi.Admin,
+			Request:     models.CreateReadingSessionRequest{},
+			Response:    models.ReadingSessionResponse{},
+			Status:      http.StatusCreated,
+		},
+
+		"GET /api/v1/trash/routes": {
+			Group:    "trash",
+			Summary:  "List the trash routes",
+			Auth:     openapi.User,
+			Response: models.RouteGroup{},
+		},
+		"GET /api/v1/trash": {
+			Group:       "trash",
+			Summary:     "List trashed entries, most recently deleted first",
+			Description: "List trashed entries, most recently deleted first, with the time each will be purged automatically",
+			Auth:        openapi.User,
+			Query:       models.TrashParams{},
+			Response:    []models.TrashItem{},
+		},
+		"DELETE /api/v1/trash": {
+			Group:   "trash",
+			Summary: "Permanently delete everything in the trash",
+			Auth:    openapi.Admin,
+			Response: struct {
+				Message string `json:"message"`
+				Purged  int64  `json:"purged"`
+			}{},
+		},
+		"POST /api/v1/trash/:type/:id/restore": {
+			Group:    "trash",
+			Summary:  "Restore a trashed game, book or resource",
+			Auth:     openapi.Admin,
+			Response: messageResponse{},
+		},
+		"DELETE /api/v1/trash/:type/:id": {
+			Group:    "trash",
+			Summary:  "Permanently delete a trashed game, book or resource",
+			Auth:     openapi.Admin,
+			Response: messageResponse{},
+		},
+
+		"GET /api/v1/admin/users": {
+			Group:    "admin",
+			Summary:  "List and search users",
+			Auth:     openapi.Admin,
+			Query:    models.UserSearchParams{},
+			Response: pageResponse[models.User]{},
+		},
+		"GET /api/v1/admin/users/:id": {
+			Group:   "admin",
+			Summary: "Get a user by ID",
+			Auth:    openapi.Admin,
+			Response: struct {
+				User           models.User `json:"user"`
+				ActiveSessions int         `json:"active_sessions"`
+			}{},
+		},
+		"PUT /api/v1/admin/users/:id/role": {
+			Group:   "admin",
+			Summary: "Change a user's role",
+			Auth:    openapi.Admin,
+			Request: models.UpdateUserRoleRequest{},
+			Response: struct {
+				Message string `json:"message"`
+				Role    string `json:"role"`
+			}{},
+		},
+		"POST /api/v1/admin/users/:id/disable": {
+			Group:    "admin",
+			Summary:  "Disable an account and revoke its sessions",
+			Auth:     openapi.Admin,
+			Response: messageResponse{},
+		},
+		"POST /api/v1/admin/users/:id/enable": {
+			Group:    "admin",
+			Summary:  "Re-enable a disabled account",
+			Auth:     openapi.Admin,
+			Response: messageResponse{},
+		},
+		"POST /api/v1/admin/users/:id/password-reset": {
+			Group:   "admin",
+			Summary: "Force a password reset and issue a one-time reset token",
+			Auth:    openapi.Admin,
+			Response: struct {
+				Message    string    `json:"message"`
+				ResetToken string    `json:"reset_token"`
+				ExpiresAt  time.Time `json:"expires_at"`
+			}{},
+		},
+		"DELETE /api/v1/admin/users/:id": {
+			Group:       "admin",
+			Summary:     "Delete a user account",
+			Description: "Responds 409 with the number of entries while the user still owns any, trashed ones included. With purge=true the entries are deleted for good along with the account, revisions and trash included.",
+			Auth:        openapi.Admin,
+			Query:       models.DeleteUserQuery{},
+			Response:    messageResponse{},
+		},
+		"GET /api/v1/admin/audit": {
+			Group:       "admin",
+			Summary:     "Search the audit log",
+			Description: "Search the audit log of mutating API calls, newest first, by actor, entity, action, status and time range (since/until, or from/to). Unknown parameters respond 400",
+			Auth:        openapi.Admin,
+			Query:       models.AuditSearchParams{},
+			Response:    pageResponse[models.AuditEntry]{},
+		},
+		"GET /api/v1/admin/audit/export": {
+			Group:       "admin",
+			Summary:     "Export the audit log as CSV",
+			Description: "Export every audit entry matching the filters as CSV, oldest first (limit and offset are ignored). Unknown parameters respond 400",
+			Auth:        openapi.Admin,
+			Query:       models.AuditSearchParams{},
+			Produces:    "text/csv",
+		},
+
+		"POST /api/v1/upload": {
+			Group:   "files",
+			Summary: "Upload a file (images, videos, audio)",
+			Auth:    openapi.Admin,
+			Upload:  "file",
+			Response: struct {
+				Message   string `json:"message"`
+				Filename  string `json:"filename"`
+				Permalink string `json:"permalink"`
+			}{},
+		},
+		"POST /api/v1/import": {
+			Group:       "import",
+			Summary:     "Import books or games from another service's export",
+			Description: "Import a Goodreads or StoryGraph CSV (books) or a Steam or Playnite library JSON (games). defaults is a JSON object of create request fields that fill in what rows leave empty. Rows matching an existing entry or an earlier row by title and author/developer are skipped as duplicates. dry_run previews every row with its validation errors; otherwise the rows are created in one transaction, and nothing is created while any row is invalid unless skip_invalid is set",
+			Auth:        openapi.Admin,
+			Upload:      "file",
+			Form:        models.ImportRequest{},
+			Response:    models.ImportResponse{},
+			Status:      http.StatusCreated,
+		},
+		"GET /api/v1/export": {
+			Group:       "export",
+			Summary:     "Export your catalog",
+			Description: "Download every entry you own, of every media type, with its links and comments. format=json (the default) has a versioned layout that POST /api/v1/import/catalog reads back; csv has a row per entry and md is a readable document. covers=true wraps the catalog in a zip archive as catalog.<format> with the stored cover files under covers/",
+			Auth:        openapi.User,
+			Query:       models.ExportQuery{},
+			Produces:    "application/json",
+		},
+		"POST /api/v1/import/catalog": {
+			Group:       "export",
+			Summary:     "Import a catalog export",
+			Description: "Import a JSON catalog export, or a zip export with its covers, into your catalog. Entries keep their timestamps, bundled covers are stored again and comments are restored as your own. Duplicates, dry_run and skip_invalid work as in POST /api/v1/import",
+			Auth:        openapi.Admin,
+			Upload:      "file",
+			Form:        models.CatalogImportRequest{},
+			Response:    models.CatalogImportResponse{},
+			Status:      http.StatusCreated,
+		},
+		"GET /api/v1/metadata/routes": {
+			Group:    "metadata",
+			Summary:  "List the metadata routes",
+			Auth:     openapi.User,
+			Response: models.RouteGroup{},
+		},
+		"GET /api/v1/metadata/providers": {
+			Group:    "metadata",
+			Summary:  "List the configured metadata providers",
+			Auth:     openapi.User,
+			Response: []models.MetadataProvider{},
+		},
+		"GET /api/v1/metadata/search": {
+			Group:       "metadata",
+			Summary:     "Search external metadata",
+			Description: "Search the providers for a media type by title (q), ISBN or a provider's own id. errors lists the providers that failed while others answered; 502 when all of them fail and 404 when the type has none",
+			Auth:        openapi.User,
+			Query:       models.MetadataSearchParams{},
+			Response:    models.MetadataSearchResponse{},
+		},
+		"GET /files/*key": {
+			Group:       "files",
+			Summary:     "Serve a stored file",
+			Description: "Serve a stored file; images accept ?w=160|320|640&fmt=jpeg|png for resized variants. WebP and AVIF cannot be encoded, so fmt=webp or fmt=avif responds 406 rather than serving another format; other unknown formats respond 400. Asking for a variant of a file that isn't an image responds 415",
+			Query:       fileQuery{},
+			Produces:    "application/octet-stream",
+			Errors:      []int{http.StatusNotAcceptable, http.StatusUnsupportedMediaType},
+		},
+		"HEAD /files/*key": {
+			Group:   "files",
+			Summary: "Check a stored file's size and type",
+		},
+	},
+}
+
+func init() {
+	addMediaDocs[models.Game](media.Game)
+	addMediaDocs[models.Book](media.Book)
+	addMediaDocs[models.Movie](media.Movie)
+	addMediaDocs[models.Show](media.Show)
+	addMediaDocs[models.Album](media.Album)
+	addMediaDocs[models.Anime](media.Anime)
+	addMediaDocs[models.Manga](media.Manga)
+	for _, taxonomy := range media.Taxonomies {
+		addTaxonomyDocs(taxonomy)
+	}
+}
+
+// addMediaDocs documents the routes setupRouter registers for a media type.
+// T is the type's model, needed to spell out the search page.
+func addMediaDocs[T any](t *media.Type) {
+	base := "/api/v1/" + t.Plural
+	plural := strings.ToUpper(t.Plural[:1]) + t.Plural[1:]
+	list := "Get" + plural
+	if plural == t.Label {
+		// "GetAnime" would clash with the single-entry operation.
+		list = "List" + plural
+	}
+
+	ops := map[string]openapi.Operation{
+		"GET " + base + "/routes": {
+			Summary:  "List the " + t.Name + " routes",
+			Auth:     openapi.User,
+			Response: models.RouteGroup{},
+		},
+		"GET " + base: {
+			Summary:     "Get all " + t.Plural + " for the current user",
+			Auth:        openapi.User,
+			Response:    []T{},
+			OperationID: list,
+		},
+		"GET " + base + "/search": {
+			Summary:     "Search " + t.Plural,
+			Description: "Search " + t.Plural + " with filters; q= runs a full-text query (\"phrases\", prefix*, AND/OR/NOT) and enables sort_by=relevance",
+			Auth:        openapi.User,
+			Query:       example(t.NewSearch()),
+			Response:    pageResponse[T]{},
+			OperationID: "Search" + plural,
+		},
+		"GET " + base + "/:id": {
+			Summary:     "Get a specific " + t.Name + " by ID",
+			Auth:        openapi.User,
+			Response:    example(t.New()),
+			OperationID: "Get" + t.Label,
+		},
+		"POST " + base: {
+			Summary:     "Create a new " + t.Name + " entry",
+			Description: "Create a new " + t.Name + " entry (supports cover_image file upload or cover_image_url; color defaults to the cover's dominant colour)",
+			Auth:        openapi.Admin,
+			Request:     example(t.NewCreate()),
+			Upload:      "cover_image",
+			Response:    coverCreatedResponse{},
+			Status:      http.StatusCreated,
+			OperationID: "Create" + t.Label,
+		},
+		"POST " + base + "/from-metadata": {
+			Summary:     "Create a " + t.Name + " entry from external metadata",
+			Description: "Create a " + t.Name + " entry from a metadata provider's candidate (see /api/v1/metadata/search). Any other create request fields in the body, such as rating, status and my_thoughts, fill in or override the candidate's; its cover is downloaded like a cover_image_url. 404 if the provider doesn't know the ID, 502 if it fails",
+			Auth:        openapi.Admin,
+			Request:     models.FromMetadataRequest{},
+			Response:    coverCreatedResponse{},
+			Status:      http.StatusCreated,
+			OperationID: "Create" + t.Label + "FromMetadata",
+		},
+		"PUT " + base + "/:id": {
+			Summary:     "Update a " + t.Name + " entry by ID",
+			Description: "Update a " + t.Name + " entry by ID; status changes must follow the transitions listed at /api/v1/statuses (409 otherwise), and statuses the type doesn't allow respond 400",
+			Auth:        openapi.Admin,
+			Request:     example(t.NewUpdate()),
+			Response:    messageResponse{},
+			OperationID: "Update" + t.Label,
+		},
+		"DELETE " + base + "/:id": {
+			Summary:     "Move a " + t.Name + " entry to the trash",
+			Auth:        openapi.Admin,
+			Response:    messageResponse{},
+			OperationID: "Delete" + t.Label,
+		},
+		"GET " + base + "/:id/revisions": {
+			Summary:     "List the revisions of a " + t.Name + ", newest first",
+			Auth:        openapi.User,
+			Response:    []models.Revision{},
+			OperationID: "List" + t.Label + "Revisions",
+		},
+		"GET " + base + "/:id/revisions/:rev": {
+			Summary:     "Get a " + t.Name + " revision with its full snapshot",
+			Auth:        openapi.User,
+			Response:    models.Revision{},
+			OperationID: "Get" + t.Label + "Revision",
+		},
+		"GET " + base + "/:id/status-history": {
+			Summary:     "List the status changes of a " + t.Name + ", oldest first",
+			Auth:        openapi.User,
+			Response:    []models.StatusChange{},
+			OperationID: "Get" + t.Label + "StatusHistory",
+		},
+		"GET " + base + "/:id/related": {
+			Summary:     "List the entries related to a " + t.Name + " and the series it belongs to",
+			Description: "Relations on both sides of the entry (direction is outgoing when this entry is <kind> the other), and each series it belongs to with the progress through it",
+			Auth:        openapi.User,
+			Response:    models.RelatedResponse{},
+			OperationID: "Get" + t.Label + "Related",
+		},
+		"POST " + base + "/:id/related": {
+			Summary:     "Relate a " + t.Name + " to another entry",
+			Description: "Record that this entry is <kind> (sequel_of, prequel_of, adaptation_of, same_series, dlc_of or remake_of) the entry named by item_type and item_id",
+			Auth:        openapi.Admin,
+			Request:     models.CreateRelationRequest{},
+			Response:    createdResponse{},
+			Status:      http.StatusCreated,
+			OperationID: "Create" + t.Label + "Relation",
+		},
+		"DELETE " + base + "/:id/related/:relation": {
+			Summary:     "Delete a relation of a " + t.Name,
+			Auth:        openapi.Admin,
+			Response:    messageResponse{},
+			OperationID: "Delete" + t.Label + "Relation",
+		},
+		"GET " + base + "/:id/diff": {
+			Summary:     "Compare two revisions of a " + t.Name,
+			Description: "Field-by-field diff between revisions from and to (to defaults to the latest revision)",
+			Auth:        openapi.User,
+			Query:       models.RevisionDiffParams{},
+			Response:    models.RevisionDiff{},
+			OperationID: "Diff" + t.Label + "Revisions",
+		},
+		"POST " + base + "/:id/revisions/:rev/restore": {
+			Summary:     "Roll a " + t.Name + " back to a revision",
+			Description: "Roll a " + t.Name + " back to a revision, taking it out of the trash or recreating it if it has been purged",
+			Auth:        openapi.Admin,
+			Response:    restoreResponse{},
+			OperationID: "Restore" + t.Label + "Revision",
+		},
+	}
+
+	if len(t.Counters) > 0 {
+		ops["POST "+base+"/:id/progress"] = openapi.Operation{
+			Summary:     "Update " + t.Name + " progress",
+			Description: "Set a progress counter (" + t.Units() + ") with current, or move it with increment; reaching a known total marks the entry completed",
+			Auth:        openapi.Admin,
+			Request:     models.ProgressRequest{},
+			Response:    models.ProgressResponse{},
+			OperationID: "Update" + t.Label + "Progress",
+		}
+
+		update := ops["PUT "+base+"/:id"]
+		update.Description += ". Progress counters are checked against the stored totals (400 when past them), and reaching a total marks the entry completed unless the update sets a status"
+		ops["PUT "+base+"/:id"] = update
+	}
+
+	for key, op := range ops {
+		op.Group = t.Plural
+		apiDocs.Operations[key] = op
+	}
+}
+
+// addTaxonomyDocs documents the routes setupRouter registers for a taxonomy.
+func addTaxonomyDocs(t *media.Taxonomy) {
+	base := "/api/v1/" + t.Plural
+	plural := strings.ToUpper(t.Plural[:1]) + t.Plural[1:]
+
+	ops := map[string]openapi.Operation{
+		"GET " + base + "/routes": {
+			Summary:  "List the " + t.Name + " routes",
+			Auth:     openapi.User,
+			Response: models.RouteGroup{},
+		},
+		"GET " + base: {
+			Summary:     "List the " + t.Plural + " in use, with usage counts",
+			Description: "List the " + t.Plural + " on your live entries by name, optionally only those starting with prefix or used by one media type",
+			Auth:        openapi.User,
+			Query:       models.TermQuery{},
+			Response:    []models.Term{},
+			OperationID: "List" + plural,
+		},
+		"GET " + base + "/autocomplete": {
+			Summary:     "Suggest " + t.Plural + " starting with a prefix, most used first",
+			Description: "Suggest " + t.Plural + " starting with prefix (case-insensitive), most used first; limit defaults to 10",
+			Auth:        openapi.User,
+			Query:       models.TermQuery{},
+			Response:    []models.Term{},
+			OperationID: "Autocomplete" + plural,
+		},
+		"POST " + base + "/merge": {
+			Summary:     "Merge " + t.Plural + " into one",
+			Description: "Replace the " + t.Plural + " in from with into on every entry, trashed ones included, and remove them",
+			Auth:        openapi.Admin,
+			Request:     models.MergeTermsRequest{},
+			Response:    models.TermChangeResponse{},
+			OperationID: "Merge" + plural,
+		},
+		"PUT " + base + "/:id": {
+			Summary:     "Rename a " + t.Name + " on every entry",
+			Description: "Rename a " + t.Name + " on every entry, trashed ones included; renaming onto an existing " + t.Name + " fails with 409 (merge them instead)",
+			Auth:        openapi.Admin,
+			Request:     models.RenameTermRequest{},
+			Response:    models.TermChangeResponse{},
+			OperationID: "Rename" + t.Label,
+		},
+		"DELETE " + base + "/:id": {
+			Summary:     "Remove a " + t.Name + " from every entry",
+			Auth:        openapi.Admin,
+			Response:    models.TermChangeResponse{},
+			OperationID: "Delete" + t.Label,
+		},
+	}
+
+	for key, op := range ops {
+		op.Group = t.Plural
+		apiDocs.Operations[key] = op
+	}
+}
+
+// example dereferences the pointers the media registry hands out, so the
+// spec shows the struct rather than a nullable reference to it.
+func example(v any) any {
+	return reflect.ValueOf(v).Elem().Interface()
+}
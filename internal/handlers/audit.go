@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+type AuditHandler struct {
+	db *database.DB
+}
+
+func NewAuditHandler(db *database.DB) *AuditHandler {
+	return &AuditHandler{db: db}
+}
+
+const auditColumns = `id, actor_id, COALESCE(actor_username, ''), COALESCE(actor_role, ''), ip, method, route, path,
+	action, COALESCE(entity_type, ''), COALESCE(entity_id, ''), before, after, status, created_at`
+
+func scanAuditEntry(row interface{ Scan(...any) error }, e *models.AuditEntry) error {
+	var before, after sql.NullString
+	err := row.Scan(&e.ID, &e.ActorID, &e.ActorUsername, &e.ActorRole, &e.IP, &e.Method, &e.Route, &e.Path,
+		&e.Action, &e.EntityType, &e.EntityID, &before, &after, &e.Status, &e.CreatedAt)
+	if before.Valid {
+		e.Before = []byte(before.String)
+	}
+	if after.Valid {
+		e.After = []byte(after.String)
+	}
+	return err
+}
+
+func (h *AuditHandler) ListAudit(c *gin.Context) {
+	var params models.AuditSearchParams
+	if err := bindAuditParams(c, &params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	where, args, err := auditFilters(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var total int
+	if err := h.db.QueryRowContext(c.Request.Context(), "SELECT COUNT(*) FROM audit_log WHERE "+where, args...).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count audit entries"})
+		return
+	}
+
+	limit := 50
+	if params.Limit > 0 && params.Limit <= 500 {
+		limit = params.Limit
+	}
+
+	offset := max(params.Offset, 0)
+
+	query := fmt.Sprintf(`SELECT %s FROM audit_log WHERE %s ORDER BY id DESC LIMIT ? OFFSET ?`, auditColumns, where)
+	rows, err := h.db.QueryContext(c.Request.Context(), query, append(args, limit, offset)...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit entries"})
+		return
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := scanAuditEntry(rows, &e); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to scan audit entry", err))
+			return
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating audit entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": entries,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"count":   len(entries),
+	})
+}
+
+// ExportAudit streams every entry matching the filters as CSV, oldest first.
+func (h *AuditHandler) ExportAudit(c *gin.Context) {
+	var params models.AuditSearchParams
+	if err := bindAuditParams(c, &params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	where, args, err := auditFilters(params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM audit_log WHERE %s ORDER BY id`, auditColumns, where)
+	rows, err := h.db.QueryContext(c.Request.Context(), query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit entries"})
+		return
+	}
+	defer rows.Close()
+
+	filename := fmt.Sprintf("audit-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{
+		"id", "created_at", "actor_id", "actor_username", "actor_role", "ip", "method", "route", "path",
+		"action", "entity_type", "entity_id", "status", "before", "after",
+	})
+
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := scanAuditEntry(rows, &e); err != nil {
+			// Headers are already sent; all that is left is to stop.
+			break
+		}
+
+		actorID := ""
+		if e.ActorID != nil {
+			actorID = strconv.FormatInt(*e.ActorID, 10)
+		}
+
+		w.Write([]string{
+			strconv.FormatInt(e.ID, 10), e.CreatedAt.UTC().Format(time.RFC3339), actorID, e.ActorUsername,
+			e.ActorRole, e.IP, e.Method, e.Route, e.Path, e.Action, e.EntityType, e.EntityID,
+			strconv.Itoa(e.Status), string(e.Before), string(e.After),
+		})
+	}
+
+	w.Flush()
+}
+
+// auditParams lists the query parameters the audit log accepts.
+var auditParams = queryParamNames(models.AuditSearchParams{})
+
+// bindAuditParams binds the audit filters, rejecting parameters it doesn't
+// know rather than ignoring them: a misspelled filter would otherwise quietly
+// widen the search to the whole log.
+func bindAuditParams(c *gin.Context, params *models.AuditSearchParams) error {
+	for name := range c.Request.URL.Query() {
+		if !auditParams[name] {
+			return fmt.Errorf("unknown filter %q", name)
+		}
+	}
+	if err := c.ShouldBindQuery(params); err != nil {
+		return err
+	}
+
+	for _, alias := range []struct {
+		value  *string
+		target *string
+		names  string
+	}{{&params.From, &params.Since, "from and since"}, {&params.To, &params.Until, "to and until"}} {
+		if *alias.value == "" {
+			continue
+		}
+		if *alias.target != "" {
+			return fmt.Errorf("use only one of %s", alias.names)
+		}
+		*alias.target = *alias.value
+	}
+	return nil
+}
+
+// queryParamNames collects the form tags of a query parameter struct.
+func queryParamNames(params any) map[string]bool {
+	names := map[string]bool{}
+	t := reflect.TypeOf(params)
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("form"), ","); name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// auditFilters turns the search parameters into a WHERE clause.
+func auditFilters(params models.AuditSearchParams) (string, []any, error) {
+	whereClauses := []string{"1 = 1"}
+	args := []any{}
+
+	if params.ActorID > 0 {
+		whereClauses = append(whereClauses, "actor_id = ?")
+		args = append(args, params.ActorID)
+	}
+
+	if params.Actor != "" {
+		whereClauses = append(whereClauses, "actor_username = ?")
+		args = append(args, params.Actor)
+	}
+
+	if params.EntityType != "" {
+		whereClauses = append(whereClauses, "entity_type = ?")
+		args = append(args, params.EntityType)
+	}
+
+	if params.EntityID != "" {
+		whereClauses = append(whereClauses, "entity_id = ?")
+		args = append(args, params.EntityID)
+	}
+
+	if params.Action != "" {
+		whereClauses = append(whereClauses, "action = ?")
+		args = append(args, params.Action)
+	}
+
+	if params.Method != "" {
+		whereClauses = append(whereClauses, "method = ?")
+		args = append(args, params.Method)
+	}
+
+	if params.Status > 0 {
+		whereClauses = append(whereClauses, "status = ?")
+		args = append(args, params.Status)
+	}
+
+	for _, bound := range []struct {
+		value string
+		op    string
+		name  string
+	}{{params.Since, ">=", "since"}, {params.Until, "<", "until"}} {
+		if bound.value == "" {
+			continue
+		}
+
+		t, err := parseAuditTime(bound.value, bound.op == "<")
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid %s (expected RFC 3339 or YYYY-MM-DD)", bound.name)
+		}
+
+		whereClauses = append(whereClauses, "created_at "+bound.op+" ?")
+		args = append(args, t.UTC().Format(sqliteTimestamp))
+	}
+
+	return strings.Join(whereClauses, " AND "), args, nil
+}
+
+// parseAuditTime accepts RFC 3339 timestamps and plain dates. A plain date
+// used as an upper bound covers the whole day.
+func parseAuditTime(value string, upper bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if upper {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
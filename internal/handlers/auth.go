@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
 	"github.com/thebearodactyl/apiodactyl/internal/database"
 	"github.com/thebearodactyl/apiodactyl/internal/middleware"
 	"github.com/thebearodactyl/apiodactyl/internal/models"
@@ -13,16 +20,18 @@ import (
 )
 
 type AuthHandler struct {
-	db              *database.DB
-	jwtSecret       string
-	expirationHours int
+	db         *database.DB
+	jwtSecret  string
+	accessTTL  time.Duration
+	refreshTTL time.Duration
 }
 
-func NewAuthHandler(db *database.DB, jwtSecret string, expirationHours int) *AuthHandler {
+func NewAuthHandler(db *database.DB, jwtSecret string, accessTTL time.Duration, refreshTTL time.Duration) *AuthHandler {
 	return &AuthHandler{
-		db:              db,
-		jwtSecret:       jwtSecret,
-		expirationHours: expirationHours,
+		db:         db,
+		jwtSecret:  jwtSecret,
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
 	}
 }
 
@@ -51,35 +60,36 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	token, expiresAt, err := middleware.GenerateToken(userID, req.Username, models.RoleNormal, h.jwtSecret, h.expirationHours)
+	audit.Target(c, "user", userID)
+
+	resp, err := h.startSession(c, models.UserInfo{
+		ID:       userID,
+		Username: req.Username,
+		Email:    req.Email,
+		Role:     models.RoleNormal,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.AuthResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		User: models.UserInfo{
-			ID:       userID,
-			Username: req.Username,
-			Email:    req.Email,
-			Role:     models.RoleNormal,
-		},
-	})
+	c.JSON(http.StatusCreated, resp)
 }
 
 func (h *AuthHandler) Login(c *gin.Context) {
+	audit.Action(c, audit.ActionLogin)
+
 	var req models.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	query := `SELECT id, username, email, password_hash, role FROM users WHERE username = ?`
+	query := `SELECT id, username, email, password_hash, role, disabled, password_reset_required FROM users WHERE username = ?`
 	var user models.User
 	err := h.db.QueryRowContext(c.Request.Context(), query, req.Username).Scan(
 		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role,
+		&user.Disabled, &user.PasswordResetRequired,
 	)
 
 	if err == sql.ErrNoRows {
@@ -92,27 +102,35 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	audit.Target(c, "user", user.ID)
+
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	token, expiresAt, err := middleware.GenerateToken(user.ID, user.Username, user.Role, h.jwtSecret, h.expirationHours)
+	if user.Disabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+		return
+	}
+
+	if user.PasswordResetRequired {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Password reset required"})
+		return
+	}
+
+	resp, err := h.startSession(c, models.UserInfo{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+		Role:     user.Role,
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.AuthResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		User: models.UserInfo{
-			ID:       user.ID,
-			Username: user.Username,
-			Email:    user.Email,
-			Role:     user.Role,
-		},
-	})
+	c.JSON(http.StatusOK, resp)
 }
 
 func (h *AuthHandler) GetProfile(c *gin.Context) {
@@ -122,10 +140,11 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 		return
 	}
 
-	query := `SELECT id, username, email, role, created_at FROM users WHERE id = ?`
+	query := `SELECT id, username, email, role, disabled, password_reset_required, created_at, updated_at FROM users WHERE id = ?`
 	var user models.User
 	err := h.db.QueryRowContext(c.Request.Context(), query, userID).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Role, &user.CreatedAt,
+		&user.ID, &user.Username, &user.Email, &user.Role,
+		&user.Disabled, &user.PasswordResetRequired, &user.CreatedAt, &user.UpdatedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -139,3 +158,284 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 	c.JSON(http.StatusOK, user)
 }
+
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	audit.Action(c, audit.ActionRefresh)
+
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+	defer tx.Rollback()
+
+	var tokenID int64
+	var sessionID string
+	var used, tokenValid, sessionActive bool
+	var user models.UserInfo
+	err = tx.QueryRowContext(ctx, `
+		SELECT rt.id, rt.session_id, rt.used_at IS NOT NULL, rt.expires_at > CURRENT_TIMESTAMP,
+		       s.revoked_at IS NULL AND s.expires_at > CURRENT_TIMESTAMP AND u.disabled = 0,
+		       u.id, u.username, u.email, u.role
+		FROM refresh_tokens rt
+		JOIN sessions s ON s.id = rt.session_id
+		JOIN users u ON u.id = s.user_id
+		WHERE rt.token_hash = ?
+	`, hashToken(req.RefreshToken)).Scan(
+		&tokenID, &sessionID, &used, &tokenValid, &sessionActive,
+		&user.ID, &user.Username, &user.Email, &user.Role,
+	)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	audit.Target(c, "user", user.ID)
+
+	if used {
+		// A rotated-out token coming back means it leaked; kill the whole family.
+		if _, err := tx.ExecContext(ctx, `UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL`, sessionID); err != nil || tx.Commit() != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token reuse detected, session revoked"})
+		return
+	}
+
+	if !tokenValid || !sessionActive {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token expired or session revoked"})
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = ?`, tokenID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	refreshToken, refreshExpiresAt, err := h.issueRefreshToken(c, tx, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	token, expiresAt, err := middleware.GenerateToken(user.ID, user.Username, user.Role, sessionID, h.jwtSecret, h.accessTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AuthResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             user,
+		Role:             user.Role,
+	})
+}
+
+func (h *AuthHandler) Logout(c *gin.Context) {
+	audit.Action(c, audit.ActionLogout)
+
+	sessionID, _ := c.Get("session_id")
+
+	_, err := h.db.ExecContext(c.Request.Context(),
+		`UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND revoked_at IS NULL`, sessionID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	audit.Action(c, audit.ActionLogout)
+
+	userID, _ := c.Get("user_id")
+
+	result, err := h.db.ExecContext(c.Request.Context(),
+		`UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL`, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log out"})
+		return
+	}
+
+	revoked, _ := result.RowsAffected()
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Logged out of all sessions",
+		"revoked_sessions": revoked,
+	})
+}
+
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	audit.Action(c, audit.ActionPasswordReset)
+
+	var req models.PasswordResetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+	defer tx.Rollback()
+
+	var resetID, userID int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, user_id FROM password_resets
+		WHERE token_hash = ? AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	`, hashToken(req.Token)).Scan(&resetID, &userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired reset token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	audit.Target(c, "user", userID)
+
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE password_resets SET used_at = CURRENT_TIMESTAMP WHERE id = ?`, []any{resetID}},
+		{`UPDATE users SET password_hash = ?, password_reset_required = 0, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, []any{string(hashedPassword), userID}},
+		{`UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL`, []any{userID}},
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully, please log in again"})
+}
+
+// startSession opens a new session family for user and returns an access
+// token bound to it along with the family's first refresh token.
+func (h *AuthHandler) startSession(c *gin.Context, user models.UserInfo) (models.AuthResponse, error) {
+	ctx := c.Request.Context()
+
+	sessionID, err := randomToken(16)
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO sessions (id, user_id, user_agent, ip_address, expires_at)
+		VALUES (?, ?, ?, ?, datetime('now', ?))
+	`, sessionID, user.ID, c.Request.UserAgent(), c.ClientIP(), sqliteOffset(h.refreshTTL))
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+
+	refreshToken, refreshExpiresAt, err := h.issueRefreshToken(c, tx, sessionID)
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.AuthResponse{}, err
+	}
+
+	token, expiresAt, err := middleware.GenerateToken(user.ID, user.Username, user.Role, sessionID, h.jwtSecret, h.accessTTL)
+	if err != nil {
+		return models.AuthResponse{}, err
+	}
+
+	return models.AuthResponse{
+		Token:            token,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             user,
+		Role:             user.Role,
+	}, nil
+}
+
+// issueRefreshToken stores a fresh refresh token for sessionID and slides the
+// session expiry forward to match it.
+func (h *AuthHandler) issueRefreshToken(c *gin.Context, tx *sql.Tx, sessionID string) (string, time.Time, error) {
+	ctx := c.Request.Context()
+
+	token, err := randomToken(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(h.refreshTTL)
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (session_id, token_hash, expires_at)
+		VALUES (?, ?, datetime('now', ?))
+	`, sessionID, hashToken(token), sqliteOffset(h.refreshTTL))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE sessions SET last_used_at = CURRENT_TIMESTAMP, expires_at = datetime('now', ?) WHERE id = ?
+	`, sqliteOffset(h.refreshTTL), sessionID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+func randomToken(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func sqliteOffset(d time.Duration) string {
+	return fmt.Sprintf("+%d seconds", int64(d.Seconds()))
+}
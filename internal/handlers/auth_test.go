@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/middleware"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+const testJWTSecret = "test-secret"
+
+// newAuthServer routes the auth endpoints the way main does.
+func newAuthServer(t *testing.T) (*gin.Engine, *database.DB) {
+	t.Helper()
+
+	db := newTestDB(t)
+	h := NewAuthHandler(db, testJWTSecret, 15*time.Minute, time.Hour)
+
+	r := gin.New()
+	r.POST("/auth/register", h.Register)
+	r.POST("/auth/login", h.Login)
+	r.POST("/auth/refresh", h.Refresh)
+	protected := r.Group("/", middleware.JWTAuth(testJWTSecret, db))
+	protected.GET("/auth/profile", h.GetProfile)
+	protected.POST("/auth/logout", h.Logout)
+	protected.POST("/auth/logout-all", h.LogoutAll)
+	return r, db
+}
+
+func serve(r *gin.Engine, method, path, token string, body any) *httptest.ResponseRecorder {
+	var reader bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&reader).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// signIn registers username on the first call and logs in after that, each
+// time opening a new session.
+func signIn(t *testing.T, r *gin.Engine, username string) models.AuthResponse {
+	t.Helper()
+
+	w := serve(r, http.MethodPost, "/auth/login", "", models.LoginRequest{Username: username, Password: "password123"})
+	if w.Code == http.StatusUnauthorized {
+		w = serve(r, http.MethodPost, "/auth/register", "", models.RegisterRequest{
+			Username: username, Email: username + "@example.com", Password: "password123",
+		})
+	}
+	if w.Code != http.StatusOK && w.Code != http.StatusCreated {
+		t.Fatalf("signing in %s: %d %s", username, w.Code, w.Body)
+	}
+
+	var resp models.AuthResponse
+	decodeBody(t, w, &resp)
+	return resp
+}
+
+func refresh(t *testing.T, r *gin.Engine, refreshToken string) (models.AuthResponse, int) {
+	t.Helper()
+
+	w := serve(r, http.MethodPost, "/auth/refresh", "", models.RefreshRequest{RefreshToken: refreshToken})
+	var resp models.AuthResponse
+	if w.Code == http.StatusOK {
+		decodeBody(t, w, &resp)
+	}
+	return resp, w.Code
+}
+
+func profileStatus(r *gin.Engine, token string) int {
+	return serve(r, http.MethodGet, "/auth/profile", token, nil).Code
+}
+
+func TestRefreshRotation(t *testing.T) {
+	r, db := newAuthServer(t)
+	first := signIn(t, r, "alice")
+
+	second, code := refresh(t, r, first.RefreshToken)
+	if code != http.StatusOK {
+		t.Fatalf("refresh: %d", code)
+	}
+	if second.RefreshToken == first.RefreshToken || second.Token == first.Token {
+		t.Error("refresh handed back the same tokens")
+	}
+	if second.User.Username != "alice" || second.Role != models.RoleNormal {
+		t.Errorf("refresh = %+v", second)
+	}
+
+	// Both access tokens belong to the same, still live, session.
+	for _, token := range []string{first.Token, second.Token} {
+		if code := profileStatus(r, token); code != http.StatusOK {
+			t.Errorf("profile: %d", code)
+		}
+	}
+	var sessions, tokens int
+	db.QueryRow(`SELECT COUNT(*) FROM sessions`).Scan(&sessions)
+	db.QueryRow(`SELECT COUNT(*) FROM refresh_tokens WHERE used_at IS NULL`).Scan(&tokens)
+	if sessions != 1 || tokens != 1 {
+		t.Errorf("%d sessions and %d unused refresh tokens, want one of each", sessions, tokens)
+	}
+
+	third, code := refresh(t, r, second.RefreshToken)
+	if code != http.StatusOK || third.RefreshToken == second.RefreshToken {
+		t.Fatalf("second refresh: %d", code)
+	}
+
+	if _, code := refresh(t, r, "not-a-token"); code != http.StatusUnauthorized {
+		t.Errorf("unknown refresh token: %d, want 401", code)
+	}
+}
+
+func TestRefreshReuseRevokesTheSession(t *testing.T) {
+	r, _ := newAuthServer(t)
+	first := signIn(t, r, "alice")
+	other := signIn(t, r, "alice")
+
+	second, code := refresh(t, r, first.RefreshToken)
+	if code != http.StatusOK {
+		t.Fatalf("refresh: %d", code)
+	}
+
+	// The rotated-out token comes back, so someone else has a copy.
+	if _, code := refresh(t, r, first.RefreshToken); code != http.StatusUnauthorized {
+		t.Fatalf("reused refresh token: %d, want 401", code)
+	}
+
+	// Everything in that family is dead: the latest refresh token and every
+	// access token issued for the session.
+	if _, code := refresh(t, r, second.RefreshToken); code != http.StatusUnauthorized {
+		t.Errorf("latest refresh token after reuse: %d, want 401", code)
+	}
+	for _, token := range []string{first.Token, second.Token} {
+		if code := profileStatus(r, token); code != http.StatusUnauthorized {
+			t.Errorf("access token after reuse: %d, want 401", code)
+		}
+	}
+
+	// Other sessions of the same user are left alone.
+	if code := profileStatus(r, other.Token); code != http.StatusOK {
+		t.Errorf("other session: %d, want 200", code)
+	}
+	if _, code := refresh(t, r, other.RefreshToken); code != http.StatusOK {
+		t.Errorf("other session's refresh: %d, want 200", code)
+	}
+}
+
+func TestRefreshDisabledUser(t *testing.T) {
+	r, db := newAuthServer(t)
+	session := signIn(t, r, "alice")
+
+	if _, err := db.Exec(`UPDATE users SET disabled = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if _, code := refresh(t, r, session.RefreshToken); code != http.StatusUnauthorized {
+		t.Errorf("refresh for a disabled user: %d, want 401", code)
+	}
+	if code := profileStatus(r, session.Token); code != http.StatusForbidden {
+		t.Errorf("access token of a disabled user: %d, want 403", code)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	r, _ := newAuthServer(t)
+	laptop := signIn(t, r, "alice")
+	phone := signIn(t, r, "alice")
+
+	if w := serve(r, http.MethodPost, "/auth/logout", laptop.Token, nil); w.Code != http.StatusOK {
+		t.Fatalf("logout: %d %s", w.Code, w.Body)
+	}
+
+	if code := profileStatus(r, laptop.Token); code != http.StatusUnauthorized {
+		t.Errorf("access token after logout: %d, want 401", code)
+	}
+	if _, code := refresh(t, r, laptop.RefreshToken); code != http.StatusUnauthorized {
+		t.Errorf("refresh after logout: %d, want 401", code)
+	}
+	if code := profileStatus(r, phone.Token); code != http.StatusOK {
+		t.Errorf("other session after logout: %d, want 200", code)
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	r, _ := newAuthServer(t)
+	laptop := signIn(t, r, "alice")
+	phone := signIn(t, r, "alice")
+	bob := signIn(t, r, "bob")
+
+	w := serve(r, http.MethodPost, "/auth/logout-all", laptop.Token, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("logout-all: %d %s", w.Code, w.Body)
+	}
+	var resp struct {
+		RevokedSessions int64 `json:"revoked_sessions"`
+	}
+	decodeBody(t, w, &resp)
+	if resp.RevokedSessions != 2 {
+		t.Errorf("revoked %d sessions, want 2", resp.RevokedSessions)
+	}
+
+	for _, session := range []models.AuthResponse{laptop, phone} {
+		if code := profileStatus(r, session.Token); code != http.StatusUnauthorized {
+			t.Errorf("access token after logout-all: %d, want 401", code)
+		}
+		if _, code := refresh(t, r, session.RefreshToken); code != http.StatusUnauthorized {
+			t.Errorf("refresh after logout-all: %d, want 401", code)
+		}
+	}
+	if code := profileStatus(r, bob.Token); code != http.StatusOK {
+		t.Errorf("another user's session: %d, want 200", code)
+	}
+}
@@ -0,0 +1,384 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// maxBundledCover caps the size of a cover file read from a zip export.
+const maxBundledCover = 32 << 20
+
+// ImportCatalog reads back a JSON catalog export, or a zip export with its
+// covers, into the current user's catalog.
+func (h *ImportHandler) ImportCatalog(c *gin.Context) {
+	var req models.CatalogImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	audit.Action(c, audit.ActionImport)
+	audit.Target(c, "catalog", "")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Failed to read file", err))
+		return
+	}
+	defer file.Close()
+
+	catalog, files, err := readCatalog(file, fileHeader.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Failed to read catalog", err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	opts := ImportOptions{DryRun: req.DryRun, SkipInvalid: req.SkipInvalid}
+	opts.UserID, _ = userID.(int64)
+
+	response, err := RunCatalogImport(c.Request.Context(), h.db, h.covers, catalog, files, opts)
+	if errors.Is(err, ErrInvalidRows) {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to import", err))
+		return
+	}
+
+	if response.Created == 0 {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	created := map[string][]int64{}
+	for _, row := range response.Rows {
+		if row.ID != nil {
+			created[row.ItemType] = append(created[row.ItemType], *row.ID)
+		}
+	}
+	audit.Change(c, nil, gin.H{"exported_by": catalog.Username, "created": created})
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// readCatalog reads a JSON export, or a zip export along with the files in
+// it by name. files is nil for a JSON export.
+func readCatalog(r io.ReaderAt, size int64) (*models.CatalogExport, map[string]*zip.File, error) {
+	var data io.Reader = io.NewSectionReader(r, 0, size)
+	var files map[string]*zip.File
+
+	magic := make([]byte, 4)
+	if n, _ := r.ReadAt(magic, 0); n == len(magic) && string(magic) == "PK\x03\x04" {
+		archive, err := zip.NewReader(r, size)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		files = map[string]*zip.File{}
+		for _, f := range archive.File {
+			files[f.Name] = f
+		}
+
+		f, ok := files["catalog.json"]
+		if !ok {
+			return nil, nil, errors.New("the archive has no catalog.json (only JSON exports can be imported)")
+		}
+		body, err := f.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer body.Close()
+		data = body
+	}
+
+	var catalog models.CatalogExport
+	if err := json.NewDecoder(data).Decode(&catalog); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if catalog.Version != models.ExportVersion {
+		return nil, nil, fmt.Errorf("unsupported export version %d (expected %d)", catalog.Version, models.ExportVersion)
+	}
+
+	return &catalog, files, nil
+}
+
+// RunCatalogImport checks every entry of a catalog export and, unless it is
+// a dry run, creates the valid ones that aren't duplicates in a single
+// transaction, the way RunImport does. Entries keep their timestamps and
+// their comments, which become the importing user's: the author named in the
+// file is never matched to an account here. Bundled covers are stored again,
+// and covers missing from files keep their cover_image.
+func RunCatalogImport(ctx context.Context, db *database.DB, pipeline *covers.Pipeline, catalog *models.CatalogExport, files map[string]*zip.File, opts ImportOptions) (*models.CatalogImportResponse, error) {
+	response := &models.CatalogImportResponse{
+		Version: catalog.Version,
+		DryRun:  opts.DryRun,
+		Total:   len(catalog.Entries),
+		Rows:    []models.ImportRow{},
+	}
+
+	existing := map[string]map[string]int64{}
+	for _, t := range media.Types {
+		entries, err := existingEntries(ctx, db, t, opts.UserID)
+		if err != nil {
+			return nil, err
+		}
+		existing[t.Name] = entries
+	}
+
+	type pending struct {
+		row   int
+		t     *media.Type
+		entry *models.ExportEntry
+		req   models.MediaCreate
+		cover *covers.Cover
+		file  *zip.File
+		color string
+	}
+	valid := []*pending{}
+	seen := map[string]bool{}
+	described := map[string]*covers.Cover{}
+	bundled := map[string]bool{}
+
+	for i := range catalog.Entries {
+		entry := &catalog.Entries[i]
+		result := models.ImportRow{Line: i + 1, ItemType: entry.ItemType, Title: entry.Title}
+		p := &pending{row: len(response.Rows), entry: entry}
+
+		t, ok := media.Lookup(entry.ItemType)
+		if !ok {
+			result.Errors = []string{fmt.Sprintf("item_type: must be one of %s", media.Names())}
+		} else {
+			fields := catalogFields(entry)
+			result.Entry = fields
+			p.t, p.req = t, t.NewCreate()
+			result.Errors = checkImportRow(t, fields, p.req)
+			p.file = files[entry.CoverFile]
+		}
+
+		if len(result.Errors) == 0 && p.file != nil {
+			if _, ok := utils.AllowedMimeTypes[strings.ToLower(path.Ext(p.file.Name))]; !ok {
+				result.Errors = append(result.Errors, "cover_file: unsupported file type")
+			} else if p.file.UncompressedSize64 > maxBundledCover {
+				result.Errors = append(result.Errors, "cover_file: too large")
+			}
+		}
+
+		if len(result.Errors) == 0 {
+			p.color = p.req.Common().Color
+			if p.file == nil {
+				url := p.req.Common().CoverImage
+				if described[url] == nil {
+					described[url] = pipeline.Describe(ctx, url)
+				}
+				p.cover = described[url]
+				if p.color == "" {
+					p.color = p.cover.Color()
+				}
+			}
+			if p.color == "" && p.file == nil {
+				result.Errors = append(result.Errors, "color is required when it cannot be derived from the cover image")
+			}
+		}
+
+		key := ""
+		if p.t != nil {
+			creator, _ := entry.Fields[p.t.Creator].(string)
+			key = importKey(entry.Title, creator)
+		}
+		switch {
+		case len(result.Errors) > 0:
+			result.Result = models.ImportInvalid
+			response.Invalid++
+		case existing[p.t.Name][key] != 0:
+			id := existing[p.t.Name][key]
+			result.Result = models.ImportDuplicate
+			result.DuplicateOf = &id
+			response.Duplicates++
+		case seen[p.t.Name+"\x00"+key]:
+			result.Result = models.ImportDuplicate
+			response.Duplicates++
+		default:
+			seen[p.t.Name+"\x00"+key] = true
+			result.Result = models.ImportValid
+			response.Valid++
+			valid = append(valid, p)
+
+			if p.file != nil && !bundled[p.file.Name] {
+				bundled[p.file.Name] = true
+				response.Covers++
+			}
+			response.Comments += len(entry.Comments)
+		}
+
+		response.Rows = append(response.Rows, result)
+	}
+
+	if opts.DryRun {
+		response.Message = fmt.Sprintf("Dry run: %d of %d entries would be imported", response.Valid, response.Total)
+		return response, nil
+	}
+
+	if response.Invalid > 0 && !opts.SkipInvalid {
+		response.Message = fmt.Sprintf("%d entries are invalid; nothing was imported (fix them or skip them with skip_invalid)", response.Invalid)
+		return response, ErrInvalidRows
+	}
+
+	// Covers are content-addressed, so storing them ahead of the transaction
+	// leaves nothing to clean up if it fails.
+	saved := map[string]*covers.Cover{}
+	for _, p := range valid {
+		if p.file == nil {
+			continue
+		}
+		if saved[p.file.Name] == nil {
+			cover, err := saveBundledCover(ctx, pipeline, p.file)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %w", response.Rows[p.row].Line, err)
+			}
+			saved[p.file.Name] = cover
+		}
+		p.cover = saved[p.file.Name]
+		if p.color == "" {
+			p.color = p.cover.Color()
+		}
+		if p.color == "" {
+			return nil, fmt.Errorf("entry %d: color is required when it cannot be derived from the cover image", response.Rows[p.row].Line)
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, p := range valid {
+		id, _, err := insertEntry(ctx, tx, p.t, p.req, p.cover, p.color, opts.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", response.Rows[p.row].Line, err)
+		}
+
+		if err := restoreExportTimes(ctx, tx, p.t, id, p.entry); err != nil {
+			return nil, fmt.Errorf("entry %d: %w", response.Rows[p.row].Line, err)
+		}
+
+		for _, comment := range p.entry.Comments {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO comments (content, item_type, item_id, user_id, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?)
+			`, comment.Content, p.t.Name, id, opts.UserID,
+				comment.CreatedAt.UTC().Format(sqliteTimestamp), comment.UpdatedAt.UTC().Format(sqliteTimestamp))
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: failed to create comment: %w", response.Rows[p.row].Line, err)
+			}
+		}
+
+		response.Rows[p.row].Result = models.ImportCreated
+		response.Rows[p.row].ID = &id
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	response.Created = len(valid)
+	response.Message = fmt.Sprintf("Imported %d of %d entries", response.Created, response.Total)
+	return response, nil
+}
+
+// catalogFields turns an exported entry back into the JSON fields of its
+// type's create request. Missing lists are taken as empty.
+func catalogFields(entry *models.ExportEntry) map[string]any {
+	fields := map[string]any{}
+	for key, value := range entry.Fields {
+		fields[key] = value
+	}
+
+	links := []map[string]any{}
+	for _, link := range entry.Links {
+		links = append(links, map[string]any{"key": link.Key, "value": link.Value})
+	}
+
+	fields["title"] = entry.Title
+	fields["genres"] = append([]string{}, entry.Genres...)
+	fields["tags"] = append([]string{}, entry.Tags...)
+	fields["rating"] = entry.Rating
+	fields["status"] = entry.Status
+	fields["description"] = entry.Description
+	fields["my_thoughts"] = entry.MyThoughts
+	fields["cover_image"] = entry.CoverImage
+	fields["explicit"] = entry.Explicit
+	fields["color"] = entry.Color
+	fields["links"] = links
+	return fields
+}
+
+// restoreExportTimes puts back the timestamps insertEntry replaced with the
+// current time. Entries without a created_at keep the new one.
+func restoreExportTimes(ctx context.Context, q database.Querier, t *media.Type, id int64, entry *models.ExportEntry) error {
+	if entry.CreatedAt.IsZero() {
+		return nil
+	}
+
+	updatedAt := entry.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = entry.CreatedAt
+	}
+
+	_, err := q.ExecContext(ctx, `UPDATE `+t.Table+` SET created_at = ?, updated_at = ?, started_at = ?, completed_at = ? WHERE id = ?`,
+		entry.CreatedAt.UTC().Format(sqliteTimestamp), updatedAt.UTC().Format(sqliteTimestamp),
+		exportTimestamp(entry.StartedAt), exportTimestamp(entry.CompletedAt), id)
+	return err
+}
+
+func exportTimestamp(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(sqliteTimestamp)
+}
+
+// saveBundledCover stores a cover file from a zip export.
+func saveBundledCover(ctx context.Context, pipeline *covers.Pipeline, f *zip.File) (*covers.Cover, error) {
+	body, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(body, maxBundledCover+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", f.Name, err)
+	}
+	if len(data) > maxBundledCover {
+		return nil, fmt.Errorf("%s is too large", f.Name)
+	}
+
+	ext := strings.ToLower(path.Ext(f.Name))
+	return pipeline.Save(ctx, bytes.NewReader(data), int64(len(data)), ext, utils.AllowedMimeTypes[ext])
+}
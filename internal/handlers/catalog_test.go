@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/fetcher"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/storage"
+)
+
+func newTestPipeline(t *testing.T) *covers.Pipeline {
+	t.Helper()
+
+	store, err := storage.NewLocalStore(filepath.Join(t.TempDir(), "files"), "/files/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return covers.New(store, fetcher.New(config.FetchConfig{}), "")
+}
+
+// exportCatalog runs the export endpoint for userID and returns the file.
+func exportCatalog(t *testing.T, db *database.DB, pipeline *covers.Pipeline, userID int64, query string) []byte {
+	t.Helper()
+
+	c, w := testContext(http.MethodGet, userID, models.RoleNormal, nil)
+	c.Request.URL.RawQuery = query
+	c.Set("username", "owner")
+	NewExportHandler(db, pipeline).Export(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("export: %d %s", w.Code, w.Body)
+	}
+	return w.Body.Bytes()
+}
+
+// exportedEntries loads every live entry of userID the way the export does,
+// without the IDs and cover paths that differ between databases.
+func exportedEntries(t *testing.T, db *database.DB, userID int64) []models.ExportEntry {
+	t.Helper()
+
+	entries := []models.ExportEntry{}
+	for _, typ := range media.Types {
+		loaded, err := loadExportEntries(context.Background(), db, typ, userID)
+		if err != nil {
+			t.Fatalf("loadExportEntries(%s): %v", typ.Name, err)
+		}
+		for _, entry := range loaded {
+			entries = append(entries, *entry)
+		}
+	}
+	return withoutIDs(entries)
+}
+
+func withoutIDs(entries []models.ExportEntry) []models.ExportEntry {
+	for i := range entries {
+		entries[i].ID = 0
+		entries[i].CoverFile = ""
+	}
+	return entries
+}
+
+func TestCatalogRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	const owner, fan = int64(1), int64(2)
+
+	source := newTestDB(t, "owner", "fan")
+	sourceCovers := newTestPipeline(t)
+
+	var buf bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for i := range img.Pix {
+		img.Pix[i] = 0x80
+	}
+	img.Set(0, 0, color.White)
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	cover, err := sourceCovers.Save(ctx, bytes.NewReader(buf.Bytes()), int64(buf.Len()), ".png", "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	game := newTestGame(t, source, owner, "Outer Wilds",
+		models.MediaLink{Key: "steam", Value: "https://store.steampowered.com/app/753640"},
+		models.MediaLink{Key: "site", Value: "https://www.mobiusdigitalgames.com"})
+	pages := 412
+	book := &models.CreateBookRequest{
+		CreateMediaRequest: models.CreateMediaRequest{
+			Title:       "Piranesi",
+			Genres:      models.StringArray{"fantasy"},
+			Tags:        models.StringArray{"favourite"},
+			Rating:      5,
+			Status:      models.StatusInProgress,
+			Description: "A house of endless halls",
+			MyThoughts:  "Strange and lovely",
+			Links:       []models.MediaLink{},
+			Explicit:    true,
+		},
+		Author:      "Susanna Clarke",
+		PageCount:   &pages,
+		CurrentPage: 120,
+	}
+	if _, _, err := insertEntry(ctx, source, media.Book, book, cover, cover.Color(), owner); err != nil {
+		t.Fatalf("insertEntry: %v", err)
+	}
+	trashed := newTestGame(t, source, owner, "Trashed")
+	newTestGame(t, source, fan, "Not mine")
+
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE games SET created_at = '2023-04-05 06:07:08', updated_at = '2023-05-06 07:08:09', started_at = '2023-04-06 00:00:00' WHERE id = ?`, []any{game}},
+		{`UPDATE games SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, []any{trashed}},
+		{`INSERT INTO comments (content, item_type, item_id, user_id, created_at, updated_at)
+			VALUES ('Great ending', 'game', ?, ?, '2023-06-01 10:00:00', '2023-06-02 11:00:00')`, []any{game, fan}},
+	} {
+		if _, err := source.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := exportedEntries(t, source, owner)
+	if len(want) != 2 {
+		t.Fatalf("exported %d entries, want the two live ones of the owner", len(want))
+	}
+
+	// Imported comments belong to the importer, named owner in the target.
+	imported := slices.Clone(want)
+	for i := range imported {
+		imported[i].Comments = slices.Clone(imported[i].Comments)
+		for j := range imported[i].Comments {
+			imported[i].Comments[j].Author = "owner"
+		}
+	}
+
+	tests := []struct {
+		name   string
+		query  string
+		covers int
+	}{
+		{name: "json", query: "format=json"},
+		{name: "zip with covers", query: "format=json&covers=true", covers: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			export := exportCatalog(t, source, sourceCovers, owner, tt.query)
+
+			catalog, files, err := readCatalog(bytes.NewReader(export), int64(len(export)))
+			if err != nil {
+				t.Fatalf("readCatalog: %v", err)
+			}
+			assertJSON(t, "catalog entries", withoutIDs(slices.Clone(catalog.Entries)), want)
+
+			// The target has an account named after the comment's author,
+			// which must not be given the comment.
+			target := newTestDB(t, "fan", "owner")
+			targetCovers := newTestPipeline(t)
+			opts := ImportOptions{UserID: 2}
+
+			dryRun := opts
+			dryRun.DryRun = true
+			preview, err := RunCatalogImport(ctx, target, targetCovers, catalog, files, dryRun)
+			if err != nil {
+				t.Fatalf("dry run: %v", err)
+			}
+			if preview.Valid != 2 || preview.Created != 0 || len(exportedEntries(t, target, 2)) != 0 {
+				t.Fatalf("dry run = %+v, want 2 valid and nothing created", preview)
+			}
+
+			response, err := RunCatalogImport(ctx, target, targetCovers, catalog, files, opts)
+			if err != nil {
+				t.Fatalf("RunCatalogImport: %v", err)
+			}
+			if response.Created != 2 || response.Comments != 1 || response.Covers != tt.covers {
+				t.Errorf("response = %+v", response)
+			}
+
+			assertJSON(t, "imported entries", exportedEntries(t, target, 2), imported)
+
+			var author int64
+			target.QueryRow(`SELECT user_id FROM comments`).Scan(&author)
+			if author != opts.UserID {
+				t.Errorf("comment author = %d, want the importer (%d)", author, opts.UserID)
+			}
+
+			if tt.covers > 0 {
+				var palette string
+				target.QueryRow(`SELECT palette FROM books`).Scan(&palette)
+				if palette == "[]" {
+					t.Error("the bundled cover was not analysed")
+				}
+				body, _, err := targetCovers.Open(ctx, cover.URL)
+				if err != nil {
+					t.Fatalf("bundled cover was not stored: %v", err)
+				}
+				body.Close()
+			}
+
+			// Importing again finds everything already there.
+			again, err := RunCatalogImport(ctx, target, targetCovers, catalog, files, opts)
+			if err != nil {
+				t.Fatalf("second import: %v", err)
+			}
+			if again.Duplicates != 2 || again.Created != 0 {
+				t.Errorf("second import = %+v, want 2 duplicates", again)
+			}
+		})
+	}
+}
+
+func TestExportJSONHeader(t *testing.T) {
+	db := newTestDB(t, "owner")
+	newTestGame(t, db, 1, "Outer Wilds")
+
+	export := exportCatalog(t, db, newTestPipeline(t), 1, "format=json")
+	var catalog models.CatalogExport
+	if err := json.Unmarshal(export, &catalog); err != nil {
+		t.Fatalf("invalid export: %v\n%s", err, export)
+	}
+	if catalog.Version != models.ExportVersion || catalog.Username != "owner" || catalog.ExportedAt.IsZero() || len(catalog.Entries) != 1 {
+		t.Errorf("catalog = %+v", catalog)
+	}
+}
+
+func TestExportFilename(t *testing.T) {
+	db := newTestDB(t, "owner")
+	newTestGame(t, db, 1, "Outer Wilds")
+
+	tests := []struct {
+		query     string
+		extension string
+	}{
+		{"format=json", ".json"},
+		{"format=csv", ".csv"},
+		{"covers=true", ".zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// Usernames are free text, so quotes and non-ASCII have to
+			// survive the Content-Disposition header.
+			c, w := testContext(http.MethodGet, 1, models.RoleNormal, nil)
+			c.Request.URL.RawQuery = tt.query
+			c.Set("username", `o"wner é`)
+			NewExportHandler(db, newTestPipeline(t)).Export(c)
+
+			disposition, params, err := mime.ParseMediaType(w.Header().Get("Content-Disposition"))
+			if err != nil || disposition != "attachment" {
+				t.Fatalf("Content-Disposition %q: %v", w.Header().Get("Content-Disposition"), err)
+			}
+			filename := params["filename"]
+			if !strings.HasPrefix(filename, `catalog-o"wner é-`) || !strings.HasSuffix(filename, tt.extension) {
+				t.Errorf("filename = %q", filename)
+			}
+		})
+	}
+}
@@ -0,0 +1,637 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+type CollectionHandler struct {
+	db *database.DB
+}
+
+func NewCollectionHandler(db *database.DB) *CollectionHandler {
+	return &CollectionHandler{db: db}
+}
+
+// collectionColumns counts only the items whose entries aren't trashed, to
+// agree with collectionItemsQuery.
+var collectionColumns = func() string {
+	cases := make([]string, 0, len(media.Types))
+	for _, t := range media.Types {
+		cases = append(cases, fmt.Sprintf(
+			"WHEN '%s' THEN EXISTS (SELECT 1 FROM %s t WHERE t.id = ci.item_id AND t.deleted_at IS NULL)", t.Name, t.Table))
+	}
+	return `c.id, c.user_id, u.username, c.title, c.description, c.cover_image, c.visibility, c.share_token,
+	(SELECT COUNT(*) FROM collection_items ci WHERE ci.collection_id = c.id AND CASE ci.item_type ` + strings.Join(cases, " ") + ` END),
+	c.created_at, c.updated_at`
+}()
+
+// collectionItemsQuery reads a collection's items with a summary of each
+// entry. Trashed entries drop out.
+var collectionItemsQuery = liveEntries(`
+			SELECT id, item_type, item_id, position, notes, added_at FROM collection_items WHERE collection_id = ?`) + `
+		ORDER BY position, id`
+
+func scanCollection(row interface{ Scan(...any) error }, col *models.Collection) error {
+	return row.Scan(&col.ID, &col.UserID, &col.Username, &col.Title, &col.Description, &col.CoverImage,
+		&col.Visibility, &col.ShareToken, &col.ItemCount, &col.CreatedAt, &col.UpdatedAt)
+}
+
+func (h *CollectionHandler) getCollection(ctx context.Context, q database.Querier, where string, args ...any) (*models.Collection, error) {
+	var col models.Collection
+	query := `SELECT ` + collectionColumns + ` FROM collections c JOIN users u ON c.user_id = u.id WHERE ` + where
+	if err := scanCollection(q.QueryRowContext(ctx, query, args...), &col); err != nil {
+		return nil, err
+	}
+	return &col, nil
+}
+
+func (h *CollectionHandler) collectionItems(ctx context.Context, id int64) ([]models.CollectionItem, error) {
+	rows, err := h.db.QueryContext(ctx, collectionItemsQuery, liveArgs(id)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.CollectionItem{}
+	for rows.Next() {
+		var item models.CollectionItem
+		if err := rows.Scan(&item.ID, &item.ItemType, &item.ItemID, &item.Position, &item.Notes, &item.AddedAt,
+			&item.Title, &item.CoverImage, &item.Status, &item.Rating); err != nil {
+			return nil, err
+		}
+		// Stored positions keep gaps left by removed entries; callers only
+		// ever see the order.
+		item.Position = len(items) + 1
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// ownCollection loads a collection owned by the current user, writing the
+// error response itself when there is none.
+func (h *CollectionHandler) ownCollection(c *gin.Context) (*models.Collection, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, false
+	}
+
+	audit.Target(c, "collection", id)
+
+	userID, _ := c.Get("user_id")
+
+	col, err := h.getCollection(c.Request.Context(), h.db, "c.id = ? AND c.user_id = ?", id, userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collection"})
+		return nil, false
+	}
+
+	return col, true
+}
+
+// ListCollections lists the current user's collections, newest first.
+func (h *CollectionHandler) ListCollections(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	h.listCollections(c, "c.user_id = ?", userID)
+}
+
+// ListPublicCollections lists everyone's public collections.
+func (h *CollectionHandler) ListPublicCollections(c *gin.Context) {
+	h.listCollections(c, "c.visibility = ?", models.VisibilityPublic)
+}
+
+func (h *CollectionHandler) listCollections(c *gin.Context, where string, args ...any) {
+	var params models.CollectionQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit := 50
+	if params.Limit > 0 && params.Limit <= 100 {
+		limit = params.Limit
+	}
+
+	offset := max(params.Offset, 0)
+
+	var total int
+	if err := h.db.QueryRowContext(c.Request.Context(), "SELECT COUNT(*) FROM collections c WHERE "+where, args...).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count collections"})
+		return
+	}
+
+	query := `SELECT ` + collectionColumns + ` FROM collections c JOIN users u ON c.user_id = u.id WHERE ` + where +
+		` ORDER BY c.updated_at DESC, c.id DESC LIMIT ? OFFSET ?`
+	rows, err := h.db.QueryContext(c.Request.Context(), query, append(args, limit, offset)...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collections"})
+		return
+	}
+	defer rows.Close()
+
+	userID, _ := c.Get("user_id")
+
+	collections := []models.Collection{}
+	for rows.Next() {
+		var col models.Collection
+		if err := scanCollection(rows, &col); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to scan collection", err))
+			return
+		}
+		if col.UserID != userID {
+			col.ShareToken = ""
+		}
+		collections = append(collections, col)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating collections"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": collections,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"count":   len(collections),
+	})
+}
+
+// GetCollection returns a collection with its items. Other users only see
+// public collections; unlisted ones are reached through GetSharedCollection.
+func (h *CollectionHandler) GetCollection(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	h.showCollection(c, "c.id = ? AND (c.user_id = ? OR c.visibility = ?)", id, userID, models.VisibilityPublic)
+}
+
+// GetSharedCollection returns a public or unlisted collection by its share
+// token. Private collections are never shared.
+func (h *CollectionHandler) GetSharedCollection(c *gin.Context) {
+	h.showCollection(c, "c.share_token = ? AND c.visibility != ?", c.Param("token"), models.VisibilityPrivate)
+}
+
+func (h *CollectionHandler) showCollection(c *gin.Context, where string, args ...any) {
+	col, err := h.getCollection(c.Request.Context(), h.db, where, args...)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Collection not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch collection"})
+		return
+	}
+
+	col.Items, err = h.collectionItems(c.Request.Context(), col.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch collection items", err))
+		return
+	}
+	if userID, _ := c.Get("user_id"); col.UserID != userID {
+		col.ShareToken = ""
+	}
+
+	c.JSON(http.StatusOK, col)
+}
+
+func (h *CollectionHandler) CreateCollection(c *gin.Context) {
+	var req models.CreateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Visibility == "" {
+		req.Visibility = models.VisibilityPrivate
+	}
+
+	token, err := randomToken(16)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create collection"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	query := `INSERT INTO collections (user_id, title, description, cover_image, visibility, share_token) VALUES (?, ?, ?, ?, ?, ?) RETURNING id`
+	var id int64
+	err = h.db.QueryRowContext(c.Request.Context(), query, userID, req.Title, req.Description, req.CoverImage, req.Visibility, token).Scan(&id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create collection"})
+		return
+	}
+
+	audit.Target(c, "collection", id)
+
+	after, _ := h.getCollection(c.Request.Context(), h.db, "c.id = ?", id)
+	audit.Change(c, nil, after)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":          id,
+		"share_token": token,
+		"message":     "Collection created successfully",
+	})
+}
+
+func (h *CollectionHandler) UpdateCollection(c *gin.Context) {
+	var req models.UpdateCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before, ok := h.ownCollection(c)
+	if !ok {
+		return
+	}
+
+	setClauses := []string{}
+	args := []any{}
+
+	if req.Title != "" {
+		setClauses = append(setClauses, "title = ?")
+		args = append(args, req.Title)
+	}
+	if req.Description != nil {
+		setClauses = append(setClauses, "description = ?")
+		args = append(args, *req.Description)
+	}
+	if req.CoverImage != nil {
+		setClauses = append(setClauses, "cover_image = ?")
+		args = append(args, *req.CoverImage)
+	}
+	if req.Visibility != "" {
+		setClauses = append(setClauses, "visibility = ?")
+		args = append(args, req.Visibility)
+	}
+
+	if len(setClauses) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	setClauses = append(setClauses, "updated_at = CURRENT_TIMESTAMP")
+	query := `UPDATE collections SET ` + strings.Join(setClauses, ", ") + ` WHERE id = ?`
+	if _, err := h.db.ExecContext(c.Request.Context(), query, append(args, before.ID)...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update collection"})
+		return
+	}
+
+	after, _ := h.getCollection(c.Request.Context(), h.db, "c.id = ?", before.ID)
+	audit.Change(c, before, after)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection updated successfully"})
+}
+
+// DeleteCollection removes a collection and its membership. The entries
+// themselves are untouched.
+func (h *CollectionHandler) DeleteCollection(c *gin.Context) {
+	before, ok := h.ownCollection(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.db.ExecContext(c.Request.Context(), `DELETE FROM collections WHERE id = ?`, before.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete collection"})
+		return
+	}
+
+	audit.Change(c, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted successfully"})
+}
+
+// AddItems appends entries to the end of a collection in the order given.
+// Entries already in the collection keep their place and notes.
+func (h *CollectionHandler) AddItems(c *gin.Context) {
+	var req models.AddCollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	targets, err := collectionTargets(req.Items)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	col, ok := h.ownCollection(c)
+	if !ok {
+		return
+	}
+
+	// Membership changes update the collection, whatever the method says.
+	audit.Action(c, audit.ActionUpdate)
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add items"})
+		return
+	}
+	defer tx.Rollback()
+
+	var position int
+	if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) FROM collection_items WHERE collection_id = ?`, col.ID).Scan(&position); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add items"})
+		return
+	}
+
+	added := 0
+	for i, item := range req.Items {
+		target := targets[i]
+
+		// Only the owner's own live entries can be added; trashed entries
+		// are hidden everywhere, and other users' entries are private.
+		var exists bool
+		err := tx.QueryRowContext(ctx, "SELECT 1 FROM "+target.Table+" WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
+			item.ItemID, col.UserID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s %d not found", target.Label, item.ItemID)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add items"})
+			return
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO collection_items (collection_id, item_type, item_id, position, notes) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (collection_id, item_type, item_id) DO NOTHING
+		`, col.ID, target.Name, item.ItemID, position+1, item.Notes)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add items"})
+			return
+		}
+
+		if n, _ := result.RowsAffected(); n > 0 {
+			position++
+			added++
+		}
+	}
+
+	if err := touchCollection(ctx, tx, col.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add items"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CollectionItemsResponse{
+		Added:   added,
+		Skipped: len(req.Items) - added,
+		Message: "Items added successfully",
+	})
+}
+
+// RemoveItems removes entries from a collection by item_type and item_id.
+// Entries that aren't in the collection are skipped.
+func (h *CollectionHandler) RemoveItems(c *gin.Context) {
+	var req models.RemoveCollectionItemsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	targets, err := collectionTargets(req.Items)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	col, ok := h.ownCollection(c)
+	if !ok {
+		return
+	}
+
+	audit.Action(c, audit.ActionUpdate)
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove items"})
+		return
+	}
+	defer tx.Rollback()
+
+	removed := 0
+	for i, item := range req.Items {
+		result, err := tx.ExecContext(ctx, `DELETE FROM collection_items WHERE collection_id = ? AND item_type = ? AND item_id = ?`,
+			col.ID, targets[i].Name, item.ItemID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove items"})
+			return
+		}
+
+		n, _ := result.RowsAffected()
+		removed += int(n)
+	}
+
+	if err := touchCollection(ctx, tx, col.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove items"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.CollectionItemsResponse{
+		Removed: removed,
+		Skipped: len(req.Items) - removed,
+		Message: "Items removed successfully",
+	})
+}
+
+// ReorderItems moves the listed items to the front of the collection in the
+// order given. Items left out keep their relative order after them.
+func (h *CollectionHandler) ReorderItems(c *gin.Context) {
+	var req models.ReorderCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	col, ok := h.ownCollection(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items"})
+		return
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM collection_items WHERE collection_id = ? ORDER BY position, id`, col.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items"})
+		return
+	}
+
+	current := []int64{}
+	members := map[int64]bool{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items"})
+			return
+		}
+		current = append(current, id)
+		members[id] = true
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items"})
+		return
+	}
+
+	order := make([]int64, 0, len(current))
+	seen := map[int64]bool{}
+	for _, id := range req.Items {
+		if !members[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Item %d is not in this collection", id)})
+			return
+		}
+		if seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Item %d is listed more than once", id)})
+			return
+		}
+		seen[id] = true
+		order = append(order, id)
+	}
+	for _, id := range current {
+		if !seen[id] {
+			order = append(order, id)
+		}
+	}
+
+	for i, id := range order {
+		if _, err := tx.ExecContext(ctx, `UPDATE collection_items SET position = ? WHERE id = ?`, i+1, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items"})
+			return
+		}
+	}
+
+	if err := touchCollection(ctx, tx, col.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reorder items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Items reordered successfully"})
+}
+
+// UpdateItem changes the notes on one item of a collection.
+func (h *CollectionHandler) UpdateItem(c *gin.Context) {
+	itemID, err := strconv.ParseInt(c.Param("item"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	var req models.UpdateCollectionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	col, ok := h.ownCollection(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE collection_items SET notes = ? WHERE id = ? AND collection_id = ?`, req.Notes, itemID, col.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+
+	if n, _ := result.RowsAffected(); n == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	if err := touchCollection(ctx, tx, col.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update item"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Item updated successfully"})
+}
+
+// collectionTargets resolves the media type of every referenced entry, in
+// order, before anything is written.
+func collectionTargets(items []models.CollectionItemRef) ([]*media.Type, error) {
+	targets := make([]*media.Type, len(items))
+	for i, item := range items {
+		target, ok := media.Lookup(item.ItemType)
+		if !ok {
+			return nil, fmt.Errorf("Invalid item_type %q (expected one of %s)", item.ItemType, media.Names())
+		}
+		if item.ItemID <= 0 {
+			return nil, fmt.Errorf("Invalid item_id %d", item.ItemID)
+		}
+		targets[i] = target
+	}
+	return targets, nil
+}
+
+func touchCollection(ctx context.Context, q database.Querier, id int64) error {
+	_, err := q.ExecContext(ctx, `UPDATE collections SET updated_at = CURRENT_TIMESTAMP WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// newTestCollection creates a collection for userID through the handler and
+// returns its ID and share token.
+func newTestCollection(t *testing.T, h *CollectionHandler, userID int64, title, visibility string) (int64, string) {
+	t.Helper()
+
+	w := call(h.CreateCollection, http.MethodPost, userID, models.RoleNormal, nil,
+		models.CreateCollectionRequest{Title: title, Visibility: visibility})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateCollection: %d %s", w.Code, w.Body)
+	}
+
+	var created struct {
+		ID         int64  `json:"id"`
+		ShareToken string `json:"share_token"`
+	}
+	decodeBody(t, w, &created)
+	return created.ID, created.ShareToken
+}
+
+func collectionParam(id int64) gin.Params {
+	return param("id", strconv.FormatInt(id, 10))
+}
+
+func gameRef(id int64) models.CollectionItemRef {
+	return models.CollectionItemRef{ItemType: "game", ItemID: id}
+}
+
+func collectionItemIDs(t *testing.T, db *database.DB, collectionID int64) []int64 {
+	t.Helper()
+
+	rows, err := db.Query(`SELECT item_id FROM collection_items WHERE collection_id = ? ORDER BY position`, collectionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		rows.Scan(&id)
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestAddItemsOwnEntriesOnly(t *testing.T) {
+	db := newTestDB(t, "owner", "other")
+	h := NewCollectionHandler(db)
+	const owner, other = int64(1), int64(2)
+
+	mine := newTestGame(t, db, owner, "Mine")
+	theirs := newTestGame(t, db, other, "Theirs")
+	trashed := newTestGame(t, db, owner, "Trashed")
+	if _, err := db.Exec(`UPDATE games SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, trashed); err != nil {
+		t.Fatal(err)
+	}
+	col, _ := newTestCollection(t, h, owner, "Favourites", models.VisibilityPublic)
+
+	tests := []struct {
+		name   string
+		id     int64
+		status int
+	}{
+		{"own entry", mine, http.StatusOK},
+		{"another user's entry", theirs, http.StatusNotFound},
+		{"trashed entry", trashed, http.StatusNotFound},
+		{"missing entry", 999, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := call(h.AddItems, http.MethodPost, owner, models.RoleNormal, collectionParam(col),
+				models.AddCollectionItemsRequest{Items: []models.CollectionItemRef{gameRef(tt.id)}})
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+		})
+	}
+
+	assertJSON(t, "items", collectionItemIDs(t, db, col), []int64{mine})
+}
+
+func TestCollectionVisibility(t *testing.T) {
+	db := newTestDB(t, "owner", "other")
+	h := NewCollectionHandler(db)
+	const owner, other = int64(1), int64(2)
+
+	public, publicToken := newTestCollection(t, h, owner, "Public", models.VisibilityPublic)
+	unlisted, unlistedToken := newTestCollection(t, h, owner, "Unlisted", models.VisibilityUnlisted)
+	private, privateToken := newTestCollection(t, h, owner, "Private", "")
+
+	get := func(id, userID int64) int {
+		return call(h.GetCollection, http.MethodGet, userID, models.RoleNormal, collectionParam(id), nil).Code
+	}
+	shared := func(token string) *httptest.ResponseRecorder {
+		return call(h.GetSharedCollection, http.MethodGet, other, models.RoleNormal, param("token", token), nil)
+	}
+
+	tests := []struct {
+		name    string
+		id      int64
+		token   string
+		byID    int
+		byToken int
+		byOwner int
+	}{
+		{"public", public, publicToken, http.StatusOK, http.StatusOK, http.StatusOK},
+		{"unlisted", unlisted, unlistedToken, http.StatusNotFound, http.StatusOK, http.StatusOK},
+		{"private", private, privateToken, http.StatusNotFound, http.StatusNotFound, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := get(tt.id, other); code != tt.byID {
+				t.Errorf("another user by ID: %d, want %d", code, tt.byID)
+			}
+			if code := get(tt.id, owner); code != tt.byOwner {
+				t.Errorf("owner by ID: %d, want %d", code, tt.byOwner)
+			}
+
+			w := shared(tt.token)
+			if w.Code != tt.byToken {
+				t.Fatalf("share token: %d, want %d", w.Code, tt.byToken)
+			}
+			if w.Code == http.StatusOK {
+				// The token is only ever shown to the owner.
+				var col models.Collection
+				decodeBody(t, w, &col)
+				if col.ID != tt.id || col.ShareToken != "" {
+					t.Errorf("shared collection = %+v", col)
+				}
+			}
+		})
+	}
+
+	// Only public collections are listed, to everyone.
+	w := call(h.ListPublicCollections, http.MethodGet, other, models.RoleNormal, nil, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListPublicCollections: %d %s", w.Code, w.Body)
+	}
+	var list struct {
+		Results []models.Collection `json:"results"`
+		Total   int                 `json:"total"`
+	}
+	decodeBody(t, w, &list)
+	if list.Total != 1 || len(list.Results) != 1 || list.Results[0].ID != public || list.Results[0].ShareToken != "" {
+		t.Errorf("public collections = %+v", list)
+	}
+
+	w = call(h.ListCollections, http.MethodGet, owner, models.RoleNormal, nil, nil)
+	decodeBody(t, w, &list)
+	if list.Total != 3 {
+		t.Errorf("owner's collections: %d, want 3", list.Total)
+	}
+	for _, col := range list.Results {
+		if col.ShareToken == "" {
+			t.Errorf("owner is missing the share token of %q", col.Title)
+		}
+	}
+}
+
+func TestAddItemsSkipsDuplicates(t *testing.T) {
+	db := newTestDB(t, "owner")
+	h := NewCollectionHandler(db)
+	const owner = int64(1)
+
+	a := newTestGame(t, db, owner, "A")
+	b := newTestGame(t, db, owner, "B")
+	c := newTestGame(t, db, owner, "C")
+	col, _ := newTestCollection(t, h, owner, "Queue", models.VisibilityPrivate)
+
+	add := func(refs ...models.CollectionItemRef) models.CollectionItemsResponse {
+		t.Helper()
+
+		w := call(h.AddItems, http.MethodPost, owner, models.RoleNormal, collectionParam(col),
+			models.AddCollectionItemsRequest{Items: refs})
+		if w.Code != http.StatusOK {
+			t.Fatalf("AddItems: %d %s", w.Code, w.Body)
+		}
+		var response models.CollectionItemsResponse
+		decodeBody(t, w, &response)
+		return response
+	}
+
+	first := gameRef(a)
+	first.Notes = "first pass"
+	if response := add(first, gameRef(b)); response.Added != 2 || response.Skipped != 0 {
+		t.Errorf("first add = %+v", response)
+	}
+
+	// Entries already in the collection keep their place and notes, and one
+	// listed twice in a request is only added once.
+	again := gameRef(a)
+	again.Notes = "second pass"
+	if response := add(gameRef(c), again, gameRef(c)); response.Added != 1 || response.Skipped != 2 {
+		t.Errorf("second add = %+v", response)
+	}
+
+	assertJSON(t, "items", collectionItemIDs(t, db, col), []int64{a, b, c})
+	var notes string
+	db.QueryRow(`SELECT notes FROM collection_items WHERE collection_id = ? AND item_id = ?`, col, a).Scan(&notes)
+	if notes != "first pass" {
+		t.Errorf("notes = %q, want the first ones", notes)
+	}
+}
+
+func TestReorderItems(t *testing.T) {
+	db := newTestDB(t, "owner")
+	h := NewCollectionHandler(db)
+	const owner = int64(1)
+
+	games := []int64{}
+	for _, title := range []string{"A", "B", "C", "D"} {
+		games = append(games, newTestGame(t, db, owner, title))
+	}
+	col, _ := newTestCollection(t, h, owner, "Queue", models.VisibilityPrivate)
+	refs := []models.CollectionItemRef{}
+	for _, id := range games {
+		refs = append(refs, gameRef(id))
+	}
+	if w := call(h.AddItems, http.MethodPost, owner, models.RoleNormal, collectionParam(col),
+		models.AddCollectionItemsRequest{Items: refs}); w.Code != http.StatusOK {
+		t.Fatalf("AddItems: %d %s", w.Code, w.Body)
+	}
+
+	// Reordering takes collection item IDs rather than entry IDs.
+	itemIDs := map[int64]int64{}
+	rows, err := db.Query(`SELECT id, item_id FROM collection_items WHERE collection_id = ?`, col)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id, itemID int64
+		rows.Scan(&id, &itemID)
+		itemIDs[itemID] = id
+	}
+	rows.Close()
+	item := func(i int) int64 { return itemIDs[games[i]] }
+
+	reorder := func(items ...int64) *httptest.ResponseRecorder {
+		return call(h.ReorderItems, http.MethodPut, owner, models.RoleNormal, collectionParam(col),
+			models.ReorderCollectionRequest{Items: items})
+	}
+
+	// Items left out keep their relative order after the listed ones.
+	if w := reorder(item(2), item(0)); w.Code != http.StatusOK {
+		t.Fatalf("ReorderItems: %d %s", w.Code, w.Body)
+	}
+	assertJSON(t, "after a partial reorder", collectionItemIDs(t, db, col), []int64{games[2], games[0], games[1], games[3]})
+
+	if w := reorder(item(3), item(2), item(1), item(0)); w.Code != http.StatusOK {
+		t.Fatalf("ReorderItems: %d %s", w.Code, w.Body)
+	}
+	assertJSON(t, "after a full reorder", collectionItemIDs(t, db, col), []int64{games[3], games[2], games[1], games[0]})
+
+	for name, items := range map[string][]int64{
+		"unknown item":  {item(0), 999},
+		"repeated item": {item(1), item(1)},
+	} {
+		if w := reorder(items...); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: %d, want 400", name, w.Code)
+		}
+	}
+	assertJSON(t, "after refused reorders", collectionItemIDs(t, db, col), []int64{games[3], games[2], games[1], games[0]})
+}
+
+func TestTrashAndPurgeCollectionItems(t *testing.T) {
+	db := newTestDB(t, "owner")
+	h := NewCollectionHandler(db)
+	trashHandler := NewTrashHandler(db, time.Hour)
+	const owner = int64(1)
+
+	kept := newTestGame(t, db, owner, "Kept")
+	purged := newTestGame(t, db, owner, "Purged")
+	col, _ := newTestCollection(t, h, owner, "Favourites", models.VisibilityPrivate)
+	if w := call(h.AddItems, http.MethodPost, owner, models.RoleNormal, collectionParam(col),
+		models.AddCollectionItemsRequest{Items: []models.CollectionItemRef{gameRef(kept), gameRef(purged)}}); w.Code != http.StatusOK {
+		t.Fatalf("AddItems: %d %s", w.Code, w.Body)
+	}
+
+	shown := func() (int, []int64) {
+		t.Helper()
+
+		w := call(h.GetCollection, http.MethodGet, owner, models.RoleNormal, collectionParam(col), nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GetCollection: %d %s", w.Code, w.Body)
+		}
+		var got models.Collection
+		decodeBody(t, w, &got)
+		ids := []int64{}
+		for _, item := range got.Items {
+			ids = append(ids, item.ItemID)
+		}
+		return got.ItemCount, ids
+	}
+
+	// A trashed entry drops out of the collection but keeps its membership,
+	// so restoring it puts it back.
+	if _, err := db.Exec(`UPDATE games SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, purged); err != nil {
+		t.Fatal(err)
+	}
+	if count, ids := shown(); count != 1 || len(ids) != 1 || ids[0] != kept {
+		t.Errorf("with a trashed entry: count %d, items %v", count, ids)
+	}
+	assertJSON(t, "stored items", collectionItemIDs(t, db, col), []int64{kept, purged})
+
+	w := call(trashHandler.PurgeTrashItem, http.MethodDelete, owner, models.RoleNormal,
+		param("type", "game", "id", strconv.FormatInt(purged, 10)), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PurgeTrashItem: %d %s", w.Code, w.Body)
+	}
+	assertJSON(t, "stored items after purge", collectionItemIDs(t, db, col), []int64{kept})
+}
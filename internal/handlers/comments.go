@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
 	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
 	"github.com/thebearodactyl/apiodactyl/internal/models"
 )
 
@@ -24,27 +29,37 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		return
 	}
 
-	if req.GameID == nil && req.BookID == nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Either game_id or book_id must be provided"})
+	target, targetID, err := commentTarget(req.ItemType, req.ItemID, req.GameID, req.BookID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if req.GameID != nil && req.BookID != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot comment on both game and book at the same time"})
+	userID, _ := c.Get("user_id")
+
+	// Trashed entries are hidden everywhere, so they take no new comments.
+	var exists bool
+	err = h.db.QueryRowContext(c.Request.Context(), "SELECT 1 FROM "+target.Table+" WHERE id = ? AND deleted_at IS NULL", targetID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": target.Label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
 		return
 	}
 
-	userID, _ := c.Get("user_id")
-
-	query := `INSERT INTO comments (content, game_id, book_id, user_id) VALUES (?, ?, ?, ?) RETURNING id, created_at, updated_at`
+	query := `INSERT INTO comments (content, item_type, item_id, user_id) VALUES (?, ?, ?, ?) RETURNING id, created_at, updated_at`
 	var id int64
 	var createdAt, updatedAt string
-	err := h.db.QueryRowContext(c.Request.Context(), query, req.Content, req.GameID, req.BookID, userID).Scan(&id, &createdAt, &updatedAt)
+	err = h.db.QueryRowContext(c.Request.Context(), query, req.Content, target.Name, targetID, userID).Scan(&id, &createdAt, &updatedAt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
 		return
 	}
 
+	audit.Target(c, "comment", id)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"id":         id,
 		"created_at": createdAt,
@@ -54,38 +69,28 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 }
 
 func (h *CommentHandler) GetComments(c *gin.Context) {
-	gameID := c.Query("game_id")
-	bookID := c.Query("book_id")
-
-	if gameID == "" && bookID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Either game_id or book_id query parameter is required"})
+	var params models.CommentQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	var query string
-	var args []any
-
-	if gameID != "" {
-		query = `
-			SELECT c.id, c.content, c.game_id, c.book_id, c.user_id, u.username, c.created_at, c.updated_at
-			FROM comments c
-			JOIN users u ON c.user_id = u.id
-			WHERE c.game_id = ?
-			ORDER BY c.created_at DESC
-		`
-		args = append(args, gameID)
-	} else {
-		query = `
-			SELECT c.id, c.content, c.game_id, c.book_id, c.user_id, u.username, c.created_at, c.updated_at
-			FROM comments c
-			JOIN users u ON c.user_id = u.id
-			WHERE c.book_id = ?
-			ORDER BY c.created_at DESC
-		`
-		args = append(args, bookID)
+	target, targetID, err := commentTarget(params.ItemType, params.ItemID, params.GameID, params.BookID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	rows, err := h.db.QueryContext(c.Request.Context(), query, args...)
+	query := `
+		SELECT c.id, c.content, c.item_type, c.item_id, c.user_id, u.username, c.created_at, c.updated_at
+		FROM comments c
+		JOIN users u ON c.user_id = u.id
+		JOIN ` + target.Table + ` t ON c.item_id = t.id AND t.deleted_at IS NULL
+		WHERE c.item_type = ? AND c.item_id = ?
+		ORDER BY c.created_at DESC
+	`
+
+	rows, err := h.db.QueryContext(c.Request.Context(), query, target.Name, targetID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
 		return
@@ -95,17 +100,58 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 	comments := []models.Comment{}
 	for rows.Next() {
 		var comment models.Comment
-		if err := rows.Scan(&comment.ID, &comment.Content, &comment.GameID, &comment.BookID,
+		if err := rows.Scan(&comment.ID, &comment.Content, &comment.ItemType, &comment.ItemID,
 			&comment.UserID, &comment.Username, &comment.CreatedAt, &comment.UpdatedAt); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan comment"})
 			return
 		}
+
+		switch comment.ItemType {
+		case "game":
+			comment.GameID = &comment.ItemID
+		case "book":
+			comment.BookID = &comment.ItemID
+		}
 		comments = append(comments, comment)
 	}
 
 	c.JSON(http.StatusOK, comments)
 }
 
+// commentTarget resolves the entry a comment request refers to, given either
+// item_type and item_id or one of the game_id and book_id shorthands.
+func commentTarget(itemType string, itemID int64, gameID, bookID *int64) (*media.Type, int64, error) {
+	given := 0
+	if itemType != "" || itemID != 0 {
+		given++
+	}
+	if gameID != nil {
+		given++
+		itemType, itemID = "game", *gameID
+	}
+	if bookID != nil {
+		given++
+		itemType, itemID = "book", *bookID
+	}
+
+	switch {
+	case given == 0:
+		return nil, 0, errors.New("Either item_type and item_id, game_id or book_id must be provided")
+	case given > 1:
+		return nil, 0, errors.New("Cannot comment on more than one entry at the same time")
+	}
+
+	target, ok := media.Lookup(itemType)
+	if !ok {
+		return nil, 0, fmt.Errorf("Invalid item_type (expected one of %s)", media.Names())
+	}
+	if itemID <= 0 {
+		return nil, 0, errors.New("item_id is required")
+	}
+
+	return target, itemID, nil
+}
+
 func (h *CommentHandler) UpdateComment(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
@@ -113,6 +159,8 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 		return
 	}
 
+	audit.Target(c, "comment", id)
+
 	var req models.UpdateCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -155,6 +203,8 @@ func (h *CommentHandler) DeleteComment(c *gin.Context) {
 		return
 	}
 
+	audit.Target(c, "comment", id)
+
 	userID, _ := c.Get("user_id")
 	userRole, _ := c.Get("user_role")
 
@@ -0,0 +1,518 @@
+package handlers
+
+import (
+	"archive/zip"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+type ExportHandler struct {
+	db     *database.DB
+	covers *covers.Pipeline
+}
+
+func NewExportHandler(db *database.DB, pipeline *covers.Pipeline) *ExportHandler {
+	return &ExportHandler{db: db, covers: pipeline}
+}
+
+var exportContentTypes = map[string]string{
+	models.ExportJSON:     "application/json; charset=utf-8",
+	models.ExportCSV:      "text/csv; charset=utf-8",
+	models.ExportMarkdown: "text/markdown; charset=utf-8",
+}
+
+// Export streams the user's catalog, every live entry of every media type
+// with its links and comments, as a download. With covers=true the catalog
+// goes into a zip archive as catalog.<format>, next to the cover files we
+// store under covers/.
+func (h *ExportHandler) Export(c *gin.Context) {
+	var params models.ExportQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format := params.Format
+	if format == "" {
+		format = models.ExportJSON
+	}
+
+	userID, _ := c.Get("user_id")
+	export := &models.CatalogExport{
+		Version:    models.ExportVersion,
+		ExportedAt: time.Now().UTC().Truncate(time.Second),
+		Username:   c.GetString("username"),
+	}
+
+	extension := format
+	if params.Covers {
+		extension = "zip"
+		c.Header("Content-Type", "application/zip")
+	} else {
+		c.Header("Content-Type", exportContentTypes[format])
+	}
+	filename := fmt.Sprintf("catalog-%s-%s.%s", export.Username, export.ExportedAt.Format("20060102-150405"), extension)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so from here on all that is left on an error
+	// is to stop, which leaves a truncated file or archive.
+	var w io.Writer = c.Writer
+	var archive *zip.Writer
+	if params.Covers {
+		archive = zip.NewWriter(c.Writer)
+		var err error
+		w, err = archive.CreateHeader(&zip.FileHeader{
+			Name:     "catalog." + format,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	var catalog catalogWriter
+	switch format {
+	case models.ExportCSV:
+		catalog = &csvCatalog{w: csv.NewWriter(w)}
+	case models.ExportMarkdown:
+		catalog = &markdownCatalog{w: w}
+	default:
+		catalog = &jsonCatalog{w: w}
+	}
+
+	ctx := c.Request.Context()
+	if err := catalog.begin(export); err != nil {
+		return
+	}
+
+	bundled := []string{}
+	for _, t := range media.Types {
+		entries, err := loadExportEntries(ctx, h.db, t, userID)
+		if err != nil {
+			return
+		}
+
+		for _, entry := range entries {
+			if archive != nil {
+				if key, ok := h.covers.Key(entry.CoverImage); ok {
+					entry.CoverFile = "covers/" + key
+					bundled = append(bundled, entry.CoverImage)
+				}
+			}
+			if err := catalog.entry(t, entry); err != nil {
+				return
+			}
+		}
+	}
+
+	if err := catalog.end(); err != nil || archive == nil {
+		return
+	}
+
+	written := map[string]bool{}
+	for _, url := range bundled {
+		if written[url] {
+			continue
+		}
+		written[url] = true
+
+		if err := h.bundleCover(ctx, archive, url); err != nil {
+			return
+		}
+	}
+
+	archive.Close()
+}
+
+// bundleCover copies a stored cover into the archive. A cover whose file has
+// gone missing is left out; importing falls back to its cover_image.
+func (h *ExportHandler) bundleCover(ctx context.Context, archive *zip.Writer, url string) error {
+	body, info, err := h.covers.Open(ctx, url)
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+
+	// Images are compressed already.
+	w, err := archive.CreateHeader(&zip.FileHeader{
+		Name:     "covers/" + info.Key,
+		Method:   zip.Store,
+		Modified: info.ModTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, body)
+	return err
+}
+
+// loadExportEntries reads the user's live entries of one type with their
+// links and comments. Each type is read in full before any of it is written
+// so a slow download doesn't hold the database.
+func loadExportEntries(ctx context.Context, q database.Querier, t *media.Type, userID any) ([]*models.ExportEntry, error) {
+	columns := strings.Join(append(append([]string{}, mediaColumns...), t.Columns()...), ", ")
+	rows, err := q.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM %s WHERE user_id = ? AND deleted_at IS NULL ORDER BY id`,
+		columns, t.Table), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.ExportEntry{}
+	byID := map[int64]*models.ExportEntry{}
+	for rows.Next() {
+		item := t.New()
+		if err := scanMedia(rows, item); err != nil {
+			return nil, err
+		}
+
+		entry := exportEntry(t, item)
+		entries = append(entries, entry)
+		byID[entry.ID] = entry
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	links, err := q.QueryContext(ctx, fmt.Sprintf(`
+		SELECT l.item_id, l.key, l.value
+		FROM media_links l
+		JOIN %s t ON t.id = l.item_id AND t.user_id = ? AND t.deleted_at IS NULL
+		WHERE l.item_type = ?
+		ORDER BY l.id
+	`, t.Table), userID, t.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer links.Close()
+
+	for links.Next() {
+		var itemID int64
+		var link models.ExportLink
+		if err := links.Scan(&itemID, &link.Key, &link.Value); err != nil {
+			return nil, err
+		}
+		if entry, ok := byID[itemID]; ok {
+			entry.Links = append(entry.Links, link)
+		}
+	}
+	if err := links.Err(); err != nil {
+		return nil, err
+	}
+	links.Close()
+
+	comments, err := q.QueryContext(ctx, fmt.Sprintf(`
+		SELECT c.item_id, u.username, c.content, c.created_at, c.updated_at
+		FROM comments c
+		JOIN users u ON u.id = c.user_id
+		JOIN %s t ON t.id = c.item_id AND t.user_id = ? AND t.deleted_at IS NULL
+		WHERE c.item_type = ?
+		ORDER BY c.created_at, c.id
+	`, t.Table), userID, t.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer comments.Close()
+
+	for comments.Next() {
+		var itemID int64
+		var comment models.ExportComment
+		if err := comments.Scan(&itemID, &comment.Author, &comment.Content, &comment.CreatedAt, &comment.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if entry, ok := byID[itemID]; ok {
+			entry.Comments = append(entry.Comments, comment)
+		}
+	}
+
+	return entries, comments.Err()
+}
+
+func exportEntry(t *media.Type, item models.MediaEntry) *models.ExportEntry {
+	m := item.Common()
+	entry := &models.ExportEntry{
+		ItemType:    t.Name,
+		ID:          m.ID,
+		Title:       m.Title,
+		Genres:      m.Genres,
+		Tags:        m.Tags,
+		Rating:      m.Rating,
+		Status:      m.Status,
+		Description: m.Description,
+		MyThoughts:  m.MyThoughts,
+		CoverImage:  m.CoverImage,
+		Explicit:    m.Explicit,
+		Color:       m.Color,
+		Fields:      map[string]any{},
+		Links:       []models.ExportLink{},
+		Comments:    []models.ExportComment{},
+		StartedAt:   m.StartedAt,
+		CompletedAt: m.CompletedAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+
+	// Extra lists the stored fields first, then the derived ones, which an
+	// import would recompute anyway.
+	extra := item.Extra()
+	for i, field := range t.Stored() {
+		entry.Fields[field.Column] = exportValue(extra[i])
+	}
+
+	return entry
+}
+
+// exportValue dereferences a scan target, giving nil for NULL.
+func exportValue(target any) any {
+	v := reflect.ValueOf(target)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+// catalogWriter writes one export format. Entries arrive grouped by type, in
+// the order of media.Types.
+type catalogWriter interface {
+	begin(export *models.CatalogExport) error
+	entry(t *media.Type, entry *models.ExportEntry) error
+	end() error
+}
+
+// jsonCatalog writes a models.CatalogExport, one entry per line.
+type jsonCatalog struct {
+	w       io.Writer
+	written int
+}
+
+func (j *jsonCatalog) begin(export *models.CatalogExport) error {
+	// Everything up to the entries list, which is left open: the header
+	// fields of a models.CatalogExport, without its closing brace.
+	header, err := json.Marshal(struct {
+		Version    int       `json:"version"`
+		ExportedAt time.Time `json:"exported_at"`
+		Username   string    `json:"username"`
+	}{export.Version, export.ExportedAt, export.Username})
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(j.w, "%s,\"entries\":[", header[:len(header)-1])
+	return err
+}
+
+func (j *jsonCatalog) entry(_ *media.Type, entry *models.ExportEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n"
+	if j.written == 0 {
+		separator = "\n"
+	}
+	j.written++
+
+	_, err = fmt.Fprintf(j.w, "%s%s", separator, data)
+	return err
+}
+
+func (j *jsonCatalog) end() error {
+	_, err := io.WriteString(j.w, "\n]}\n")
+	return err
+}
+
+// csvCatalog writes a row per entry. The type-specific columns of every type
+// follow the shared ones, empty where they don't apply; lists are joined with
+// "; ", links written as key=value and comments one per line.
+type csvCatalog struct {
+	w *csv.Writer
+}
+
+var csvCommonColumns = []string{
+	"item_type", "id", "title", "genres", "tags", "rating", "status", "description", "my_thoughts",
+	"cover_image", "cover_file", "explicit", "color", "started_at", "completed_at", "created_at", "updated_at",
+}
+
+// csvFieldColumns lists the stored columns of every type once, in type order.
+func csvFieldColumns() []string {
+	columns := []string{}
+	seen := map[string]bool{}
+	for _, t := range media.Types {
+		for _, field := range t.Stored() {
+			if !seen[field.Column] {
+				seen[field.Column] = true
+				columns = append(columns, field.Column)
+			}
+		}
+	}
+	return columns
+}
+
+func (w *csvCatalog) begin(*models.CatalogExport) error {
+	header := append(append(append([]string{}, csvCommonColumns...), csvFieldColumns()...), "links", "comments")
+	return w.w.Write(header)
+}
+
+func (w *csvCatalog) entry(_ *media.Type, entry *models.ExportEntry) error {
+	record := []string{
+		entry.ItemType, strconv.FormatInt(entry.ID, 10), entry.Title,
+		strings.Join(entry.Genres, "; "), strings.Join(entry.Tags, "; "), strconv.Itoa(entry.Rating), entry.Status,
+		entry.Description, entry.MyThoughts, entry.CoverImage, entry.CoverFile, strconv.FormatBool(entry.Explicit),
+		entry.Color, exportTime(entry.StartedAt), exportTime(entry.CompletedAt),
+		exportTime(&entry.CreatedAt), exportTime(&entry.UpdatedAt),
+	}
+
+	for _, column := range csvFieldColumns() {
+		record = append(record, fieldText(entry.Fields[column]))
+	}
+
+	links := make([]string, len(entry.Links))
+	for i, link := range entry.Links {
+		links[i] = link.Key + "=" + link.Value
+	}
+	comments := make([]string, len(entry.Comments))
+	for i, comment := range entry.Comments {
+		comments[i] = fmt.Sprintf("%s (%s): %s", comment.Author, exportTime(&comment.CreatedAt), comment.Content)
+	}
+
+	record = append(record, strings.Join(links, "; "), strings.Join(comments, "\n"))
+	return w.w.Write(record)
+}
+
+func (w *csvCatalog) end() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// fieldText writes a type-specific value as text: scalars as themselves,
+// lists such as a tracklist as JSON and NULL as "".
+func fieldText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool, int, int64, float64:
+		return fmt.Sprint(v)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func exportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// markdownCatalog writes a readable document: a section per type and a
+// heading per entry, with its fields as a list and its comments quoted.
+type markdownCatalog struct {
+	w       io.Writer
+	current *media.Type
+}
+
+func (m *markdownCatalog) begin(export *models.CatalogExport) error {
+	_, err := fmt.Fprintf(m.w, "# %s's catalog\n\nExported %s.\n", export.Username,
+		export.ExportedAt.Format("January 2, 2006 at 15:04 UTC"))
+	return err
+}
+
+func (m *markdownCatalog) entry(t *media.Type, entry *models.ExportEntry) error {
+	var b strings.Builder
+	if m.current != t {
+		m.current = t
+		fmt.Fprintf(&b, "\n## %s\n", strings.ToUpper(t.Plural[:1])+t.Plural[1:])
+	}
+
+	fmt.Fprintf(&b, "\n### %s\n\n", entry.Title)
+	if cover := cmp.Or(entry.CoverFile, entry.CoverImage); cover != "" {
+		fmt.Fprintf(&b, "![%s](%s)\n\n", entry.Title, cover)
+	}
+
+	// Empty fields and flags that are off are left out.
+	for _, field := range t.Stored() {
+		if text := fieldText(entry.Fields[field.Column]); text != "" && text != "false" {
+			fmt.Fprintf(&b, "- **%s:** %s\n", fieldLabel(field.Column), text)
+		}
+	}
+	fmt.Fprintf(&b, "- **Status:** %s\n", entry.Status)
+	fmt.Fprintf(&b, "- **Rating:** %s%s\n", strings.Repeat("★", entry.Rating), strings.Repeat("☆", max(5-entry.Rating, 0)))
+	if len(entry.Genres) > 0 {
+		fmt.Fprintf(&b, "- **Genres:** %s\n", strings.Join(entry.Genres, ", "))
+	}
+	if len(entry.Tags) > 0 {
+		fmt.Fprintf(&b, "- **Tags:** %s\n", strings.Join(entry.Tags, ", "))
+	}
+	if entry.StartedAt != nil {
+		fmt.Fprintf(&b, "- **Started:** %s\n", entry.StartedAt.Format("2006-01-02"))
+	}
+	if entry.CompletedAt != nil {
+		fmt.Fprintf(&b, "- **Completed:** %s\n", entry.CompletedAt.Format("2006-01-02"))
+	}
+	for _, link := range entry.Links {
+		fmt.Fprintf(&b, "- **%s:** <%s>\n", link.Key, link.Value)
+	}
+
+	if entry.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", entry.Description)
+	}
+	if entry.MyThoughts != "" {
+		fmt.Fprintf(&b, "\n#### My thoughts\n\n%s\n", entry.MyThoughts)
+	}
+
+	if len(entry.Comments) > 0 {
+		b.WriteString("\n#### Comments\n")
+		for _, comment := range entry.Comments {
+			fmt.Fprintf(&b, "\n> **%s**, %s:\n>\n%s\n", comment.Author, comment.CreatedAt.Format("2006-01-02"),
+				quote(comment.Content))
+		}
+	}
+
+	_, err := io.WriteString(m.w, b.String())
+	return err
+}
+
+func (m *markdownCatalog) end() error {
+	return nil
+}
+
+// fieldLabel turns a column name such as page_count into "Page count".
+func fieldLabel(column string) string {
+	label := strings.ReplaceAll(column, "_", " ")
+	return strings.ToUpper(label[:1]) + label[1:]
+}
+
+// quote prefixes every line of text for a Markdown block quote.
+func quote(text string) string {
+	return "> " + strings.ReplaceAll(text, "\n", "\n> ")
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/fetcher"
+	"github.com/thebearodactyl/apiodactyl/internal/imaging"
+	"github.com/thebearodactyl/apiodactyl/internal/storage"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+type FileHandler struct {
+	store  storage.Store
+	covers *covers.Pipeline
+}
+
+func NewFileHandler(store storage.Store, covers *covers.Pipeline) *FileHandler {
+	return &FileHandler{store: store, covers: covers}
+}
+
+func (h *FileHandler) Upload(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.String(http.StatusBadRequest, fmt.Sprintf("failed to upload file: %v", err))
+		return
+	}
+
+	if !utils.HasAllowedExtension(fileHeader.Filename) {
+		c.String(http.StatusForbidden, "file MUST end with one of the following extensions:\nmp4, mkv, webm, png, jpg, jpeg, gif, mp3, ogg, avif")
+		return
+	}
+
+	ext := strings.ToLower(filepath.Ext(fileHeader.Filename))
+	expectedMime := utils.AllowedMimeTypes[ext]
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.String(http.StatusInternalServerError, fmt.Sprintf("file open error: %v", err))
+		return
+	}
+	defer file.Close()
+
+	buf := make([]byte, 512)
+	n, err := file.Read(buf)
+	if err != nil && err != io.EOF {
+		c.String(http.StatusInternalServerError, fmt.Sprintf("file read error: %v", err))
+		return
+	}
+	mimeType := http.DetectContentType(buf[:n])
+	if mimeType != expectedMime {
+		c.String(http.StatusBadRequest, fmt.Sprintf("mimetype %s does not match expected %s", mimeType, expectedMime))
+		return
+	}
+
+	key, existed, err := storage.PutContentAddressed(c.Request.Context(), h.store, file, fileHeader.Size, ext, expectedMime)
+	if err != nil {
+		c.String(http.StatusInternalServerError, fmt.Sprintf("failed to save file: %v", err))
+		return
+	}
+
+	audit.Target(c, "file", key)
+
+	message := "file uploaded successfully"
+	if existed {
+		message = "duplicate detected, returning existing file"
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   message,
+		"filename":  key,
+		"permalink": h.store.URL(key),
+	})
+}
+
+// ServeFile streams an object out of the configured store. Keys are content
+// hashes, so responses can be cached forever. Images can be resized on the fly
+// with ?w=<width>&fmt=<format>; see imaging.VariantWidths for allowed widths.
+func (h *FileHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	var body io.ReadCloser
+	var info *storage.ObjectInfo
+	var err error
+
+	if c.Query("w") != "" || c.Query("fmt") != "" {
+		body, info, err = h.variant(c, key)
+		if body == nil && err == nil {
+			return
+		}
+	} else {
+		body, info, err = h.store.Get(c.Request.Context(), key)
+	}
+
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		NotFound(c)
+		return
+	}
+	if errors.Is(err, imaging.ErrUnsupportedImage) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "File is not a resizable image"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to read file", err))
+		return
+	}
+	defer body.Close()
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	if info.ContentType != "" {
+		c.Header("Content-Type", info.ContentType)
+	}
+
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, key, info.ModTime, seeker)
+		return
+	}
+
+	c.DataFromReader(http.StatusOK, info.Size, info.ContentType, body, nil)
+}
+
+// variant validates the resize parameters and fetches the requested variant.
+// It writes the 400 or 406 itself and returns a nil body and error in that
+// case.
+func (h *FileHandler) variant(c *gin.Context, key string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	width := imaging.VariantWidths[len(imaging.VariantWidths)-1]
+	if raw := c.Query("w"); raw != "" {
+		w, err := strconv.Atoi(raw)
+		if err != nil || !imaging.IsVariantWidth(w) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":          "Unsupported width",
+				"allowed_widths": imaging.VariantWidths,
+			})
+			return nil, nil, nil
+		}
+		width = w
+	}
+
+	format, err := imaging.ResolveFormat(c.Query("fmt"))
+	if errors.Is(err, imaging.ErrUnencodableFormat) {
+		c.JSON(http.StatusNotAcceptable, gin.H{
+			"error":           "Cannot encode this format",
+			"allowed_formats": imaging.Formats(),
+		})
+		return nil, nil, nil
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":           "Unsupported format",
+			"allowed_formats": imaging.Formats(),
+		})
+		return nil, nil, nil
+	}
+
+	if strings.Contains(key, "/") {
+		return nil, nil, storage.ErrNotFound
+	}
+
+	return h.covers.Variant(c.Request.Context(), key, width, format)
+}
+
+// coverDownloadError reports a failed cover_image_url download. Problems with
+// the URL itself are the client's fault; anything else is a bad gateway.
+func coverDownloadError(c *gin.Context, err error) {
+	var fetchErr *fetcher.Error
+	if !errors.As(err, &fetchErr) {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to save cover image", err))
+		return
+	}
+
+	status := http.StatusBadGateway
+	if fetchErr.IsClientError() {
+		status = http.StatusUnprocessableEntity
+	}
+
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"title":   "Failed to download cover image",
+			"code":    fetchErr.Code,
+			"message": fetchErr.Message,
+		},
+	})
+}
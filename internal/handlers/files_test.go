@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/fetcher"
+	"github.com/thebearodactyl/apiodactyl/internal/imaging"
+	"github.com/thebearodactyl/apiodactyl/internal/storage"
+)
+
+func TestUploadPermalink(t *testing.T) {
+	store, err := storage.New(config.StorageConfig{Backend: "local", PublicBaseURL: "https://media.example.com"},
+		filepath.Join(t.TempDir(), "files"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewFileHandler(store, nil)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "cover.png")
+	png.Encode(part, image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	form.Close()
+
+	// The permalink comes from PUBLIC_BASE_URL, whatever host the request
+	// claims to be for.
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/upload", &body)
+	c.Request.Host = "attacker.example.com"
+	c.Request.Header.Set("Content-Type", form.FormDataContentType())
+	c.Request.Header.Set("X-Forwarded-Host", "attacker.example.com")
+	h.Upload(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Upload: %d %s", w.Code, w.Body)
+	}
+	var response struct {
+		Filename  string `json:"filename"`
+		Permalink string `json:"permalink"`
+	}
+	decodeBody(t, w, &response)
+	if !strings.HasSuffix(response.Filename, ".png") || response.Permalink != "https://media.example.com/files/"+response.Filename {
+		t.Errorf("response = %+v", response)
+	}
+}
+
+// TestServeFileFormats checks the formats documented for GET /files/*key
+// against what the handler does with them.
+func TestServeFileFormats(t *testing.T) {
+	store, err := storage.NewLocalStore(filepath.Join(t.TempDir(), "files"), "/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pipeline := covers.New(store, fetcher.New(config.FetchConfig{}), "")
+	h := NewFileHandler(store, pipeline)
+
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 400, 200)))
+	cover, err := pipeline.Save(context.Background(), bytes.NewReader(buf.Bytes()), int64(buf.Len()), ".png", "image/png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := []byte("plain text")
+	if err := store.Put(context.Background(), "notes.txt", bytes.NewReader(text), int64(len(text)), "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+
+	r := gin.New()
+	r.GET("/files/*key", h.ServeFile)
+	op := apiDocs.Document(r.Routes(), "").Paths["/files/{key}"]["get"]
+
+	var formats []any
+	for _, p := range op.Parameters {
+		if p.Name == "fmt" {
+			formats = p.Schema.Enum
+		}
+	}
+	if len(formats) == 0 {
+		t.Fatal("fmt is not documented")
+	}
+	for _, status := range []string{"200", "400", "404", "406", "415"} {
+		if op.Responses[status] == nil {
+			t.Errorf("response %s is not documented", status)
+		}
+	}
+
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	key := strings.TrimPrefix(cover.URL, "/files/")
+	for _, format := range formats {
+		format := format.(string)
+		want := http.StatusNotAcceptable
+		if slices.Contains(imaging.Formats(), format) {
+			want = http.StatusOK
+		}
+		if code := get("/files/" + key + "?w=160&fmt=" + format); code != want {
+			t.Errorf("fmt=%s: %d, want %d", format, code, want)
+		}
+	}
+
+	if code := get("/files/" + key + "?fmt=gif"); code != http.StatusBadRequest {
+		t.Errorf("undocumented format: %d, want 400", code)
+	}
+	if code := get("/files/notes.txt?w=160"); code != http.StatusUnsupportedMediaType {
+		t.Errorf("variant of a text file: %d, want 415", code)
+	}
+}
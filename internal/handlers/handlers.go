@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
 	"github.com/thebearodactyl/apiodactyl/internal/database"
 	"github.com/thebearodactyl/apiodactyl/internal/models"
 )
@@ -21,7 +22,7 @@ func NewHandler(db *database.DB) *Handler {
 func (h *Handler) GetResources(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 
-	query := `SELECT id, name, description, user_id, created_at, updated_at FROM resources WHERE user_id = ? ORDER BY created_at DESC`
+	query := `SELECT id, name, description, user_id, created_at, updated_at FROM resources WHERE user_id = ? AND deleted_at IS NULL ORDER BY created_at DESC`
 
 	rows, err := h.db.QueryContext(c.Request.Context(), query, userID)
 	if err != nil {
@@ -52,7 +53,7 @@ func (h *Handler) GetResource(c *gin.Context) {
 
 	userID, _ := c.Get("user_id")
 
-	query := `SELECT id, name, description, user_id, created_at, updated_at FROM resources WHERE id = ? AND user_id = ?`
+	query := `SELECT id, name, description, user_id, created_at, updated_at FROM resources WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
 
 	var r models.Resource
 	err = h.db.QueryRowContext(c.Request.Context(), query, id, userID).Scan(
@@ -90,6 +91,11 @@ func (h *Handler) CreateResource(c *gin.Context) {
 		return
 	}
 
+	// Audit images are best effort; a missing snapshot leaves that side empty.
+	after, _ := snapshotItem(c.Request.Context(), h.db, resourceSnapshots, id)
+	audit.Target(c, "resource", id)
+	audit.Change(c, nil, after)
+
 	c.JSON(http.StatusCreated, gin.H{
 		"id":      id,
 		"message": "Resource created successfully",
@@ -109,9 +115,13 @@ func (h *Handler) UpdateResource(c *gin.Context) {
 		return
 	}
 
+	audit.Target(c, "resource", id)
+
 	userID, _ := c.Get("user_id")
 
-	query := `UPDATE resources SET name = ?, description = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ?`
+	before, _ := snapshotItem(c.Request.Context(), h.db, resourceSnapshots, id)
+
+	query := `UPDATE resources SET name = ?, description = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
 
 	result, err := h.db.ExecContext(c.Request.Context(), query, req.Name, req.Description, id, userID)
 	if err != nil {
@@ -125,6 +135,9 @@ func (h *Handler) UpdateResource(c *gin.Context) {
 		return
 	}
 
+	after, _ := snapshotItem(c.Request.Context(), h.db, resourceSnapshots, id)
+	audit.Change(c, before, after)
+
 	c.JSON(http.StatusOK, gin.H{"message": "Resource updated successfully"})
 }
 
@@ -135,9 +148,13 @@ func (h *Handler) DeleteResource(c *gin.Context) {
 		return
 	}
 
+	audit.Target(c, "resource", id)
+
 	userID, _ := c.Get("user_id")
 
-	query := `DELETE FROM resources WHERE id = ? AND user_id = ?`
+	before, _ := snapshotItem(c.Request.Context(), h.db, resourceSnapshots, id)
+
+	query := `UPDATE resources SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND user_id = ? AND deleted_at IS NULL`
 
 	result, err := h.db.ExecContext(c.Request.Context(), query, id, userID)
 	if err != nil {
@@ -151,5 +168,7 @@ func (h *Handler) DeleteResource(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Resource deleted successfully"})
+	audit.Change(c, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource moved to trash"})
 }
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestDB returns a fully migrated database with the users named.
+func newTestDB(t *testing.T, users ...string) *database.DB {
+	t.Helper()
+
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for _, username := range users {
+		_, err := db.Exec(`INSERT INTO users (username, email, password_hash, role) VALUES (?, ?, 'x', ?)`,
+			username, username+"@example.com", models.RoleNormal)
+		if err != nil {
+			t.Fatalf("failed to add user %s: %v", username, err)
+		}
+	}
+	return db
+}
+
+func newTestGame(t *testing.T, db *database.DB, userID int64, title string, links ...models.MediaLink) int64 {
+	t.Helper()
+
+	req := &models.CreateGameRequest{
+		CreateMediaRequest: models.CreateMediaRequest{
+			Title:       title,
+			Genres:      models.StringArray{"rpg"},
+			Tags:        models.StringArray{},
+			Rating:      4,
+			Status:      models.StatusPlanned,
+			Description: "A game",
+			MyThoughts:  "Looks good",
+			Links:       links,
+		},
+		Developer: "Studio",
+	}
+
+	id, _, err := insertEntry(context.Background(), db, media.Game, req, &covers.Cover{URL: "https://example.com/cover.png"}, "#000000", userID)
+	if err != nil {
+		t.Fatalf("insertEntry: %v", err)
+	}
+	return id
+}
+
+// testContext builds a request context for userID in role, with params.
+func testContext(method string, userID int64, role string, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(method, "/", nil)
+	c.Params = params
+	c.Set("user_id", userID)
+	c.Set("user_role", role)
+	return c, w
+}
+
+func orEmpty[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+	return values
+}
+
+// assertJSON compares got and want through their JSON encodings.
+func assertJSON(t *testing.T, name string, got, want any) {
+	t.Helper()
+
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("%s = %s, want %s", name, gotJSON, wantJSON)
+	}
+}
+
+// call runs handler for userID in role, with params and body sent as JSON
+// (nil sends no body).
+func call(handler gin.HandlerFunc, method string, userID int64, role string, params gin.Params, body any) *httptest.ResponseRecorder {
+	c, w := testContext(method, userID, role, params)
+	if body != nil {
+		data, _ := json.Marshal(body)
+		c.Request = httptest.NewRequest(method, "/", bytes.NewReader(data))
+		c.Request.Header.Set("Content-Type", "application/json")
+	}
+	handler(c)
+	return w
+}
+
+// param builds the path parameters of a request.
+func param(pairs ...string) gin.Params {
+	params := gin.Params{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		params = append(params, gin.Param{Key: pairs[i], Value: pairs[i+1]})
+	}
+	return params
+}
+
+// decodeBody decodes a JSON response into v.
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal(w.Body.Bytes(), v); err != nil {
+		t.Fatalf("invalid response %s: %v", w.Body, err)
+	}
+}
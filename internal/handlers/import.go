@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/importer"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// ErrInvalidRows stops an import that has invalid rows, unless they are to
+// be skipped. Nothing has been written when it is returned.
+var ErrInvalidRows = errors.New("the export has invalid rows")
+
+type ImportHandler struct {
+	db     *database.DB
+	covers *covers.Pipeline
+}
+
+func NewImportHandler(db *database.DB, pipeline *covers.Pipeline) *ImportHandler {
+	return &ImportHandler{db: db, covers: pipeline}
+}
+
+// ImportOptions controls RunImport. Defaults fill in the create request
+// fields a row leaves empty.
+type ImportOptions struct {
+	Defaults    map[string]any
+	DryRun      bool
+	SkipInvalid bool
+	UserID      int64
+}
+
+// Import reads an uploaded export and previews or imports its rows.
+func (h *ImportHandler) Import(c *gin.Context) {
+	var req models.ImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source, ok := importer.Lookup(req.Source)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source (expected one of " + importer.Names() + ")"})
+		return
+	}
+
+	audit.Action(c, audit.ActionImport)
+	audit.Target(c, source.Type.Name, "")
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	opts := ImportOptions{DryRun: req.DryRun, SkipInvalid: req.SkipInvalid}
+	if req.Defaults != "" {
+		if err := json.Unmarshal([]byte(req.Defaults), &opts.Defaults); err != nil {
+			c.JSON(http.StatusBadRequest, utils.GenErr("Invalid defaults", err))
+			return
+		}
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Failed to read file", err))
+		return
+	}
+	defer file.Close()
+
+	rows, err := source.Parse(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Failed to read "+source.Label, err))
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	opts.UserID, _ = userID.(int64)
+
+	response, err := RunImport(c.Request.Context(), h.db, h.covers, source, rows, opts)
+	if errors.Is(err, ErrInvalidRows) {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to import", err))
+		return
+	}
+
+	if response.Created == 0 {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	created := []int64{}
+	for _, row := range response.Rows {
+		if row.ID != nil {
+			created = append(created, *row.ID)
+		}
+	}
+	audit.Change(c, nil, gin.H{"source": source.Name, "created": created})
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// RunImport checks every row of an export and, unless it is a dry run,
+// creates the valid ones that aren't duplicates in a single transaction.
+// Rows are duplicates when their title and creator match, ignoring case, an
+// entry of the user's or an earlier row.
+func RunImport(ctx context.Context, db *database.DB, pipeline *covers.Pipeline, source *importer.Source, rows []importer.Row, opts ImportOptions) (*models.ImportResponse, error) {
+	t := source.Type
+
+	response := &models.ImportResponse{
+		Source:   source.Name,
+		ItemType: t.Name,
+		DryRun:   opts.DryRun,
+		Total:    len(rows),
+		Rows:     []models.ImportRow{},
+	}
+
+	existing, err := existingEntries(ctx, db, t, opts.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	type pending struct {
+		row   int
+		req   models.MediaCreate
+		cover *covers.Cover
+		color string
+	}
+	valid := []pending{}
+	seen := map[string]bool{}
+	described := map[string]*covers.Cover{}
+
+	for _, row := range rows {
+		fields := mergeImportFields(opts.Defaults, row.Fields)
+		title, _ := fields["title"].(string)
+		creator, _ := fields[t.Creator].(string)
+
+		result := models.ImportRow{Line: row.Line, Title: title, Entry: fields}
+
+		req := t.NewCreate()
+		result.Errors = checkImportRow(t, fields, req)
+
+		var cover *covers.Cover
+		color := ""
+		if len(result.Errors) == 0 {
+			url := req.Common().CoverImage
+			if described[url] == nil {
+				described[url] = pipeline.Describe(ctx, url)
+			}
+			cover = described[url]
+
+			color = req.Common().Color
+			if color == "" {
+				color = cover.Color()
+			}
+			if color == "" {
+				result.Errors = append(result.Errors, "color is required when it cannot be derived from the cover image")
+			}
+		}
+
+		key := importKey(title, creator)
+		switch {
+		case len(result.Errors) > 0:
+			result.Result = models.ImportInvalid
+			response.Invalid++
+		case existing[key] != 0:
+			id := existing[key]
+			result.Result = models.ImportDuplicate
+			result.DuplicateOf = &id
+			response.Duplicates++
+		case seen[key]:
+			result.Result = models.ImportDuplicate
+			response.Duplicates++
+		default:
+			seen[key] = true
+			result.Result = models.ImportValid
+			response.Valid++
+			valid = append(valid, pending{row: len(response.Rows), req: req, cover: cover, color: color})
+		}
+
+		response.Rows = append(response.Rows, result)
+	}
+
+	if opts.DryRun {
+		response.Message = fmt.Sprintf("Dry run: %d of %d rows would be imported", response.Valid, response.Total)
+		return response, nil
+	}
+
+	if response.Invalid > 0 && !opts.SkipInvalid {
+		response.Message = fmt.Sprintf("%d rows are invalid; nothing was imported (fix them, fill them in with defaults or skip them with skip_invalid)", response.Invalid)
+		return response, ErrInvalidRows
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, p := range valid {
+		id, _, err := insertEntry(ctx, tx, t, p.req, p.cover, p.color, opts.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", response.Rows[p.row].Line, err)
+		}
+
+		response.Rows[p.row].Result = models.ImportCreated
+		response.Rows[p.row].ID = &id
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	response.Created = len(valid)
+	response.Message = fmt.Sprintf("Imported %d of %d rows", response.Created, response.Total)
+	return response, nil
+}
+
+// existingEntries maps the importKey of each of the user's live entries of
+// type t to its ID.
+func existingEntries(ctx context.Context, q database.Querier, t *media.Type, userID int64) (map[string]int64, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("SELECT id, title, COALESCE(%s, '') FROM %s WHERE user_id = ? AND deleted_at IS NULL",
+		t.Creator, t.Table), userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := map[string]int64{}
+	for rows.Next() {
+		var id int64
+		var title, creator string
+		if err := rows.Scan(&id, &title, &creator); err != nil {
+			return nil, err
+		}
+		existing[importKey(title, creator)] = id
+	}
+
+	return existing, rows.Err()
+}
+
+func importKey(title, creator string) string {
+	return strings.ToLower(strings.TrimSpace(title)) + "\x00" + strings.ToLower(strings.TrimSpace(creator))
+}
+
+// mergeImportFields lays a row's fields over the defaults. Empty row values
+// (blank strings, zeros and empty lists) leave the default in place.
+func mergeImportFields(defaults, row map[string]any) map[string]any {
+	fields := map[string]any{}
+	for key, value := range defaults {
+		fields[key] = value
+	}
+	for key, value := range row {
+		if _, ok := fields[key]; ok && emptyImportValue(value) {
+			continue
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+func emptyImportValue(value any) bool {
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return true
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+// checkImportRow decodes a row into req and runs the checks Create would,
+// returning what is wrong with it.
+func checkImportRow(t *media.Type, fields map[string]any, req models.MediaCreate) []string {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		return []string{err.Error()}
+	}
+
+	problems := []string{}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		var fieldErrors validator.ValidationErrors
+		if !errors.As(err, &fieldErrors) {
+			return []string{err.Error()}
+		}
+		for _, fe := range fieldErrors {
+			problems = append(problems, fieldProblem(req, fe))
+		}
+		return problems
+	}
+
+	if n, ok := req.(models.MediaNormalizer); ok {
+		if err := n.Normalize(); err != nil {
+			return []string{err.Error()}
+		}
+	}
+
+	common := req.Common()
+	if !t.HasStatus(common.Status) {
+		problems = append(problems, fmt.Sprintf("status: must be one of %s for %s", t.StatusNames(), t.Plural))
+	}
+	if common.CoverImageURL != "" {
+		problems = append(problems, "cover_image_url is not supported when importing; use cover_image")
+	} else if common.CoverImage == "" {
+		problems = append(problems, "cover_image is required")
+	}
+
+	return problems
+}
+
+// fieldProblem describes a failed binding check by the field's JSON name.
+func fieldProblem(req any, fe validator.FieldError) string {
+	name := fe.Field()
+	if field, ok := reflect.TypeOf(req).Elem().FieldByName(fe.StructField()); ok {
+		if tag, _, _ := strings.Cut(field.Tag.Get("json"), ","); tag != "" {
+			name = tag
+		}
+	}
+
+	rule := fe.Tag()
+	if fe.Param() != "" {
+		rule += "=" + fe.Param()
+	}
+	return fmt.Sprintf("%s: failed %s", name, rule)
+}
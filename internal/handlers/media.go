@@ -0,0 +1,890 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/metadata"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// mediaColumns are the columns every media table shares, in the order
+// scanMedia reads them. Type-specific columns follow.
+var mediaColumns = []string{
+	"id", "title", "genres", "tags", "rating", "status", "description", "my_thoughts",
+	"cover_image", "explicit", "color", "palette", "blurhash", "user_id", "created_at", "updated_at",
+	"started_at", "completed_at",
+}
+
+// mediaSortFields are the shared columns search can sort by.
+var mediaSortFields = []string{"title", "rating", "status", "created_at", "updated_at", "started_at", "completed_at"}
+
+// MediaHandler serves one media type; everything type-specific comes from
+// its media.Type.
+type MediaHandler struct {
+	db       *database.DB
+	covers   *covers.Pipeline
+	metadata *metadata.Registry
+	typ      *media.Type
+}
+
+func NewMediaHandler(db *database.DB, covers *covers.Pipeline, providers *metadata.Registry, typ *media.Type) *MediaHandler {
+	return &MediaHandler{db: db, covers: covers, metadata: providers, typ: typ}
+}
+
+func (h *MediaHandler) columns() string {
+	return strings.Join(append(append([]string{}, mediaColumns...), h.typ.Columns()...), ", ")
+}
+
+func scanMedia(row interface{ Scan(...any) error }, item models.MediaEntry, extra ...any) error {
+	m := item.Common()
+	targets := []any{&m.ID, &m.Title, &m.Genres, &m.Tags, &m.Rating, &m.Status, &m.Description, &m.MyThoughts,
+		&m.CoverImage, &m.Explicit, &m.Color, &m.Palette, &m.BlurHash, &m.UserID, &m.CreatedAt, &m.UpdatedAt,
+		&m.StartedAt, &m.CompletedAt}
+	targets = append(targets, item.Extra()...)
+	return row.Scan(append(targets, extra...)...)
+}
+
+func (h *MediaHandler) List(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	query := `
+		SELECT ` + h.columns() + `
+		FROM ` + h.typ.Table + `
+		WHERE user_id = ? AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := h.db.QueryContext(c.Request.Context(), query, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + h.typ.Plural})
+		return
+	}
+	defer rows.Close()
+
+	items, err := h.scanItems(rows, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to read "+h.typ.Plural, err))
+		return
+	}
+
+	if err := h.attachLinks(c.Request.Context(), items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + h.typ.Name + " links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *MediaHandler) Get(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	query := `
+		SELECT ` + h.columns() + `
+		FROM ` + h.typ.Table + `
+		WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+	`
+
+	item := h.typ.New()
+	err = scanMedia(h.db.QueryRowContext(c.Request.Context(), query, id, userID), item)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.typ.Label + " not found"})
+		return
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch "+h.typ.Name, err))
+		return
+	}
+
+	if err := h.attachLinks(c.Request.Context(), []models.MediaEntry{item}); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch "+h.typ.Name+" links", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *MediaHandler) Create(c *gin.Context) {
+	req := h.typ.NewCreate()
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+
+	h.create(c, req)
+}
+
+// create stores a bound create request, for Create and CreateFromMetadata.
+func (h *MediaHandler) create(c *gin.Context, req models.MediaCreate) {
+	if !normalize(c, req) {
+		return
+	}
+	common := req.Common()
+	if !h.typ.HasStatus(common.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status for a " + h.typ.Name + " (expected one of " + h.typ.StatusNames() + ")"})
+		return
+	}
+
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, utils.GenErr("Unauthorized", fmt.Errorf("no user id in context")))
+		return
+	}
+
+	cover, ok := h.saveCover(c, common)
+	if !ok {
+		return
+	}
+
+	color := common.Color
+	if color == "" {
+		color = cover.Color()
+	}
+	if color == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "color is required when it cannot be derived from the cover image"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create " + h.typ.Name})
+		return
+	}
+	defer tx.Rollback()
+
+	id, after, err := insertEntry(ctx, tx, h.typ, req, cover, color, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to create "+h.typ.Name, err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create " + h.typ.Name})
+		return
+	}
+
+	audit.Target(c, h.typ.Name, id)
+	audit.Change(c, nil, after)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":          id,
+		"cover_image": cover.URL,
+		"color":       color,
+		"palette":     models.StringArray(cover.Palette),
+		"blurhash":    cover.BlurHash,
+		"message":     h.typ.Label + " created successfully",
+	})
+}
+
+// insertEntry stores a new entry with its links, initial status and first
+// revision, returning the entry's ID and snapshot.
+func insertEntry(ctx context.Context, q database.Querier, t *media.Type, req models.MediaCreate, cover *covers.Cover, color string, userID any) (int64, any, error) {
+	common := req.Common()
+
+	columns := []string{
+		"title", "genres", "tags", "rating", "status", "description", "my_thoughts",
+		"cover_image", "explicit", "color", "palette", "blurhash", "user_id",
+	}
+	for _, field := range t.Stored() {
+		columns = append(columns, field.Column)
+	}
+	values := append([]any{
+		common.Title, common.Genres, common.Tags, common.Rating, common.Status, common.Description, common.MyThoughts,
+		cover.URL, common.Explicit, color, models.StringArray(cover.Palette), cover.BlurHash, userID,
+	}, req.Extra()...)
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING id`,
+		t.Table, strings.Join(columns, ", "), placeholders(len(columns)))
+
+	var id int64
+	if err := q.QueryRowContext(ctx, query, values...).Scan(&id); err != nil {
+		return 0, nil, err
+	}
+
+	if err := insertMediaLinks(ctx, q, t.Name, id, common.Links); err != nil {
+		return 0, nil, fmt.Errorf("failed to create links: %w", err)
+	}
+
+	if err := changeStatus(ctx, q, mediaRevisions(t), id, "", common.Status, userID); err != nil {
+		return 0, nil, fmt.Errorf("failed to record status: %w", err)
+	}
+
+	after, err := recordRevision(ctx, q, mediaRevisions(t), id, models.RevisionCreate, userID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to record revision: %w", err)
+	}
+
+	return id, after, nil
+}
+
+// saveCover resolves the cover of a new entry from a cover_image upload, a
+// cover_image_url to download, or a cover_image that is already stored, in
+// that order. It writes the error response itself.
+func (h *MediaHandler) saveCover(c *gin.Context, req *models.CreateMediaRequest) (*covers.Cover, bool) {
+	fileHeader, fileErr := c.FormFile("cover_image")
+	switch {
+	case fileErr == nil && fileHeader != nil:
+		cover, err := h.covers.SaveUpload(c.Request.Context(), fileHeader)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		return cover, true
+	case req.CoverImageURL != "":
+		cover, err := h.covers.SaveFromURL(c.Request.Context(), req.CoverImageURL)
+		if err != nil {
+			coverDownloadError(c, err)
+			return nil, false
+		}
+		return cover, true
+	case req.CoverImage != "":
+		return h.covers.Describe(c.Request.Context(), req.CoverImage), true
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "cover_image, cover_image_url, or cover_image file upload is required"})
+	return nil, false
+}
+
+func (h *MediaHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Invalid ID", err))
+		return
+	}
+
+	req := h.typ.NewUpdate()
+	if err := c.ShouldBindJSON(req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Invalid JSON", err))
+		return
+	}
+	if !normalize(c, req) {
+		return
+	}
+	common := req.Common()
+	if common.Status != "" && !h.typ.HasStatus(common.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status for a " + h.typ.Name + " (expected one of " + h.typ.StatusNames() + ")"})
+		return
+	}
+
+	audit.Target(c, h.typ.Name, id)
+
+	userID, _ := c.Get("user_id")
+
+	updates := []string{}
+	args := []any{}
+
+	if common.Title != "" {
+		updates = append(updates, "title = ?")
+		args = append(args, common.Title)
+	}
+	if common.Genres != nil {
+		updates = append(updates, "genres = ?")
+		args = append(args, common.Genres)
+	}
+	if common.Tags != nil {
+		updates = append(updates, "tags = ?")
+		args = append(args, common.Tags)
+	}
+	if common.Rating > 0 {
+		updates = append(updates, "rating = ?")
+		args = append(args, common.Rating)
+	}
+	if common.Status != "" {
+		updates = append(updates, "status = ?")
+		args = append(args, common.Status)
+	}
+	if common.Description != "" {
+		updates = append(updates, "description = ?")
+		args = append(args, common.Description)
+	}
+	if common.MyThoughts != "" {
+		updates = append(updates, "my_thoughts = ?")
+		args = append(args, common.MyThoughts)
+	}
+	if common.CoverImage != "" {
+		cover := h.covers.Describe(c.Request.Context(), common.CoverImage)
+		updates = append(updates, "cover_image = ?", "palette = ?", "blurhash = ?")
+		args = append(args, cover.URL, models.StringArray(cover.Palette), cover.BlurHash)
+		if common.Color == "" && cover.Color() != "" {
+			updates = append(updates, "color = ?")
+			args = append(args, cover.Color())
+		}
+	}
+	if common.Explicit != nil {
+		updates = append(updates, "explicit = ?")
+		args = append(args, *common.Explicit)
+	}
+	if common.Color != "" {
+		updates = append(updates, "color = ?")
+		args = append(args, common.Color)
+	}
+
+	stored := h.typ.Stored()
+	touched := map[string]bool{}
+	for i, value := range req.Extra() {
+		if value, ok := updatedValue(value); ok {
+			updates = append(updates, stored[i].Column+" = ?")
+			args = append(args, value)
+			touched[stored[i].Column] = true
+		}
+	}
+
+	if len(updates) == 0 && common.Links == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	updates = append(updates, "updated_at = CURRENT_TIMESTAMP")
+	args = append(args, id, userID)
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = ? AND user_id = ? AND deleted_at IS NULL", h.typ.Table, strings.Join(updates, ", "))
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+	defer tx.Rollback()
+
+	var from string
+	if common.Status != "" {
+		err := tx.QueryRowContext(ctx, "SELECT status FROM "+h.typ.Table+" WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
+			id, userID).Scan(&from)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": h.typ.Label + " not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+			return
+		}
+
+		if !h.typ.CanTransition(from, common.Status) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":   fmt.Sprintf("Cannot move a %s from %s to %s", h.typ.Name, from, common.Status),
+				"allowed": h.typ.Transitions(from),
+			})
+			return
+		}
+	}
+
+	before, err := snapshotItem(ctx, tx, mediaRevisions(h.typ), id)
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.typ.Label + " not found"})
+		return
+	}
+
+	if common.Links != nil {
+		_, err := tx.ExecContext(ctx, "DELETE FROM media_links WHERE item_type = ? AND item_id = ?", h.typ.Name, id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name + " links"})
+			return
+		}
+
+		if err := insertMediaLinks(ctx, tx, h.typ.Name, id, common.Links); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name + " links"})
+			return
+		}
+	}
+
+	if common.Status != "" {
+		if err := changeStatus(ctx, tx, mediaRevisions(h.typ), id, from, common.Status, userID); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record status", err))
+			return
+		}
+	}
+
+	if !h.settleCounters(c, tx, id, touched, common.Status != "") {
+		return
+	}
+
+	after, err := recordRevision(ctx, tx, mediaRevisions(h.typ), id, models.RevisionUpdate, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record revision", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+
+	audit.Change(c, before, after)
+
+	c.JSON(http.StatusOK, gin.H{"message": h.typ.Label + " updated successfully"})
+}
+
+// normalize runs the request's own checks, if it has any.
+func normalize(c *gin.Context, req any) bool {
+	if n, ok := req.(models.MediaNormalizer); ok {
+		if err := n.Normalize(); err != nil {
+			c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+			return false
+		}
+	}
+	return true
+}
+
+// updatedValue reports whether a type-specific update field was set: nil
+// pointers and slices and empty strings are left alone.
+func updatedValue(value any) (any, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil, false
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, false
+		}
+		return v.Elem().Interface(), true
+	case reflect.Slice:
+		return value, !v.IsNil()
+	case reflect.String:
+		return value, v.Len() > 0
+	}
+	return value, true
+}
+
+func (h *MediaHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	audit.Target(c, h.typ.Name, id)
+
+	userID, _ := c.Get("user_id")
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + h.typ.Name})
+		return
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM `+h.typ.Table+` WHERE id = ? AND user_id = ? AND deleted_at IS NULL`, id, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.typ.Label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + h.typ.Name})
+		return
+	}
+
+	// Snapshot before trashing so the entry can be rolled back later.
+	before, err := recordRevision(ctx, tx, mediaRevisions(h.typ), id, models.RevisionDelete, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record revision", err))
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE `+h.typ.Table+` SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + h.typ.Name})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + h.typ.Name})
+		return
+	}
+
+	audit.Change(c, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": h.typ.Label + " moved to trash"})
+}
+
+func (h *MediaHandler) Search(c *gin.Context) {
+	search := h.typ.NewSearch()
+	if err := c.ShouldBindQuery(search); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	params := search.Common()
+
+	userID, _ := c.Get("user_id")
+
+	table := h.typ.Table
+	fromClause := table
+	snippetColumns := "NULL, NULL"
+	joinArgs := []any{}
+
+	if params.Query != "" {
+		match, err := buildFTSQuery(params.Query)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.GenErr("Invalid search query", err))
+			return
+		}
+
+		fts := h.typ.FTS
+		fromClause = table + ` JOIN (
+			SELECT rowid AS fts_id,
+			       bm25(` + fts + `, 10.0, 5.0, 1.0, 2.0) AS fts_rank,
+			       snippet(` + fts + `, 3, char(2), char(3), '…', 24) AS thoughts_snippet,
+			       snippet(` + fts + `, 2, char(2), char(3), '…', 24) AS description_snippet
+			FROM ` + fts + `
+			WHERE ` + fts + ` MATCH ?
+		) fts ON fts.fts_id = ` + table + `.id`
+		snippetColumns = "fts.thoughts_snippet, fts.description_snippet"
+		joinArgs = append(joinArgs, match)
+	}
+
+	whereClauses, args := mediaFilters(h.typ, params, userID)
+	whereClauses, args = h.fieldFilters(c, whereClauses, args)
+
+	orderBy := "created_at DESC"
+	if params.Query != "" && (params.SortBy == "" || params.SortBy == "relevance") {
+		orderBy = "fts.fts_rank ASC"
+	} else if params.SortBy != "" && h.sortable(params.SortBy) {
+		sortOrder := "ASC"
+		if params.SortOrder == "desc" {
+			sortOrder = "DESC"
+		}
+		orderBy = params.SortBy + " " + sortOrder
+	}
+
+	limit := 50
+	if params.Limit > 0 && params.Limit <= 100 {
+		limit = params.Limit
+	}
+
+	offset := max(params.Offset, 0)
+
+	query := fmt.Sprintf(`
+		SELECT %s, %s
+		FROM %s
+		WHERE %s
+		ORDER BY %s
+		LIMIT ? OFFSET ?
+	`, h.columns(), snippetColumns, fromClause, strings.Join(whereClauses, " AND "), orderBy)
+
+	args = append(append(joinArgs, args...), limit, offset)
+
+	rows, err := h.db.QueryContext(c.Request.Context(), query, args...)
+	if isFTSSyntaxError(err) {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Invalid search query", err))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search " + h.typ.Plural})
+		return
+	}
+	defer rows.Close()
+
+	items, err := h.scanItems(rows, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Error iterating search results", err))
+		return
+	}
+
+	if err := h.attachLinks(c.Request.Context(), items); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + h.typ.Name + " links"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": items,
+		"limit":   limit,
+		"offset":  offset,
+		"count":   len(items),
+	})
+}
+
+// mediaFilters builds the WHERE clauses for the filters every media type
+// shares.
+func mediaFilters(t *media.Type, params *models.MediaSearchParams, userID any) ([]string, []any) {
+	whereClauses := []string{"user_id = ?", "deleted_at IS NULL"}
+	args := []any{userID}
+
+	for _, filter := range []struct {
+		column string
+		value  string
+	}{{"title", params.Title}, {"description", params.Description}, {"my_thoughts", params.MyThoughts}} {
+		if filter.value != "" {
+			whereClauses = append(whereClauses, filter.column+" LIKE ?")
+			args = append(args, "%"+filter.value+"%")
+		}
+	}
+
+	if params.Color != "" {
+		whereClauses = append(whereClauses, "color = ?")
+		args = append(args, params.Color)
+	}
+
+	for _, filter := range []struct {
+		tax   *media.Taxonomy
+		names []string
+	}{{media.Genres, params.Genres}, {media.Tags, params.Tags}} {
+		if len(filter.names) > 0 {
+			clause, clauseArgs := taxonomyFilter(t, filter.tax, userID, filter.names)
+			whereClauses = append(whereClauses, clause)
+			args = append(args, clauseArgs...)
+		}
+	}
+
+	if params.MinRating > 0 {
+		whereClauses = append(whereClauses, "rating >= ?")
+		args = append(args, params.MinRating)
+	}
+
+	if params.MaxRating > 0 {
+		whereClauses = append(whereClauses, "rating <= ?")
+		args = append(args, params.MaxRating)
+	}
+
+	if params.Rating > 0 {
+		whereClauses = append(whereClauses, "rating = ?")
+		args = append(args, params.Rating)
+	}
+
+	if params.Status != "" {
+		whereClauses = append(whereClauses, "status = ?")
+		args = append(args, params.Status)
+	}
+
+	if params.Explicit != nil {
+		whereClauses = append(whereClauses, "explicit = ?")
+		args = append(args, *params.Explicit)
+	}
+
+	if params.CreatedAfter != "" {
+		whereClauses = append(whereClauses, "created_at >= ?")
+		args = append(args, params.CreatedAfter+" 00:00:00")
+	}
+
+	if params.CreatedBefore != "" {
+		whereClauses = append(whereClauses, "created_at <= ?")
+		args = append(args, params.CreatedBefore+" 23:59:59")
+	}
+
+	return whereClauses, args
+}
+
+// taxonomyFilter matches entries carrying any of names, through the join
+// table the taxonomy triggers maintain.
+func taxonomyFilter(t *media.Type, tax *media.Taxonomy, userID any, names []string) (string, []any) {
+	clause := fmt.Sprintf(`%s.id IN (
+		SELECT m.item_id FROM %s m JOIN %s x ON x.id = m.%s
+		WHERE m.item_type = ? AND x.user_id = ? AND x.name IN (%s)
+	)`, t.Table, tax.Join, tax.Table, tax.Key, placeholders(len(names)))
+
+	args := []any{t.Name, userID}
+	for _, name := range names {
+		args = append(args, name)
+	}
+	return clause, args
+}
+
+// fieldFilters adds the filters on the type's own columns and conditions. The query has
+// already been bound to the type's search params, so numbers and booleans
+// are known to parse.
+func (h *MediaHandler) fieldFilters(c *gin.Context, whereClauses []string, args []any) ([]string, []any) {
+	for _, field := range h.typ.Fields {
+		column := field.Value()
+		switch field.Filter {
+		case media.Contains:
+			if query := c.Query(field.Column); query != "" {
+				whereClauses = append(whereClauses, column+" LIKE ?")
+				args = append(args, "%"+query+"%")
+			}
+		case media.Equals:
+			if query := c.Query(field.Column); query != "" {
+				whereClauses = append(whereClauses, column+" = ?")
+				args = append(args, query)
+			}
+		case media.Range:
+			if n, _ := strconv.Atoi(c.Query("min_" + field.Column)); n > 0 {
+				whereClauses = append(whereClauses, column+" >= ?")
+				args = append(args, n)
+			}
+			if n, _ := strconv.Atoi(c.Query("max_" + field.Column)); n > 0 {
+				whereClauses = append(whereClauses, column+" <= ?")
+				args = append(args, n)
+			}
+		case media.Flag:
+			if flag, err := strconv.ParseBool(c.Query(field.Column)); err == nil {
+				whereClauses = append(whereClauses, column+" = ?")
+				args = append(args, flag)
+			}
+		}
+	}
+
+	for _, condition := range h.typ.Conditions {
+		if value := c.Query(condition.Param); value != "" {
+			if clause, clauseArgs, ok := condition.Where(value); ok {
+				whereClauses = append(whereClauses, clause)
+				args = append(args, clauseArgs...)
+			}
+		}
+	}
+
+	return whereClauses, args
+}
+
+func (h *MediaHandler) sortable(column string) bool {
+	for _, field := range mediaSortFields {
+		if field == column {
+			return true
+		}
+	}
+	for _, field := range h.typ.Fields {
+		if field.Sortable && field.Column == column {
+			return true
+		}
+	}
+	return false
+}
+
+// scanItems reads a result set of the type's columns, followed by the two
+// snippet columns when highlights is set.
+func (h *MediaHandler) scanItems(rows *sql.Rows, highlights bool) ([]models.MediaEntry, error) {
+	items := []models.MediaEntry{}
+	for rows.Next() {
+		item := h.typ.New()
+		var thoughtsSnippet, descriptionSnippet sql.NullString
+		var extra []any
+		if highlights {
+			extra = []any{&thoughtsSnippet, &descriptionSnippet}
+		}
+
+		if err := scanMedia(rows, item, extra...); err != nil {
+			return nil, err
+		}
+
+		if highlights {
+			item.Common().Highlights = newHighlights(thoughtsSnippet, descriptionSnippet)
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// attachLinks loads the links of every item in one query and fills in the
+// cover variants.
+func (h *MediaHandler) attachLinks(ctx context.Context, items []models.MediaEntry) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*models.MediaItem, len(items))
+	args := []any{h.typ.Name}
+	for _, item := range items {
+		m := item.Common()
+		m.Links = []models.MediaLink{}
+		m.CoverVariants = h.covers.Variants(m.CoverImage)
+		byID[m.ID] = m
+		args = append(args, m.ID)
+	}
+
+	query := `SELECT id, item_id, key, value FROM media_links WHERE item_type = ? AND item_id IN (` +
+		placeholders(len(items)) + `) ORDER BY id`
+	rows, err := h.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var link models.MediaLink
+		var itemID int64
+		if err := rows.Scan(&link.ID, &itemID, &link.Key, &link.Value); err != nil {
+			return err
+		}
+		if m, ok := byID[itemID]; ok {
+			m.Links = append(m.Links, link)
+		}
+	}
+
+	return rows.Err()
+}
+
+func insertMediaLinks(ctx context.Context, q database.Querier, itemType string, itemID int64, links []models.MediaLink) error {
+	query := `INSERT INTO media_links (item_type, item_id, key, value) VALUES (?, ?, ?, ?)`
+	for _, link := range links {
+		if _, err := q.ExecContext(ctx, query, itemType, itemID, link.Key, link.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// placeholders returns n comma-separated bind parameters.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// liveEntries wraps source, a query with item_type and item_id columns, in
+// one branch per media type that appends the entry's title, cover_image,
+// status and rating and drops rows whose entry is trashed. Every branch
+// repeats source, so its arguments go in liveArgs.
+func liveEntries(source string) string {
+	branches := make([]string, 0, len(media.Types))
+	for _, t := range media.Types {
+		branches = append(branches, fmt.Sprintf(`
+		SELECT x.*, t.title, t.cover_image, t.status, t.rating FROM (%s) x
+		JOIN %s t ON x.item_type = '%s' AND t.id = x.item_id AND t.deleted_at IS NULL`, source, t.Table, t.Name))
+	}
+	return strings.Join(branches, "\n\t\tUNION ALL")
+}
+
+// liveArgs repeats the arguments of a liveEntries source once per branch.
+func liveArgs(args ...any) []any {
+	repeated := make([]any, 0, len(args)*len(media.Types))
+	for range media.Types {
+		repeated = append(repeated, args...)
+	}
+	return repeated
+}
+
+func (h *MediaHandler) ListRevisions(c *gin.Context) {
+	listRevisions(c, h.db, mediaRevisions(h.typ))
+}
+
+func (h *MediaHandler) GetRevision(c *gin.Context) {
+	getRevision(c, h.db, mediaRevisions(h.typ))
+}
+
+func (h *MediaHandler) DiffRevisions(c *gin.Context) {
+	diffRevisions(c, h.db, mediaRevisions(h.typ))
+}
+
+func (h *MediaHandler) RestoreRevision(c *gin.Context) {
+	restoreRevision(c, h.db, mediaRevisions(h.typ))
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/metadata"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+type MetadataHandler struct {
+	metadata *metadata.Registry
+}
+
+func NewMetadataHandler(providers *metadata.Registry) *MetadataHandler {
+	return &MetadataHandler{metadata: providers}
+}
+
+// ListProviders lists the configured providers.
+func (h *MetadataHandler) ListProviders(c *gin.Context) {
+	providers := []models.MetadataProvider{}
+	for _, p := range h.metadata.Providers("") {
+		providers = append(providers, models.MetadataProvider{Name: p.Name(), Label: p.Label(), ItemType: p.Type()})
+	}
+
+	c.JSON(http.StatusOK, providers)
+}
+
+// Search asks every provider for the type, or just the one named, for
+// candidates. An id is looked up directly instead.
+func (h *MetadataHandler) Search(c *gin.Context) {
+	var params models.MetadataSearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	t, ok := media.Lookup(params.Type)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type (expected one of " + media.Names() + ")"})
+		return
+	}
+
+	query := metadata.Query{
+		Title: strings.TrimSpace(params.Q),
+		ISBN:  strings.ReplaceAll(strings.TrimSpace(params.ISBN), "-", ""),
+		Limit: params.Limit,
+	}
+	id := strings.TrimSpace(params.ID)
+	if query.Title == "" && query.ISBN == "" && id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q, isbn or id is required"})
+		return
+	}
+	if query.Limit == 0 {
+		query.Limit = 10
+	}
+
+	providers := h.metadata.Providers(t.Name)
+	if params.Provider != "" {
+		p, ok := h.metadata.Lookup(params.Provider)
+		if !ok || p.Type() != t.Name {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider for " + t.Plural + " (expected one of " + h.metadata.Names(t.Name) + ")"})
+			return
+		}
+		providers = []metadata.Provider{p}
+	}
+	if len(providers) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No metadata provider is configured for " + t.Plural})
+		return
+	}
+
+	ctx := c.Request.Context()
+	response := models.MetadataSearchResponse{Results: []models.MetadataCandidate{}, Errors: map[string]string{}}
+	for _, p := range providers {
+		if id != "" {
+			candidate, err := p.Get(ctx, id)
+			switch {
+			case errors.Is(err, metadata.ErrNotFound):
+			case err != nil:
+				response.Errors[p.Name()] = err.Error()
+			default:
+				response.Results = append(response.Results, *candidate)
+			}
+			continue
+		}
+
+		candidates, err := p.Search(ctx, query)
+		if err != nil {
+			response.Errors[p.Name()] = err.Error()
+			continue
+		}
+		response.Results = append(response.Results, candidates...)
+	}
+
+	if len(response.Errors) == len(providers) {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Metadata search failed", "errors": response.Errors})
+		return
+	}
+	if len(response.Errors) == 0 {
+		response.Errors = nil
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateFromMetadata creates an entry from a provider's candidate. The rest
+// of the body is a partial create request laid over the candidate, so it
+// must add what providers don't know, such as rating, status and my_thoughts.
+// The candidate's cover is downloaded like a cover_image_url.
+func (h *MediaHandler) CreateFromMetadata(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+
+	var ref models.FromMetadataRequest
+	var overrides map[string]any
+	if err := json.Unmarshal(body, &ref); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+	if err := binding.Validator.ValidateStruct(&ref); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+	if err := json.Unmarshal(body, &overrides); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+	delete(overrides, "provider")
+	delete(overrides, "id")
+
+	provider, ok := h.metadata.Lookup(ref.Provider)
+	if !ok || provider.Type() != h.typ.Name {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid provider for " + h.typ.Plural + " (expected one of " + h.metadata.Names(h.typ.Name) + ")"})
+		return
+	}
+
+	candidate, err := provider.Get(c.Request.Context(), ref.ID)
+	if errors.Is(err, metadata.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s has no %s with ID %s", provider.Label(), h.typ.Name, ref.ID)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, utils.GenErr("Failed to fetch metadata", err))
+		return
+	}
+
+	fields := mergeImportFields(candidateFields(h.typ, candidate), overrides)
+	if !emptyImportValue(overrides["cover_image"]) {
+		delete(fields, "cover_image_url")
+	}
+
+	req := h.typ.NewCreate()
+	data, err := json.Marshal(fields)
+	if err == nil {
+		err = json.Unmarshal(data, req)
+	}
+	if err == nil {
+		err = binding.Validator.ValidateStruct(req)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+
+	h.create(c, req)
+}
+
+// candidateFields maps a candidate onto the JSON fields of t's create
+// request.
+func candidateFields(t *media.Type, candidate *models.MetadataCandidate) map[string]any {
+	fields := map[string]any{}
+	for key, value := range candidate.Fields {
+		fields[key] = value
+	}
+
+	fields["title"] = candidate.Title
+	fields["description"] = candidate.Description
+	fields["genres"] = append([]string{}, candidate.Genres...)
+	fields["tags"] = []string{}
+	fields["links"] = append([]models.MediaLink{}, candidate.Links...)
+	if candidate.Creator != "" {
+		fields[t.Creator] = candidate.Creator
+	}
+	if candidate.CoverURL != "" {
+		fields["cover_image_url"] = candidate.CoverURL
+	}
+	return fields
+}
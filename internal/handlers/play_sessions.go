@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+type PlaySessionHandler struct {
+	db *database.DB
+}
+
+func NewPlaySessionHandler(db *database.DB) *PlaySessionHandler {
+	return &PlaySessionHandler{db: db}
+}
+
+const playSessionColumns = `id, game_id, platform, started_at, ended_at, notes, user_id, created_at`
+
+func scanPlaySession(row interface{ Scan(...any) error }, s *models.PlaySession) error {
+	err := row.Scan(&s.ID, &s.GameID, &s.Platform, &s.StartedAt, &s.EndedAt, &s.Notes, &s.UserID, &s.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	if s.EndedAt != nil {
+		minutes := math.Round(s.EndedAt.Sub(s.StartedAt).Minutes()*10) / 10
+		s.Minutes = &minutes
+	}
+	return nil
+}
+
+// gameExists reports whether the game is one of the user's and not in the
+// trash.
+func gameExists(ctx context.Context, q database.Querier, gameID int64, userID any) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT 1 FROM games WHERE id = ? AND user_id = ? AND deleted_at IS NULL`, gameID, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListSessions returns a game's play sessions, most recent first.
+func (h *PlaySessionHandler) ListSessions(c *gin.Context) {
+	gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	ok, err := gameExists(c.Request.Context(), h.db, gameID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch play sessions"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(c.Request.Context(),
+		`SELECT `+playSessionColumns+` FROM play_sessions WHERE game_id = ? ORDER BY started_at DESC, id DESC`, gameID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch play sessions"})
+		return
+	}
+	defer rows.Close()
+
+	sessions := []models.PlaySession{}
+	for rows.Next() {
+		var s models.PlaySession
+		if err := scanPlaySession(rows, &s); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to scan play session", err))
+			return
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating play sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// CreateSession logs a finished session after the fact.
+func (h *PlaySessionHandler) CreateSession(c *gin.Context) {
+	gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.CreatePlaySessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+
+	if req.EndedAt.Before(req.StartedAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ended_at must not be before started_at"})
+		return
+	}
+
+	h.insertSession(c, gameID, req.Platform, req.StartedAt, &req.EndedAt, req.Notes)
+}
+
+// StartSession starts a running session; a game can only have one.
+func (h *PlaySessionHandler) StartSession(c *gin.Context) {
+	gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.StartPlaySessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+
+	startedAt := time.Now()
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+
+	h.insertSession(c, gameID, req.Platform, startedAt, nil, req.Notes)
+}
+
+func (h *PlaySessionHandler) insertSession(c *gin.Context, gameID int64, platform string, startedAt time.Time, endedAt *time.Time, notes string) {
+	userID, _ := c.Get("user_id")
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create play session"})
+		return
+	}
+	defer tx.Rollback()
+
+	ok, err := gameExists(ctx, tx, gameID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create play session"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+
+	var ended any
+	if endedAt != nil {
+		ended = endedAt.UTC().Format(sqliteTimestamp)
+	} else {
+		var running int64
+		err := tx.QueryRowContext(ctx, `SELECT id FROM play_sessions WHERE game_id = ? AND ended_at IS NULL`, gameID).Scan(&running)
+		if err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "A play session is already running", "session_id": running})
+			return
+		}
+		if err != sql.ErrNoRows {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create play session"})
+			return
+		}
+	}
+
+	var session models.PlaySession
+	err = scanPlaySession(tx.QueryRowContext(ctx, `
+		INSERT INTO play_sessions (game_id, user_id, platform, started_at, ended_at, notes)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING `+playSessionColumns,
+		gameID, userID, platform, startedAt.UTC().Format(sqliteTimestamp), ended, notes), &session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to create play session", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create play session"})
+		return
+	}
+
+	audit.Target(c, "play_session", session.ID)
+	audit.Change(c, nil, session)
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// StopSession ends the game's running session.
+func (h *PlaySessionHandler) StopSession(c *gin.Context) {
+	gameID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.StopPlaySessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+
+	endedAt := time.Now()
+	if req.EndedAt != nil {
+		endedAt = *req.EndedAt
+	}
+
+	audit.Action(c, audit.ActionUpdate)
+
+	userID, _ := c.Get("user_id")
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop play session"})
+		return
+	}
+	defer tx.Rollback()
+
+	ok, err := gameExists(ctx, tx, gameID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop play session"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Game not found"})
+		return
+	}
+
+	var before models.PlaySession
+	err = scanPlaySession(tx.QueryRowContext(ctx,
+		`SELECT `+playSessionColumns+` FROM play_sessions WHERE game_id = ? AND ended_at IS NULL`, gameID), &before)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "No play session is running"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop play session"})
+		return
+	}
+
+	audit.Target(c, "play_session", before.ID)
+
+	if endedAt.Before(before.StartedAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ended_at must not be before the session's started_at"})
+		return
+	}
+
+	notes := before.Notes
+	if req.Notes != "" {
+		notes = req.Notes
+	}
+
+	var after models.PlaySession
+	err = scanPlaySession(tx.QueryRowContext(ctx,
+		`UPDATE play_sessions SET ended_at = ?, notes = ? WHERE id = ? RETURNING `+playSessionColumns,
+		endedAt.UTC().Format(sqliteTimestamp), notes, before.ID), &after)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to stop play session", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to stop play session"})
+		return
+	}
+
+	audit.Change(c, before, after)
+
+	c.JSON(http.StatusOK, after)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func getGame(t *testing.T, h *MediaHandler, userID, id int64) models.Game {
+	t.Helper()
+
+	w := call(h.Get, http.MethodGet, userID, models.RoleNormal, param(idParam(id)...), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Get: %d %s", w.Code, w.Body)
+	}
+	var game models.Game
+	decodeBody(t, w, &game)
+	return game
+}
+
+func TestStartAndStopPlaySession(t *testing.T) {
+	db := newTestDB(t, "owner", "other")
+	h := NewPlaySessionHandler(db)
+	games := NewMediaHandler(db, newTestPipeline(t), nil, media.Game)
+	const owner, other = int64(1), int64(2)
+
+	id := newTestGame(t, db, owner, "Outer Wilds")
+	start := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	stop := func(userID int64, body any) (models.PlaySession, int) {
+		t.Helper()
+
+		w := call(h.StopSession, http.MethodPost, userID, models.RoleNormal, param(idParam(id)...), body)
+		var session models.PlaySession
+		if w.Code == http.StatusOK {
+			decodeBody(t, w, &session)
+		}
+		return session, w.Code
+	}
+
+	if _, code := stop(owner, nil); code != http.StatusConflict {
+		t.Errorf("stopping with nothing running: %d, want 409", code)
+	}
+
+	w := call(h.StartSession, http.MethodPost, owner, models.RoleNormal, param(idParam(id)...),
+		models.StartPlaySessionRequest{Platform: "pc", StartedAt: &start, Notes: "first loop"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("StartSession: %d %s", w.Code, w.Body)
+	}
+	var running models.PlaySession
+	decodeBody(t, w, &running)
+	if running.EndedAt != nil || running.Minutes != nil || !getGame(t, games, owner, id).Playing {
+		t.Errorf("running session = %+v", running)
+	}
+
+	// A game has one running session at a time; the conflict names it.
+	w = call(h.StartSession, http.MethodPost, owner, models.RoleNormal, param(idParam(id)...), nil)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("starting twice: %d, want 409", w.Code)
+	}
+	var conflict struct {
+		SessionID int64 `json:"session_id"`
+	}
+	decodeBody(t, w, &conflict)
+	if conflict.SessionID != running.ID {
+		t.Errorf("conflict names session %d, want %d", conflict.SessionID, running.ID)
+	}
+
+	before := start.Add(-time.Minute)
+	if _, code := stop(owner, models.StopPlaySessionRequest{EndedAt: &before}); code != http.StatusBadRequest {
+		t.Errorf("stopping before the start: %d, want 400", code)
+	}
+	if _, code := stop(other, nil); code != http.StatusNotFound {
+		t.Errorf("another user stopping: %d, want 404", code)
+	}
+
+	// Stopping keeps the notes unless new ones are given.
+	end := start.Add(45 * time.Minute)
+	stopped, code := stop(owner, models.StopPlaySessionRequest{EndedAt: &end})
+	if code != http.StatusOK {
+		t.Fatalf("StopSession: %d", code)
+	}
+	if stopped.ID != running.ID || stopped.Minutes == nil || *stopped.Minutes != 45 || stopped.Notes != "first loop" {
+		t.Errorf("stopped session = %+v", stopped)
+	}
+
+	game := getGame(t, games, owner, id)
+	if game.Playing || game.Playtime != 45 {
+		t.Errorf("game = playing %v, playtime %d", game.Playing, game.Playtime)
+	}
+	if _, code := stop(owner, nil); code != http.StatusConflict {
+		t.Errorf("stopping twice: %d, want 409", code)
+	}
+}
+
+func TestPlaytime(t *testing.T) {
+	db := newTestDB(t, "owner")
+	h := NewPlaySessionHandler(db)
+	games := NewMediaHandler(db, newTestPipeline(t), nil, media.Game)
+	const owner = int64(1)
+
+	ids := map[string]int64{}
+	for _, title := range []string{"Long", "Short", "Running", "Unplayed"} {
+		ids[title] = newTestGame(t, db, owner, title)
+	}
+
+	start := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	logged := func(title string, minutes int) {
+		t.Helper()
+
+		w := call(h.CreateSession, http.MethodPost, owner, models.RoleNormal, param(idParam(ids[title])...),
+			models.CreatePlaySessionRequest{StartedAt: start, EndedAt: start.Add(time.Duration(minutes) * time.Minute)})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("CreateSession: %d %s", w.Code, w.Body)
+		}
+	}
+
+	// Only finished sessions count towards playtime, so the session Running
+	// still has going adds nothing.
+	logged("Long", 90)
+	logged("Long", 30)
+	logged("Short", 20)
+	logged("Running", 15)
+	if w := call(h.StartSession, http.MethodPost, owner, models.RoleNormal, param(idParam(ids["Running"])...), nil); w.Code != http.StatusCreated {
+		t.Fatalf("StartSession: %d %s", w.Code, w.Body)
+	}
+
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE games SET percent = 100 WHERE id = ?`, []any{ids["Long"]}},
+		{`UPDATE games SET percent = 40 WHERE id = ?`, []any{ids["Short"]}},
+		{`UPDATE games SET percent = 10 WHERE id = ?`, []any{ids["Running"]}},
+	} {
+		if _, err := db.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	playtimes := map[string]int{}
+	for title, id := range ids {
+		playtimes[title] = getGame(t, games, owner, id).Playtime
+	}
+	assertJSON(t, "playtimes", playtimes, map[string]int{"Long": 120, "Short": 20, "Running": 15, "Unplayed": 0})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"min_playtime=20", []string{"Long", "Short"}},
+		{"max_playtime=20", []string{"Running", "Short", "Unplayed"}},
+		{"min_playtime=1&max_playtime=60", []string{"Running", "Short"}},
+		{"playing=true", []string{"Running"}},
+		{"min_percent=40", []string{"Long", "Short"}},
+		{"max_percent=40", []string{"Running", "Short", "Unplayed"}},
+		{"min_percent=10&max_percent=50&min_playtime=16", []string{"Short"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assertJSON(t, "titles", searchTitles(t, games, owner, tt.query), tt.want)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// UpdateProgress moves one of the type's progress counters. Reaching a known
+// total marks the entry completed.
+func (h *MediaHandler) UpdateProgress(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Invalid ID", err))
+		return
+	}
+
+	var req models.ProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Invalid JSON", err))
+		return
+	}
+
+	if (req.Current == nil) == (req.Increment == 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Exactly one of current or increment is required"})
+		return
+	}
+
+	counter, ok := h.typ.Counter(req.Unit)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unit (expected one of " + h.typ.Units() + ")"})
+		return
+	}
+
+	audit.Target(c, h.typ.Name, id)
+
+	userID, _ := c.Get("user_id")
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+	defer tx.Rollback()
+
+	var current int
+	var total sql.NullInt64
+	var status string
+	query := fmt.Sprintf("SELECT %s, %s, status FROM %s WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
+		counter.Current, counter.Total, h.typ.Table)
+	err = tx.QueryRowContext(ctx, query, id, userID).Scan(&current, &total, &status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.typ.Label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+
+	before, err := snapshotItem(ctx, tx, mediaRevisions(h.typ), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+
+	if req.Current != nil {
+		current = *req.Current
+	} else {
+		current += req.Increment
+	}
+
+	if current < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s progress cannot go below 0", counter.Unit)})
+		return
+	}
+	if total.Valid && int64(current) > total.Int64 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%d %ss is past the total of %d", current, counter.Unit, total.Int64)})
+		return
+	}
+
+	status, err = setProgress(ctx, tx, h.typ, counter, id, current, total, status, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+
+	after, err := recordRevision(ctx, tx, mediaRevisions(h.typ), id, models.RevisionUpdate, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record revision", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+		return
+	}
+
+	audit.Change(c, before, after)
+
+	response := models.ProgressResponse{
+		ID:      id,
+		Unit:    counter.Unit,
+		Current: current,
+		Status:  status,
+		Message: h.typ.Label + " progress updated",
+	}
+	if total.Valid {
+		t := int(total.Int64)
+		response.Total = &t
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// setProgress stores a counter's new value and returns the entry's status,
+// which becomes completed when the value reaches a known total (unless the
+// entry's status cannot move to completed, as when it was dropped).
+func setProgress(ctx context.Context, q database.Querier, t *media.Type, counter media.Counter, id int64, current int, total sql.NullInt64, status string, authorID any) (string, error) {
+	query := fmt.Sprintf("UPDATE %s SET %s = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", t.Table, counter.Current)
+	if _, err := q.ExecContext(ctx, query, current, id); err != nil {
+		return status, err
+	}
+
+	return completeAtTotal(ctx, q, t, id, current, total, status, authorID)
+}
+
+// completeAtTotal marks the entry completed when current has reached a known
+// total and its status allows it, and returns the entry's status.
+func completeAtTotal(ctx context.Context, q database.Querier, t *media.Type, id int64, current int, total sql.NullInt64, status string, authorID any) (string, error) {
+	if !total.Valid || total.Int64 == 0 || int64(current) != total.Int64 || !t.CanTransition(status, models.StatusCompleted) {
+		return status, nil
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET status = ? WHERE id = ?", t.Table)
+	if _, err := q.ExecContext(ctx, query, models.StatusCompleted, id); err != nil {
+		return status, err
+	}
+
+	return models.StatusCompleted, changeStatus(ctx, q, mediaRevisions(t), id, status, models.StatusCompleted, authorID)
+}
+
+// settleCounters checks the counters an update touched against the totals
+// now stored, so a PUT cannot set progress past a total any more than the
+// progress endpoint can, and completes the entry when a counter reaches its
+// total, unless the update set a status itself. It writes the 400 itself
+// and returns false in that case.
+func (h *MediaHandler) settleCounters(c *gin.Context, tx *sql.Tx, id int64, touched map[string]bool, statusSet bool) bool {
+	ctx := c.Request.Context()
+	userID, _ := c.Get("user_id")
+
+	for _, counter := range h.typ.Counters {
+		if !touched[counter.Current] && !touched[counter.Total] {
+			continue
+		}
+
+		var current int
+		var total sql.NullInt64
+		var status string
+		query := fmt.Sprintf("SELECT %s, %s, status FROM %s WHERE id = ?", counter.Current, counter.Total, h.typ.Table)
+		if err := tx.QueryRowContext(ctx, query, id).Scan(&current, &total, &status); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update " + h.typ.Name})
+			return false
+		}
+
+		if total.Valid && int64(current) > total.Int64 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%d %ss is past the total of %d", current, counter.Unit, total.Int64)})
+			return false
+		}
+
+		if statusSet {
+			continue
+		}
+		if _, err := completeAtTotal(ctx, tx, h.typ, id, current, total, status, userID); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record status", err))
+			return false
+		}
+	}
+
+	return true
+}
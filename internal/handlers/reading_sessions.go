@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"database/sql"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+type ReadingSessionHandler struct {
+	db *database.DB
+}
+
+func NewReadingSessionHandler(db *database.DB) *ReadingSessionHandler {
+	return &ReadingSessionHandler{db: db}
+}
+
+const readingSessionColumns = `id, book_id, start_page, end_page, started_at, ended_at, notes, user_id, created_at`
+
+func scanReadingSession(row interface{ Scan(...any) error }, s *models.ReadingSession) error {
+	err := row.Scan(&s.ID, &s.BookID, &s.StartPage, &s.EndPage, &s.StartedAt, &s.EndedAt, &s.Notes, &s.UserID, &s.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	s.Pages = s.EndPage - s.StartPage
+	if s.EndedAt != nil {
+		minutes := math.Round(s.EndedAt.Sub(s.StartedAt).Minutes()*10) / 10
+		s.Minutes = &minutes
+	}
+	return nil
+}
+
+// ListSessions returns a book's reading sessions, most recent first.
+func (h *ReadingSessionHandler) ListSessions(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	var exists bool
+	err = h.db.QueryRowContext(c.Request.Context(),
+		`SELECT 1 FROM books WHERE id = ? AND user_id = ? AND deleted_at IS NULL`, bookID, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading sessions"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(c.Request.Context(),
+		`SELECT `+readingSessionColumns+` FROM reading_sessions WHERE book_id = ? ORDER BY started_at DESC, id DESC`, bookID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reading sessions"})
+		return
+	}
+	defer rows.Close()
+
+	sessions := []models.ReadingSession{}
+	for rows.Next() {
+		var s models.ReadingSession
+		if err := scanReadingSession(rows, &s); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to scan reading session", err))
+			return
+		}
+		sessions = append(sessions, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating reading sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// CreateSession logs a reading session and moves the book's current page on
+// to where the session ended. Re-reading earlier pages never moves it back.
+func (h *ReadingSessionHandler) CreateSession(c *gin.Context) {
+	bookID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.CreateReadingSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Bad request body", err))
+		return
+	}
+
+	if req.EndedAt != nil && req.EndedAt.Before(req.StartedAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ended_at must not be before started_at"})
+		return
+	}
+
+	audit.Target(c, media.Book.Name, bookID)
+
+	userID, _ := c.Get("user_id")
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log reading session"})
+		return
+	}
+	defer tx.Rollback()
+
+	var current int
+	var total sql.NullInt64
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT current_page, page_count, status FROM books WHERE id = ? AND user_id = ? AND deleted_at IS NULL`,
+		bookID, userID).Scan(&current, &total, &status)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log reading session"})
+		return
+	}
+
+	startPage := current
+	if req.StartPage != nil {
+		startPage = *req.StartPage
+	}
+
+	if req.EndPage < startPage {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_page must not be before start_page"})
+		return
+	}
+	if total.Valid && int64(req.EndPage) > total.Int64 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_page is past the book's page_count"})
+		return
+	}
+
+	before, err := snapshotItem(ctx, tx, mediaRevisions(media.Book), bookID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log reading session"})
+		return
+	}
+
+	var endedAt any
+	if req.EndedAt != nil {
+		endedAt = req.EndedAt.UTC().Format(sqliteTimestamp)
+	}
+
+	var session models.ReadingSession
+	err = scanReadingSession(tx.QueryRowContext(ctx, `
+		INSERT INTO reading_sessions (book_id, user_id, start_page, end_page, started_at, ended_at, notes)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING `+readingSessionColumns,
+		bookID, userID, startPage, req.EndPage, req.StartedAt.UTC().Format(sqliteTimestamp), endedAt, req.Notes), &session)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to log reading session", err))
+		return
+	}
+
+	var after *models.RevisionContent
+	if req.EndPage > current {
+		current = req.EndPage
+		counter, _ := media.Book.Counter("page")
+		status, err = setProgress(ctx, tx, media.Book, counter, bookID, current, total, status, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update book"})
+			return
+		}
+
+		after, err = recordRevision(ctx, tx, mediaRevisions(media.Book), bookID, models.RevisionUpdate, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record revision", err))
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log reading session"})
+		return
+	}
+
+	if after != nil {
+		audit.Change(c, before, after)
+	}
+
+	response := models.ReadingSessionResponse{
+		Session:     session,
+		CurrentPage: current,
+		Status:      status,
+		Message:     "Reading session logged",
+	}
+	if total.Valid {
+		pageCount := int(total.Int64)
+		response.PageCount = &pageCount
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
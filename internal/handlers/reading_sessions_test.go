@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func getBook(t *testing.T, h *MediaHandler, userID, id int64) models.Book {
+	t.Helper()
+
+	w := call(h.Get, http.MethodGet, userID, models.RoleNormal, param(idParam(id)...), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Get: %d %s", w.Code, w.Body)
+	}
+	var book models.Book
+	decodeBody(t, w, &book)
+	return book
+}
+
+func TestReadingSessions(t *testing.T) {
+	db := newTestDB(t, "owner", "other")
+	h := NewReadingSessionHandler(db)
+	books := NewMediaHandler(db, newTestPipeline(t), nil, media.Book)
+	const owner, other = int64(1), int64(2)
+
+	id := newTestBook(t, db, owner, "Piranesi")
+	if _, err := db.Exec(`UPDATE books SET page_count = 200 WHERE id = ?`, id); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	log := func(userID int64, req models.CreateReadingSessionRequest) (models.ReadingSessionResponse, int) {
+		t.Helper()
+
+		w := call(h.CreateSession, http.MethodPost, userID, models.RoleNormal, param(idParam(id)...), req)
+		var response models.ReadingSessionResponse
+		if w.Code == http.StatusCreated {
+			decodeBody(t, w, &response)
+		}
+		return response, w.Code
+	}
+	at := func(minutes int) *time.Time {
+		t := start.Add(time.Duration(minutes) * time.Minute)
+		return &t
+	}
+
+	// The first session starts from the book's current page.
+	response, code := log(owner, models.CreateReadingSessionRequest{EndPage: 30, StartedAt: start, EndedAt: at(30)})
+	if code != http.StatusCreated {
+		t.Fatalf("first session: %d", code)
+	}
+	if response.Session.StartPage != 0 || response.Session.Pages != 30 || response.CurrentPage != 30 ||
+		response.Session.Minutes == nil || *response.Session.Minutes != 30 {
+		t.Errorf("first session = %+v", response)
+	}
+
+	// Re-reading earlier pages logs a session but leaves the page alone.
+	startPage := 10
+	response, code = log(owner, models.CreateReadingSessionRequest{StartPage: &startPage, EndPage: 20, StartedAt: *at(60)})
+	if code != http.StatusCreated || response.CurrentPage != 30 || response.Session.Minutes != nil {
+		t.Errorf("re-read = %d %+v", code, response)
+	}
+
+	// Sessions without an end time count towards progress but not speed.
+	book := getBook(t, books, owner, id)
+	if book.CurrentPage != 30 || book.Percent == nil || *book.Percent != 15 ||
+		book.PagesPerHour == nil || *book.PagesPerHour != 60 {
+		t.Errorf("book = page %d, percent %v, pages per hour %v", book.CurrentPage, book.Percent, book.PagesPerHour)
+	}
+
+	tests := []struct {
+		name   string
+		userID int64
+		req    models.CreateReadingSessionRequest
+		want   int
+	}{
+		{"end before start page", owner, models.CreateReadingSessionRequest{EndPage: 20, StartedAt: start}, http.StatusBadRequest},
+		{"past page_count", owner, models.CreateReadingSessionRequest{EndPage: 201, StartedAt: start}, http.StatusBadRequest},
+		{"ended before started", owner, models.CreateReadingSessionRequest{EndPage: 40, StartedAt: start, EndedAt: at(-1)}, http.StatusBadRequest},
+		{"another user's book", other, models.CreateReadingSessionRequest{EndPage: 40, StartedAt: start}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if _, code := log(tt.userID, tt.req); code != tt.want {
+			t.Errorf("%s: %d, want %d", tt.name, code, tt.want)
+		}
+	}
+
+	// Reaching page_count finishes the book.
+	response, code = log(owner, models.CreateReadingSessionRequest{EndPage: 200, StartedAt: *at(120)})
+	if code != http.StatusCreated || response.CurrentPage != 200 || response.Status != models.StatusCompleted {
+		t.Errorf("last session = %d %+v", code, response)
+	}
+	if book := getBook(t, books, owner, id); *book.Percent != 100 || book.Status != models.StatusCompleted {
+		t.Errorf("finished book = percent %d, status %s", *book.Percent, book.Status)
+	}
+
+	w := call(h.ListSessions, http.MethodGet, owner, models.RoleNormal, param(idParam(id)...), nil)
+	var sessions []models.ReadingSession
+	decodeBody(t, w, &sessions)
+	if len(sessions) != 3 || sessions[0].EndPage != 200 || sessions[2].EndPage != 30 {
+		t.Errorf("sessions = %+v", sessions)
+	}
+}
+
+func TestBookPercent(t *testing.T) {
+	db := newTestDB(t, "owner")
+	h := NewMediaHandler(db, newTestPipeline(t), nil, media.Book)
+	const owner = int64(1)
+
+	books := map[string]int64{}
+	for _, title := range []string{"Started", "Halfway", "Overshot", "Unknown length"} {
+		books[title] = newTestBook(t, db, owner, title)
+	}
+
+	// Overshot stands for a row saved before updates were checked against
+	// page_count.
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE books SET page_count = 300, current_page = 30 WHERE id = ?`, []any{books["Started"]}},
+		{`UPDATE books SET page_count = 300, current_page = 150 WHERE id = ?`, []any{books["Halfway"]}},
+		{`UPDATE books SET page_count = 100, current_page = 250 WHERE id = ?`, []any{books["Overshot"]}},
+		{`UPDATE books SET current_page = 40 WHERE id = ?`, []any{books["Unknown length"]}},
+	} {
+		if _, err := db.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	percents := map[string]*int{}
+	for title, id := range books {
+		percents[title] = getBook(t, h, owner, id).Percent
+	}
+	ten, fifty, hundred := 10, 50, 100
+	assertJSON(t, "percents", percents, map[string]*int{
+		"Started": &ten, "Halfway": &fifty, "Overshot": &hundred, "Unknown length": nil,
+	})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"min_percent=50", []string{"Halfway", "Overshot"}},
+		{"max_percent=50", []string{"Halfway", "Started"}},
+		{"min_percent=11&max_percent=99", []string{"Halfway"}},
+		{"min_percent=100", []string{"Overshot"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assertJSON(t, "titles", searchTitles(t, h, owner, tt.query), tt.want)
+		})
+	}
+}
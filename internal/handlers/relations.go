@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// relatedQuery reads the relations on both sides of an entry, leaving out
+// those whose other entry is trashed.
+var relatedQuery = liveEntries(`
+			SELECT id, kind, 'outgoing' AS direction, to_type AS item_type, to_id AS item_id, created_at
+			FROM media_relations WHERE from_type = ? AND from_id = ?
+			UNION ALL
+			SELECT id, kind, 'incoming', from_type, from_id, created_at
+			FROM media_relations WHERE to_type = ? AND to_id = ?`) + `
+		ORDER BY kind, id`
+
+// Related lists the entries related to this one and the series it belongs
+// to, with the progress through each.
+func (h *MediaHandler) Related(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	var exists bool
+	err = h.db.QueryRowContext(ctx, "SELECT 1 FROM "+h.typ.Table+" WHERE id = ? AND user_id = ? AND deleted_at IS NULL", id, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.typ.Label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related entries"})
+		return
+	}
+
+	response := models.RelatedResponse{
+		ItemType:  h.typ.Name,
+		ItemID:    id,
+		Relations: []models.Relation{},
+		Series:    []models.SeriesMembership{},
+	}
+
+	rows, err := h.db.QueryContext(ctx, relatedQuery, liveArgs(h.typ.Name, id, h.typ.Name, id)...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch related entries", err))
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r models.Relation
+		e := &r.Entry
+		if err := rows.Scan(&r.ID, &r.Kind, &r.Direction, &e.ItemType, &e.ItemID, &r.CreatedAt,
+			&e.Title, &e.CoverImage, &e.Status, &e.Rating); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan relation"})
+			return
+		}
+		response.Relations = append(response.Relations, r)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating relations"})
+		return
+	}
+	rows.Close()
+
+	seriesRows, err := h.db.QueryContext(ctx, `
+		SELECT s.id, s.title, e.number FROM series_entries e JOIN series s ON s.id = e.series_id
+		WHERE e.item_type = ? AND e.item_id = ? AND s.user_id = ?
+		ORDER BY s.title COLLATE NOCASE, s.id
+	`, h.typ.Name, id, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+	defer seriesRows.Close()
+
+	for seriesRows.Next() {
+		var m models.SeriesMembership
+		if err := seriesRows.Scan(&m.SeriesID, &m.Title, &m.Number); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan series"})
+			return
+		}
+		response.Series = append(response.Series, m)
+	}
+
+	if err = seriesRows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating series"})
+		return
+	}
+	seriesRows.Close()
+
+	progress, err := seriesProgress(ctx, h.db, `SELECT series_id FROM series_entries WHERE item_type = ? AND item_id = ?`, h.typ.Name, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch series progress", err))
+		return
+	}
+	for i := range response.Series {
+		response.Series[i].Progress = progress[response.Series[i].SeriesID]
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateRelation records that this entry is <kind> another entry. Both must
+// belong to the current user.
+func (h *MediaHandler) CreateRelation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.CreateRelationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, ok := media.Lookup(req.ItemType)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item_type (expected one of " + media.Names() + ")"})
+		return
+	}
+
+	if target.Name == h.typ.Name && req.ItemID == id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An entry cannot be related to itself"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create relation"})
+		return
+	}
+	defer tx.Rollback()
+
+	for _, entry := range []struct {
+		typ *media.Type
+		id  int64
+	}{{h.typ, id}, {target, req.ItemID}} {
+		var exists bool
+		err := tx.QueryRowContext(ctx, "SELECT 1 FROM "+entry.typ.Table+" WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
+			entry.id, userID).Scan(&exists)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": entry.typ.Label + " not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create relation"})
+			return
+		}
+	}
+
+	var relationID int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO media_relations (user_id, kind, from_type, from_id, to_type, to_id) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT DO NOTHING RETURNING id
+	`, userID, req.Kind, h.typ.Name, id, target.Name, req.ItemID).Scan(&relationID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": "Relation already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create relation"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create relation"})
+		return
+	}
+
+	audit.Target(c, "relation", relationID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":      relationID,
+		"message": "Relation created successfully",
+	})
+}
+
+// DeleteRelation removes a relation on either side of this entry.
+func (h *MediaHandler) DeleteRelation(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	relationID, err := strconv.ParseInt(c.Param("relation"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid relation ID"})
+		return
+	}
+
+	audit.Target(c, "relation", relationID)
+
+	userID, _ := c.Get("user_id")
+
+	result, err := h.db.ExecContext(c.Request.Context(), `
+		DELETE FROM media_relations
+		WHERE id = ? AND user_id = ? AND ((from_type = ? AND from_id = ?) OR (to_type = ? AND to_id = ?))
+	`, relationID, userID, h.typ.Name, id, h.typ.Name, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete relation"})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Relation not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Relation deleted successfully"})
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/covers"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func newTestBook(t *testing.T, db *database.DB, userID int64, title string) int64 {
+	t.Helper()
+
+	req := &models.CreateBookRequest{
+		CreateMediaRequest: models.CreateMediaRequest{
+			Title:  title,
+			Genres: models.StringArray{"fantasy"},
+			Tags:   models.StringArray{},
+			Rating: 4,
+			Status: models.StatusPlanned,
+			Links:  []models.MediaLink{},
+		},
+		Author: "Author",
+	}
+
+	id, _, err := insertEntry(context.Background(), db, media.Book, req, &covers.Cover{URL: "https://example.com/cover.png"}, "#000000", userID)
+	if err != nil {
+		t.Fatalf("insertEntry: %v", err)
+	}
+	return id
+}
+
+func idParam(id int64) []string {
+	return []string{"id", strconv.FormatInt(id, 10)}
+}
+
+// related runs the Related endpoint of h for the entry id.
+func related(t *testing.T, h *MediaHandler, userID, id int64) models.RelatedResponse {
+	t.Helper()
+
+	w := call(h.Related, http.MethodGet, userID, models.RoleNormal, param(idParam(id)...), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Related: %d %s", w.Code, w.Body)
+	}
+	var response models.RelatedResponse
+	decodeBody(t, w, &response)
+	return response
+}
+
+// relationSummary is the part of a models.Relation the tests compare.
+type relationSummary struct {
+	Kind      string
+	Direction string
+	ItemType  string
+	ItemID    int64
+}
+
+func relationSummaries(relations []models.Relation) []relationSummary {
+	summaries := []relationSummary{}
+	for _, r := range relations {
+		summaries = append(summaries, relationSummary{r.Kind, r.Direction, r.Entry.ItemType, r.Entry.ItemID})
+	}
+	return summaries
+}
+
+func createRelation(h *MediaHandler, userID, id int64, kind, itemType string, itemID int64) int {
+	return call(h.CreateRelation, http.MethodPost, userID, models.RoleNormal, param(idParam(id)...),
+		models.CreateRelationRequest{Kind: kind, ItemType: itemType, ItemID: itemID}).Code
+}
+
+func TestRelatedBothDirections(t *testing.T) {
+	db := newTestDB(t, "owner", "other")
+	pipeline := newTestPipeline(t)
+	games := NewMediaHandler(db, pipeline, nil, media.Game)
+	books := NewMediaHandler(db, pipeline, nil, media.Book)
+	const owner, other = int64(1), int64(2)
+
+	first := newTestGame(t, db, owner, "First")
+	sequel := newTestGame(t, db, owner, "Sequel")
+	novel := newTestBook(t, db, owner, "Novel")
+	theirs := newTestGame(t, db, other, "Theirs")
+
+	for _, r := range []struct {
+		h        *MediaHandler
+		id       int64
+		kind     string
+		itemType string
+		itemID   int64
+	}{
+		{games, sequel, models.RelationSequelOf, "game", first},
+		{games, first, models.RelationAdaptationOf, "book", novel},
+	} {
+		if code := createRelation(r.h, owner, r.id, r.kind, r.itemType, r.itemID); code != http.StatusCreated {
+			t.Fatalf("CreateRelation %s: %d", r.kind, code)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		code     int
+		id       int64
+		kind     string
+		itemType string
+		itemID   int64
+	}{
+		{"duplicate", http.StatusConflict, sequel, models.RelationSequelOf, "game", first},
+		{"itself", http.StatusBadRequest, first, models.RelationSameSeries, "game", first},
+		{"another user's entry", http.StatusNotFound, first, models.RelationSameSeries, "game", theirs},
+		{"unknown type", http.StatusBadRequest, first, models.RelationSameSeries, "podcast", 1},
+	}
+	for _, tt := range tests {
+		if code := createRelation(games, owner, tt.id, tt.kind, tt.itemType, tt.itemID); code != tt.code {
+			t.Errorf("%s: %d, want %d", tt.name, code, tt.code)
+		}
+	}
+
+	// Each relation shows on both of its entries, outgoing from the one it
+	// was created on and incoming on the other, whatever their types.
+	assertJSON(t, "first", relationSummaries(related(t, games, owner, first).Relations), []relationSummary{
+		{models.RelationAdaptationOf, "outgoing", "book", novel},
+		{models.RelationSequelOf, "incoming", "game", sequel},
+	})
+	assertJSON(t, "sequel", relationSummaries(related(t, games, owner, sequel).Relations), []relationSummary{
+		{models.RelationSequelOf, "outgoing", "game", first},
+	})
+	assertJSON(t, "novel", relationSummaries(related(t, books, owner, novel).Relations), []relationSummary{
+		{models.RelationAdaptationOf, "incoming", "game", first},
+	})
+
+	// A trashed entry hides its relations from the other side until it is
+	// restored.
+	if _, err := db.Exec(`UPDATE games SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, sequel); err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, "first with the sequel trashed", relationSummaries(related(t, games, owner, first).Relations), []relationSummary{
+		{models.RelationAdaptationOf, "outgoing", "book", novel},
+	})
+
+	if w := call(games.Related, http.MethodGet, other, models.RoleNormal, param(idParam(first)...), nil); w.Code != http.StatusNotFound {
+		t.Errorf("another user's Related: %d, want 404", w.Code)
+	}
+}
+
+func TestPurgeRemovesRelationsAndSeriesEntries(t *testing.T) {
+	db := newTestDB(t, "owner")
+	games := NewMediaHandler(db, newTestPipeline(t), nil, media.Game)
+	series := NewSeriesHandler(db)
+	trashHandler := NewTrashHandler(db, time.Hour)
+	const owner = int64(1)
+
+	kept := newTestGame(t, db, owner, "Kept")
+	purged := newTestGame(t, db, owner, "Purged")
+	if code := createRelation(games, owner, purged, models.RelationSequelOf, "game", kept); code != http.StatusCreated {
+		t.Fatalf("CreateRelation: %d", code)
+	}
+	seriesID := newTestSeries(t, series, owner, "Saga", kept, purged)
+
+	count := func(query string, args ...any) int {
+		t.Helper()
+
+		var n int
+		if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// Trashing keeps both, for a restore to bring back.
+	if _, err := db.Exec(`UPDATE games SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, purged); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(`SELECT COUNT(*) FROM media_relations`); n != 1 {
+		t.Errorf("relations after trashing = %d, want 1", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM series_entries WHERE series_id = ?`, seriesID); n != 2 {
+		t.Errorf("series entries after trashing = %d, want 2", n)
+	}
+
+	w := call(trashHandler.PurgeTrashItem, http.MethodDelete, owner, models.RoleNormal,
+		param("type", "game", "id", strconv.FormatInt(purged, 10)), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PurgeTrashItem: %d %s", w.Code, w.Body)
+	}
+	if n := count(`SELECT COUNT(*) FROM media_relations`); n != 0 {
+		t.Errorf("relations after purge = %d, want 0", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM series_entries WHERE item_id = ?`, purged); n != 0 {
+		t.Errorf("series entries of the purged entry = %d, want 0", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM series_entries WHERE item_id = ?`, kept); n != 1 {
+		t.Errorf("series entries of the kept entry = %d, want 1", n)
+	}
+}
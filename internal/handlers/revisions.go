@@ -0,0 +1,508 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// revisionSubject describes a table whose rows are tracked in revisions.
+// Links, for media types, live in media_links under itemType.
+type revisionSubject struct {
+	itemType string
+	table    string
+	label    string
+	links    bool
+}
+
+// Resources have no revision history; they are only snapshotted for the
+// audit log.
+var resourceSnapshots = revisionSubject{itemType: "resource", table: "resources", label: "Resource"}
+
+func mediaRevisions(t *media.Type) revisionSubject {
+	return revisionSubject{itemType: t.Name, table: t.Table, label: t.Label, links: true}
+}
+
+// sqliteTimestamp is the CURRENT_TIMESTAMP layout, so restored timestamps
+// look exactly like ones SQLite wrote itself.
+const sqliteTimestamp = "2006-01-02 15:04:05"
+
+// snapshotItem captures every column of the row plus its links. Taking
+// SELECT * keeps snapshots complete as the schema grows.
+func snapshotItem(ctx context.Context, q database.Querier, s revisionSubject, id int64) (*models.RevisionContent, error) {
+	rows, err := q.QueryContext(ctx, "SELECT * FROM "+s.table+" WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, sql.ErrNoRows
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	content := &models.RevisionContent{Row: map[string]any{}, Links: []models.RevisionLink{}}
+	for i, column := range columns {
+		switch v := values[i].(type) {
+		case []byte:
+			content.Row[column] = string(v)
+		case time.Time:
+			content.Row[column] = v.UTC().Format(sqliteTimestamp)
+		default:
+			content.Row[column] = v
+		}
+	}
+
+	if !s.links {
+		return content, nil
+	}
+
+	linkRows, err := q.QueryContext(ctx,
+		"SELECT key, value FROM media_links WHERE item_type = ? AND item_id = ? ORDER BY id", s.itemType, id)
+	if err != nil {
+		return nil, err
+	}
+	defer linkRows.Close()
+
+	for linkRows.Next() {
+		var link models.RevisionLink
+		if err := linkRows.Scan(&link.Key, &link.Value); err != nil {
+			return nil, err
+		}
+		content.Links = append(content.Links, link)
+	}
+
+	return content, linkRows.Err()
+}
+
+// recordRevision snapshots the item as it currently is in q and returns the
+// snapshot. Call it after creates and updates, and before deletes.
+func recordRevision(ctx context.Context, q database.Querier, s revisionSubject, id int64, action string, authorID any) (*models.RevisionContent, error) {
+	content, err := snapshotItem(ctx, q, s, id)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %s %d: %w", s.itemType, id, err)
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = q.ExecContext(ctx, `
+		INSERT INTO revisions (item_type, item_id, user_id, author_id, action, snapshot)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, s.itemType, id, content.Row["user_id"], authorID, action, string(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+const revisionColumns = `r.id, r.item_type, r.item_id, r.action, r.author_id, COALESCE(u.username, ''), r.created_at`
+
+func scanRevision(row interface{ Scan(...any) error }, rev *models.Revision, extra ...any) error {
+	return row.Scan(append([]any{&rev.ID, &rev.ItemType, &rev.ItemID, &rev.Action,
+		&rev.AuthorID, &rev.Author, &rev.CreatedAt}, extra...)...)
+}
+
+// revisionOwner is the user revisions are scoped to: the caller, or nil for
+// admins, who can see and restore everyone's.
+func revisionOwner(c *gin.Context) any {
+	if role, _ := c.Get("user_role"); role == models.RoleAdmin {
+		return nil
+	}
+	userID, _ := c.Get("user_id")
+	return userID
+}
+
+// loadRevision reads a revision of an item owned by owner, or by anyone when
+// owner is nil.
+func loadRevision(ctx context.Context, q database.Querier, s revisionSubject, itemID, revisionID int64, owner any) (*models.Revision, error) {
+	var rev models.Revision
+	var snapshot string
+	err := scanRevision(q.QueryRowContext(ctx, `
+		SELECT `+revisionColumns+`, r.snapshot
+		FROM revisions r LEFT JOIN users u ON u.id = r.author_id
+		WHERE r.id = ? AND r.item_type = ? AND r.item_id = ? AND (? IS NULL OR r.user_id = ?)
+	`, revisionID, s.itemType, itemID, owner, owner), &rev, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	// UseNumber keeps integer columns integral instead of float64.
+	decoder := json.NewDecoder(strings.NewReader(snapshot))
+	decoder.UseNumber()
+	if err := decoder.Decode(&rev.Snapshot); err != nil {
+		return nil, err
+	}
+	for column, value := range rev.Snapshot.Row {
+		if n, ok := value.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				rev.Snapshot.Row[column] = i
+			} else {
+				rev.Snapshot.Row[column], _ = n.Float64()
+			}
+		}
+	}
+
+	return &rev, nil
+}
+
+func listRevisions(c *gin.Context, db *database.DB, s revisionSubject) {
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	owner := revisionOwner(c)
+
+	rows, err := db.QueryContext(c.Request.Context(), `
+		SELECT `+revisionColumns+`
+		FROM revisions r LEFT JOIN users u ON u.id = r.author_id
+		WHERE r.item_type = ? AND r.item_id = ? AND (? IS NULL OR r.user_id = ?)
+		ORDER BY r.id DESC
+	`, s.itemType, itemID, owner, owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch revisions"})
+		return
+	}
+	defer rows.Close()
+
+	revisions := []models.Revision{}
+	for rows.Next() {
+		var rev models.Revision
+		if err := scanRevision(rows, &rev); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan revision"})
+			return
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating revisions"})
+		return
+	}
+
+	if len(revisions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": s.label + " not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+func getRevision(c *gin.Context, db *database.DB, s revisionSubject) {
+	itemID, revisionID, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	rev, err := loadRevision(c.Request.Context(), db, s, itemID, revisionID, revisionOwner(c))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch revision", err))
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+// diffRevisions compares two revisions of an item field by field. "to"
+// defaults to the latest revision.
+func diffRevisions(c *gin.Context, db *database.DB, s revisionSubject) {
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var params models.RevisionDiffParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	owner := revisionOwner(c)
+
+	if params.To == 0 {
+		err := db.QueryRowContext(ctx, `
+			SELECT MAX(id) FROM revisions WHERE item_type = ? AND item_id = ? AND (? IS NULL OR user_id = ?)
+		`, s.itemType, itemID, owner, owner).Scan(&params.To)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": s.label + " not found"})
+			return
+		}
+	}
+
+	from, err := loadRevision(ctx, db, s, itemID, params.From, owner)
+	if err == nil {
+		var to *models.Revision
+		to, err = loadRevision(ctx, db, s, itemID, params.To, owner)
+		if err == nil {
+			c.JSON(http.StatusOK, diffSnapshots(from, to))
+			return
+		}
+	}
+
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch revision", err))
+}
+
+func diffSnapshots(from, to *models.Revision) models.RevisionDiff {
+	diff := models.RevisionDiff{
+		Changes:      []models.RevisionChange{},
+		LinksAdded:   []models.RevisionLink{},
+		LinksRemoved: []models.RevisionLink{},
+	}
+
+	fields := map[string]bool{}
+	for field := range from.Snapshot.Row {
+		fields[field] = true
+	}
+	for field := range to.Snapshot.Row {
+		fields[field] = true
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		// updated_at changes on every write and would drown out real edits.
+		if field != "updated_at" {
+			names = append(names, field)
+		}
+	}
+	sort.Strings(names)
+
+	for _, field := range names {
+		before, after := from.Snapshot.Row[field], to.Snapshot.Row[field]
+		if !reflect.DeepEqual(before, after) {
+			diff.Changes = append(diff.Changes, models.RevisionChange{Field: field, From: before, To: after})
+		}
+	}
+
+	remaining := map[models.RevisionLink]int{}
+	for _, link := range from.Snapshot.Links {
+		remaining[link]++
+	}
+	for _, link := range to.Snapshot.Links {
+		if remaining[link] > 0 {
+			remaining[link]--
+			continue
+		}
+		diff.LinksAdded = append(diff.LinksAdded, link)
+	}
+	for _, link := range from.Snapshot.Links {
+		if remaining[link] > 0 {
+			remaining[link]--
+			diff.LinksRemoved = append(diff.LinksRemoved, link)
+		}
+	}
+
+	from.Snapshot, to.Snapshot = nil, nil
+	diff.From, diff.To = *from, *to
+	return diff
+}
+
+// restoreRevision rolls an item back to a revision, taking it out of the
+// trash or recreating it under its original ID if it has been purged.
+// Columns added since the snapshot keep their current (or default) values;
+// columns since dropped are ignored. Comments removed by a purge are not part
+// of snapshots and stay gone.
+func restoreRevision(c *gin.Context, db *database.DB, s revisionSubject) {
+	itemID, revisionID, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	audit.Target(c, s.itemType, itemID)
+	audit.Action(c, audit.ActionRestore)
+
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore revision"})
+		return
+	}
+	defer tx.Rollback()
+
+	rev, err := loadRevision(ctx, tx, s, itemID, revisionID, revisionOwner(c))
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Revision not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch revision", err))
+		return
+	}
+
+	// A purged item has no current state; before stays nil.
+	before, err := snapshotItem(ctx, tx, s, itemID)
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to restore revision", err))
+		return
+	}
+
+	if err := writeSnapshot(ctx, tx, s, itemID, rev.Snapshot); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to restore revision", err))
+		return
+	}
+
+	after, err := recordRevision(ctx, tx, s, itemID, models.RevisionRestore, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record revision", err))
+		return
+	}
+
+	var from string
+	if before != nil {
+		from, _ = before.Row["status"].(string)
+	}
+	to, _ := after.Row["status"].(string)
+	if err := logStatus(ctx, tx, s, itemID, from, to, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record status", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore revision"})
+		return
+	}
+
+	audit.Change(c, before, after)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     fmt.Sprintf("%s restored to revision %d", s.label, revisionID),
+		"id":          itemID,
+		"revision_id": revisionID,
+	})
+}
+
+func writeSnapshot(ctx context.Context, tx *sql.Tx, s revisionSubject, itemID int64, content *models.RevisionContent) error {
+	columns, err := tableColumns(ctx, tx, s.table)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	var values []any
+	for column, value := range content.Row {
+		if column == "id" || column == "updated_at" || column == "deleted_at" || !columns[column] {
+			continue
+		}
+		names = append(names, column)
+		values = append(values, value)
+	}
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT 1 FROM "+s.table+" WHERE id = ?", itemID).Scan(&exists)
+	switch {
+	case err == sql.ErrNoRows:
+		placeholders := strings.Repeat("?, ", len(names)+1)
+		query := fmt.Sprintf("INSERT INTO %s (id, %s, updated_at) VALUES (%sCURRENT_TIMESTAMP)",
+			s.table, strings.Join(names, ", "), placeholders)
+		_, err = tx.ExecContext(ctx, query, append([]any{itemID}, values...)...)
+	case err == nil:
+		var assignments bytes.Buffer
+		for _, name := range names {
+			assignments.WriteString(name + " = ?, ")
+		}
+		if columns["deleted_at"] {
+			assignments.WriteString("deleted_at = NULL, ")
+		}
+		query := fmt.Sprintf("UPDATE %s SET %supdated_at = CURRENT_TIMESTAMP WHERE id = ?", s.table, assignments.String())
+		_, err = tx.ExecContext(ctx, query, append(values, itemID)...)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !s.links {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM media_links WHERE item_type = ? AND item_id = ?", s.itemType, itemID); err != nil {
+		return err
+	}
+
+	links := make([]models.MediaLink, len(content.Links))
+	for i, link := range content.Links {
+		links[i] = models.MediaLink{Key: link.Key, Value: link.Value}
+	}
+	return insertMediaLinks(ctx, tx, s.itemType, itemID, links)
+}
+
+func tableColumns(ctx context.Context, q database.Querier, table string) (map[string]bool, error) {
+	rows, err := q.QueryContext(ctx, "SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+func revisionParams(c *gin.Context) (int64, int64, bool) {
+	itemID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, 0, false
+	}
+
+	revisionID, err := strconv.ParseInt(c.Param("rev"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision ID"})
+		return 0, 0, false
+	}
+
+	return itemID, revisionID, true
+}
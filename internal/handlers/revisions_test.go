@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	revision := func(row map[string]any, links ...models.RevisionLink) *models.Revision {
+		return &models.Revision{Snapshot: &models.RevisionContent{Row: row, Links: links}}
+	}
+	link := func(key, value string) models.RevisionLink { return models.RevisionLink{Key: key, Value: value} }
+
+	tests := []struct {
+		name    string
+		from    *models.Revision
+		to      *models.Revision
+		changes []models.RevisionChange
+		added   []models.RevisionLink
+		removed []models.RevisionLink
+	}{
+		{
+			name: "unchanged",
+			from: revision(map[string]any{"title": "A", "rating": int64(3)}, link("steam", "1")),
+			to:   revision(map[string]any{"title": "A", "rating": int64(3)}, link("steam", "1")),
+		},
+		{
+			name: "fields changed, sorted by name",
+			from: revision(map[string]any{"title": "A", "rating": int64(3), "status": "planned"}),
+			to:   revision(map[string]any{"title": "B", "rating": int64(5), "status": "planned"}),
+			changes: []models.RevisionChange{
+				{Field: "rating", From: int64(3), To: int64(5)},
+				{Field: "title", From: "A", To: "B"},
+			},
+		},
+		{
+			name: "updated_at is ignored",
+			from: revision(map[string]any{"updated_at": "2024-01-01 00:00:00"}),
+			to:   revision(map[string]any{"updated_at": "2024-02-01 00:00:00"}),
+		},
+		{
+			name: "columns added and dropped",
+			from: revision(map[string]any{"old": "x"}),
+			to:   revision(map[string]any{"new": "y"}),
+			changes: []models.RevisionChange{
+				{Field: "new", From: nil, To: "y"},
+				{Field: "old", From: "x", To: nil},
+			},
+		},
+		{
+			name:    "links added and removed",
+			from:    revision(map[string]any{}, link("steam", "1"), link("gog", "2")),
+			to:      revision(map[string]any{}, link("steam", "1"), link("itch", "3")),
+			added:   []models.RevisionLink{link("itch", "3")},
+			removed: []models.RevisionLink{link("gog", "2")},
+		},
+		{
+			name:    "duplicate links are counted",
+			from:    revision(map[string]any{}, link("steam", "1"), link("steam", "1")),
+			to:      revision(map[string]any{}, link("steam", "1")),
+			removed: []models.RevisionLink{link("steam", "1")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffSnapshots(tt.from, tt.to)
+
+			assertJSON(t, "changes", diff.Changes, orEmpty(tt.changes))
+			assertJSON(t, "links added", diff.LinksAdded, orEmpty(tt.added))
+			assertJSON(t, "links removed", diff.LinksRemoved, orEmpty(tt.removed))
+			if diff.From.Snapshot != nil || diff.To.Snapshot != nil {
+				t.Errorf("diff carries the snapshots")
+			}
+		})
+	}
+}
+
+func TestRestoreRevision(t *testing.T) {
+	ctx := context.Background()
+	db := newTestDB(t, "owner", "other")
+	const owner, other = int64(1), int64(2)
+	s := mediaRevisions(media.Game)
+
+	id := newTestGame(t, db, owner, "Original", models.MediaLink{Key: "steam", Value: "1"})
+
+	// An edit, recorded the way Update records it.
+	if _, err := db.Exec(`UPDATE games SET title = 'Edited', status = 'in_progress' WHERE id = ?`, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`DELETE FROM media_links WHERE item_type = 'game' AND item_id = ?`, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := recordRevision(ctx, db, s, id, models.RevisionUpdate, owner); err != nil {
+		t.Fatal(err)
+	}
+
+	from, err := loadRevision(ctx, db, s, id, 1, owner)
+	if err != nil {
+		t.Fatalf("loadRevision: %v", err)
+	}
+	to, err := loadRevision(ctx, db, s, id, 2, owner)
+	if err != nil {
+		t.Fatalf("loadRevision: %v", err)
+	}
+	if _, ok := from.Snapshot.Row["rating"].(int64); !ok {
+		t.Errorf("integer columns should load as int64, got %T", from.Snapshot.Row["rating"])
+	}
+	diff := diffSnapshots(from, to)
+	assertJSON(t, "changes", diff.Changes, []models.RevisionChange{
+		{Field: "status", From: "planned", To: "in_progress"},
+		{Field: "title", From: "Original", To: "Edited"},
+	})
+	assertJSON(t, "links removed", diff.LinksRemoved, []models.RevisionLink{{Key: "steam", Value: "1"}})
+
+	restore := func(userID int64, role string) int {
+		c, w := testContext(http.MethodPost, userID, role, gin.Params{{Key: "id", Value: "1"}, {Key: "rev", Value: "1"}})
+		restoreRevision(c, db, s)
+		return w.Code
+	}
+
+	tests := []struct {
+		name   string
+		setup  string
+		userID int64
+		role   string
+		status int
+	}{
+		{name: "someone else's entry", userID: other, role: models.RoleNormal, status: http.StatusNotFound},
+		{name: "owner", userID: owner, role: models.RoleNormal, status: http.StatusOK},
+		{name: "out of the trash", setup: `UPDATE games SET deleted_at = CURRENT_TIMESTAMP`, userID: owner, role: models.RoleNormal, status: http.StatusOK},
+		{name: "after a purge", setup: `DELETE FROM games`, userID: owner, role: models.RoleNormal, status: http.StatusOK},
+		{name: "admin, for another user", setup: `UPDATE games SET title = 'Edited again'`, userID: other, role: models.RoleAdmin, status: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != "" {
+				if _, err := db.Exec(tt.setup); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if status := restore(tt.userID, tt.role); status != tt.status {
+				t.Fatalf("status = %d, want %d", status, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var title, status string
+			var userID int64
+			var deleted bool
+			err := db.QueryRow(`SELECT title, status, user_id, deleted_at IS NOT NULL FROM games WHERE id = ?`, id).
+				Scan(&title, &status, &userID, &deleted)
+			if err != nil {
+				t.Fatalf("restored game: %v", err)
+			}
+			if title != "Original" || status != models.StatusPlanned || userID != owner || deleted {
+				t.Errorf("restored game = %q, %s, user %d, deleted %v", title, status, userID, deleted)
+			}
+
+			var links int
+			db.QueryRow(`SELECT COUNT(*) FROM media_links WHERE item_type = 'game' AND item_id = ?`, id).Scan(&links)
+			if links != 1 {
+				t.Errorf("restored game has %d links, want 1", links)
+			}
+		})
+	}
+}
@@ -2,476 +2,117 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/openapi"
 )
 
-type RouteHandler struct{}
+// RouteHandler describes the API. Everything it serves is derived from the
+// routes registered on router, merged with the documentation in apiDocs.
+type RouteHandler struct {
+	router    *gin.Engine
+	serverURL string
+}
 
-func NewRouteHandler() *RouteHandler {
-	return &RouteHandler{}
+func NewRouteHandler(router *gin.Engine, serverURL string) *RouteHandler {
+	return &RouteHandler{router: router, serverURL: serverURL}
 }
 
 func (h *RouteHandler) GetAllRoutes(c *gin.Context) {
-	routes := []models.RouteGroup{
-		{
-			Name:        "Authentication",
-			Description: "User authentication and profile management",
-			BasePath:    "/api/v1",
-			Routes: []models.RouteInfo{
-				{
-					Method:      "POST",
-					Path:        "/auth/register",
-					Description: "Register a new user account",
-					Protected:   false,
-					Group:       "auth",
-				},
-				{
-					Method:      "POST",
-					Path:        "/auth/login",
-					Description: "Login with username and password",
-					Protected:   false,
-					Group:       "auth",
-				},
-				{
-					Method:      "GET",
-					Path:        "/me",
-					Description: "Get current user profile",
-					Protected:   true,
-					Group:       "auth",
-				},
-			},
-		},
-		{
-			Name:        "Resources",
-			Description: "Manage generic resources",
-			BasePath:    "/api/v1/resources",
-			Routes: []models.RouteInfo{
-				{
-					Method:      "GET",
-					Path:        "",
-					Description: "Get all resources for the current user",
-					Protected:   true,
-					Group:       "resources",
-				},
-				{
-					Method:      "GET",
-					Path:        "/:id",
-					Description: "Get a specific resource by ID",
-					Protected:   true,
-					Group:       "resources",
-					Params:      []string{"id"},
-				},
-				{
-					Method:       "POST",
-					Path:         "",
-					Description:  "Create a new resource",
-					Protected:    true,
-					Group:        "resources",
-					RequiredRole: "admin",
-				},
-				{
-					Method:       "PUT",
-					Path:         "/:id",
-					Description:  "Update a resource by ID",
-					Protected:    true,
-					Group:        "resources",
-					Params:       []string{"id"},
-					RequiredRole: "admin",
-				},
-				{
-					Method:       "DELETE",
-					Path:         "/:id",
-					Description:  "Delete a resource by ID",
-					Protected:    true,
-					Group:        "resources",
-					Params:       []string{"id"},
-					RequiredRole: "admin",
-				},
-			},
-		},
-		{
-			Name:        "Games",
-			Description: "Manage game entries",
-			BasePath:    "/api/v1/games",
-			Routes: []models.RouteInfo{
-				{
-					Method:      "GET",
-					Path:        "",
-					Description: "Get all games for the current user",
-					Protected:   true,
-					Group:       "games",
-				},
-				{
-					Method:      "GET",
-					Path:        "/:id",
-					Description: "Get a specific game by ID",
-					Protected:   true,
-					Group:       "games",
-					Params:      []string{"id"},
-				},
-				{
-					Method:       "POST",
-					Path:         "",
-					Description:  "Create a new game entry (supports cover_image file upload or cover_image_url)",
-					Protected:    true,
-					Group:        "games",
-					RequiredRole: "admin",
-				},
-				{
-					Method:       "PUT",
-					Path:         "/:id",
-					Description:  "Update a game entry by ID",
-					Protected:    true,
-					Group:        "games",
-					Params:       []string{"id"},
-					RequiredRole: "admin",
-				},
-				{
-					Method:       "DELETE",
-					Path:         "/:id",
-					Description:  "Delete a game entry by ID",
-					Protected:    true,
-					Group:        "games",
-					Params:       []string{"id"},
-					RequiredRole: "admin",
-				},
-				{
-					Method:      "GET",
-					Path:        "/search",
-					Description: "Search games with various filters",
-					Protected:   true,
-					Group:       "games",
-				},
-			},
-		},
-		{
-			Name:        "Books",
-			Description: "Manage book entries",
-			BasePath:    "/api/v1/books",
-			Routes: []models.RouteInfo{
-				{
-					Method:      "GET",
-					Path:        "",
-					Description: "Get all books for the current user",
-					Protected:   true,
-					Group:       "books",
-				},
-				{
-					Method:      "GET",
-					Path:        "/:id",
-					Description: "Get a specific book by ID",
-					Protected:   true,
-					Group:       "books",
-					Params:      []string{"id"},
-				},
-				{
-					Method:       "POST",
-					Path:         "",
-					Description:  "Create a new book entry (supports cover_image file upload or cover_image_url)",
-					Protected:    true,
-					Group:        "books",
-					RequiredRole: "admin",
-				},
-				{
-					Method:       "PUT",
-					Path:         "/:id",
-					Description:  "Update a book entry by ID",
-					Protected:    true,
-					Group:        "books",
-					Params:       []string{"id"},
-					RequiredRole: "admin",
-				},
-				{
-					Method:       "DELETE",
-					Path:         "/:id",
-					Description:  "Delete a book entry by ID",
-					Protected:    true,
-					Group:        "books",
-					Params:       []string{"id"},
-					RequiredRole: "admin",
-				},
-				{
-					Method:      "GET",
-					Path:        "/search",
-					Description: "Search books with various filters",
-					Protected:   true,
-					Group:       "books",
-				},
-			},
-		},
-		{
-			Name:        "Comments",
-			Description: "Manage comments on games and books",
-			BasePath:    "/api/v1/comments",
-			Routes: []models.RouteInfo{
-				{
-					Method:      "POST",
-					Path:        "",
-					Description: "Create a new comment on a game or book",
-					Protected:   true,
-					Group:       "comments",
-				},
-				{
-					Method:      "GET",
-					Path:        "",
-					Description: "Get comments for a game or book (requires game_id or book_id query param)",
-					Protected:   true,
-					Group:       "comments",
-				},
-				{
-					Method:      "PUT",
-					Path:        "/:id",
-					Description: "Update your own comment (admins can update any comment)",
-					Protected:   true,
-					Group:       "comments",
-					Params:      []string{"id"},
-				},
-				{
-					Method:      "DELETE",
-					Path:        "/:id",
-					Description: "Delete your own comment (admins can delete any comment)",
-					Protected:   true,
-					Group:       "comments",
-					Params:      []string{"id"},
-				},
-			},
-		},
-		{
-			Name:        "Files",
-			Description: "File upload management",
-			BasePath:    "/api/v1",
-			Routes: []models.RouteInfo{
-				{
-					Method:       "POST",
-					Path:         "/upload",
-					Description:  "Upload a file (images, videos, audio)",
-					Protected:    true,
-					Group:        "files",
-					RequiredRole: "admin",
-				},
-			},
-		},
+	routes := apiDocs.Routes(h.router.Routes())
+
+	groups := []models.RouteGroup{}
+	for _, key := range apiDocs.GroupKeys(routes) {
+		groups = append(groups, routeGroup(key, routes))
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"route_groups": routes,
-		"total_groups": len(routes),
+		"route_groups": groups,
+		"total_groups": len(groups),
 	})
 }
 
-func (h *RouteHandler) GetResourcesRoutes(c *gin.Context) {
-	routes := models.RouteGroup{
-		Name:        "Resources",
-		Description: "Manage generic resources",
-		BasePath:    "/api/v1/resources",
-		Routes: []models.RouteInfo{
-			{
-				Method:      "GET",
-				Path:        "",
-				Description: "Get all resources for the current user",
-				Protected:   true,
-				Group:       "resources",
-			},
-			{
-				Method:      "GET",
-				Path:        "/:id",
-				Description: "Get a specific resource by ID",
-				Protected:   true,
-				Group:       "resources",
-				Params:      []string{"id"},
-			},
-			{
-				Method:       "POST",
-				Path:         "",
-				Description:  "Create a new resource",
-				Protected:    true,
-				Group:        "resources",
-				RequiredRole: "admin",
-			},
-			{
-				Method:       "PUT",
-				Path:         "/:id",
-				Description:  "Update a resource by ID",
-				Protected:    true,
-				Group:        "resources",
-				Params:       []string{"id"},
-				RequiredRole: "admin",
-			},
-			{
-				Method:       "DELETE",
-				Path:         "/:id",
-				Description:  "Delete a resource by ID",
-				Protected:    true,
-				Group:        "resources",
-				Params:       []string{"id"},
-				RequiredRole: "admin",
-			},
-		},
+// GroupRoutes serves the route catalogue of a single group.
+func (h *RouteHandler) GroupRoutes(group string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, routeGroup(group, apiDocs.Routes(h.router.Routes())))
 	}
+}
 
-	c.JSON(http.StatusOK, routes)
+func (h *RouteHandler) GetOpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, apiDocs.Document(h.router.Routes(), h.serverURL))
 }
 
-func (h *RouteHandler) GetGamesRoutes(c *gin.Context) {
-	routes := models.RouteGroup{
-		Name:        "Games",
-		Description: "Manage game entries",
-		BasePath:    "/api/v1/games",
-		Routes: []models.RouteInfo{
-			{
-				Method:      "GET",
-				Path:        "",
-				Description: "Get all games for the current user",
-				Protected:   true,
-				Group:       "games",
-			},
-			{
-				Method:      "GET",
-				Path:        "/:id",
-				Description: "Get a specific game by ID",
-				Protected:   true,
-				Group:       "games",
-				Params:      []string{"id"},
-			},
-			{
-				Method:       "POST",
-				Path:         "",
-				Description:  "Create a new game entry (supports cover_image file upload or cover_image_url in JSON)",
-				Protected:    true,
-				Group:        "games",
-				RequiredRole: "admin",
-			},
-			{
-				Method:       "PUT",
-				Path:         "/:id",
-				Description:  "Update a game entry by ID",
-				Protected:    true,
-				Group:        "games",
-				Params:       []string{"id"},
-				RequiredRole: "admin",
-			},
-			{
-				Method:       "DELETE",
-				Path:         "/:id",
-				Description:  "Delete a game entry by ID",
-				Protected:    true,
-				Group:        "games",
-				Params:       []string{"id"},
-				RequiredRole: "admin",
-			},
-			{
-				Method:      "GET",
-				Path:        "/search",
-				Description: "Search games with various filters",
-				Protected:   true,
-				Group:       "games",
-			},
-		},
+func routeGroup(key string, routes []openapi.Route) models.RouteGroup {
+	var members []openapi.Route
+	for _, route := range routes {
+		if route.Operation.Group == key {
+			members = append(members, route)
+		}
 	}
 
-	c.JSON(http.StatusOK, routes)
-}
+	basePath := commonBasePath(members)
+	meta := apiDocs.Group(key)
+
+	group := models.RouteGroup{
+		Name:        meta.Name,
+		Description: meta.Description,
+		BasePath:    basePath,
+		Routes:      []models.RouteInfo{},
+	}
 
-func (h *RouteHandler) GetBooksRoutes(c *gin.Context) {
-	routes := models.RouteGroup{
-		Name:        "Books",
-		Description: "Manage book entries",
-		BasePath:    "/api/v1/books",
-		Routes: []models.RouteInfo{
-			{
-				Method:      "GET",
-				Path:        "",
-				Description: "Get all books for the current user",
-				Protected:   true,
-				Group:       "books",
-			},
-			{
-				Method:      "GET",
-				Path:        "/:id",
-				Description: "Get a specific book by ID",
-				Protected:   true,
-				Group:       "books",
-				Params:      []string{"id"},
-			},
-			{
-				Method:       "POST",
-				Path:         "",
-				Description:  "Create a new book entry (supports cover_image file upload or cover_image_url in JSON)",
-				Protected:    true,
-				Group:        "books",
-				RequiredRole: "admin",
-			},
-			{
-				Method:       "PUT",
-				Path:         "/:id",
-				Description:  "Update a book entry by ID",
-				Protected:    true,
-				Group:        "books",
-				Params:       []string{"id"},
-				RequiredRole: "admin",
-			},
-			{
-				Method:       "DELETE",
-				Path:         "/:id",
-				Description:  "Delete a book entry by ID",
-				Protected:    true,
-				Group:        "books",
-				Params:       []string{"id"},
-				RequiredRole: "admin",
-			},
-			{
-				Method:      "GET",
-				Path:        "/search",
-				Description: "Search books with various filters",
-				Protected:   true,
-				Group:       "books",
-			},
-		},
+	for _, route := range members {
+		op := route.Operation
+		description := op.Summary
+		if op.Description != "" {
+			description = op.Description
+		}
+
+		info := models.RouteInfo{
+			Method:      route.Method,
+			Path:        strings.TrimPrefix(route.Path, basePath),
+			Description: description,
+			Protected:   op.Auth != openapi.Public,
+			Group:       key,
+			Params:      route.Params,
+		}
+		if op.Auth == openapi.Admin {
+			info.RequiredRole = models.RoleAdmin
+		}
+		group.Routes = append(group.Routes, info)
 	}
 
-	c.JSON(http.StatusOK, routes)
+	return group
 }
 
-func (h *RouteHandler) GetCommentsRoutes(c *gin.Context) {
-	routes := models.RouteGroup{
-		Name:        "Comments",
-		Description: "Manage comments on games and books",
-		BasePath:    "/api/v1/comments",
-		Routes: []models.RouteInfo{
-			{
-				Method:      "POST",
-				Path:        "",
-				Description: "Create a new comment on a game or book",
-				Protected:   true,
-				Group:       "comments",
-			},
-			{
-				Method:      "GET",
-				Path:        "",
-				Description: "Get comments for a game or book (requires game_id or book_id query param)",
-				Protected:   true,
-				Group:       "comments",
-			},
-			{
-				Method:      "PUT",
-				Path:        "/:id",
-				Description: "Update your own comment (admins can update any comment)",
-				Protected:   true,
-				Group:       "comments",
-				Params:      []string{"id"},
-			},
-			{
-				Method:      "DELETE",
-				Path:        "/:id",
-				Description: "Delete your own comment (admins can delete any comment)",
-				Protected:   true,
-				Group:       "comments",
-				Params:      []string{"id"},
-			},
-		},
+// commonBasePath returns the longest run of leading static path segments
+// shared by every route.
+func commonBasePath(routes []openapi.Route) string {
+	if len(routes) == 0 {
+		return ""
+	}
+
+	common := strings.Split(strings.Trim(routes[0].Path, "/"), "/")
+	for _, route := range routes[1:] {
+		segments := strings.Split(strings.Trim(route.Path, "/"), "/")
+		n := 0
+		for n < len(common) && n < len(segments) && common[n] == segments[n] {
+			n++
+		}
+		common = common[:n]
 	}
 
-	c.JSON(http.StatusOK, routes)
+	for i, segment := range common {
+		if strings.ContainsAny(segment, ":*") {
+			common = common[:i]
+			break
+		}
+	}
+
+	if len(common) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(common, "/")
 }
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+var errInvalidSearchQuery = errors.New("invalid search query")
+
+// buildFTSQuery turns the user-facing q= syntax into an FTS5 MATCH expression.
+// Supported: bare terms, "quoted phrases", prefix terms (term*), the boolean
+// operators AND, OR and NOT, and parentheses. Every term is quoted so stray
+// punctuation or column filters cannot reach the FTS5 parser.
+func buildFTSQuery(q string) (string, error) {
+	var parts []string
+	depth := 0
+	expectOperand := true
+
+	runes := []rune(q)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			if !expectOperand {
+				parts = append(parts, "AND")
+			}
+			parts = append(parts, "(")
+			depth++
+			expectOperand = true
+			i++
+
+		case r == ')':
+			if depth == 0 || expectOperand {
+				return "", errInvalidSearchQuery
+			}
+			parts = append(parts, ")")
+			depth--
+			i++
+
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return "", errInvalidSearchQuery
+			}
+			phrase := strings.TrimSpace(string(runes[i+1 : end]))
+			i = end + 1
+			if phrase == "" {
+				continue
+			}
+
+			if !expectOperand {
+				parts = append(parts, "AND")
+			}
+			term := quoteFTSTerm(phrase)
+			if i < len(runes) && runes[i] == '*' {
+				term += "*"
+				i++
+			}
+			parts = append(parts, term)
+			expectOperand = false
+
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()"`, runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+
+			if word == "NOT" && len(parts) > 0 && parts[len(parts)-1] == "AND" {
+				// FTS5's NOT is binary, so "a AND NOT b" is spelled "a NOT b".
+				parts[len(parts)-1] = "NOT"
+				continue
+			}
+
+			if word == "AND" || word == "OR" || word == "NOT" {
+				if expectOperand {
+					return "", errInvalidSearchQuery
+				}
+				parts = append(parts, word)
+				expectOperand = true
+				continue
+			}
+
+			prefix := strings.HasSuffix(word, "*")
+			word = strings.TrimRight(word, "*")
+			if word == "" {
+				continue
+			}
+
+			if !expectOperand {
+				parts = append(parts, "AND")
+			}
+			term := quoteFTSTerm(word)
+			if prefix {
+				term += "*"
+			}
+			parts = append(parts, term)
+			expectOperand = false
+		}
+	}
+
+	if len(parts) == 0 || depth != 0 || expectOperand {
+		return "", errInvalidSearchQuery
+	}
+
+	return strings.Join(parts, " "), nil
+}
+
+func quoteFTSTerm(term string) string {
+	return `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+}
+
+// isFTSSyntaxError reports whether err came from FTS5 rejecting a MATCH
+// expression rather than from the database itself.
+func isFTSSyntaxError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "fts5:")
+}
+
+// snippetStart and snippetEnd delimit matches in snippet() output. They are
+// control characters rather than <mark> tags so the entry's own text can be
+// escaped before the tags go in.
+const (
+	snippetStart = "\x02"
+	snippetEnd   = "\x03"
+)
+
+// highlightSnippet escapes a snippet for HTML and marks up its matches.
+func highlightSnippet(snippet string) string {
+	return strings.NewReplacer(snippetStart, "<mark>", snippetEnd, "</mark>").Replace(html.EscapeString(snippet))
+}
+
+// newHighlights keeps only the snippets FTS5 actually marked up; snippet()
+// returns a plain excerpt for columns that did not match.
+func newHighlights(thoughts, description sql.NullString) *models.Highlights {
+	h := &models.Highlights{}
+	if strings.Contains(thoughts.String, snippetStart) {
+		h.MyThoughts = highlightSnippet(thoughts.String)
+	}
+	if strings.Contains(description.String, snippetStart) {
+		h.Description = highlightSnippet(description.String)
+	}
+
+	if h.MyThoughts == "" && h.Description == "" {
+		return nil
+	}
+	return h
+}
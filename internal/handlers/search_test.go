@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func TestBuildFTSQuery(t *testing.T) {
+	tests := []struct {
+		q    string
+		want string
+	}{
+		{`dragon`, `"dragon"`},
+		{`  dragon   quest `, `"dragon" AND "quest"`},
+		{`"final fantasy" vii`, `"final fantasy" AND "vii"`},
+		{`drag*`, `"drag"*`},
+		{`"final fan"*`, `"final fan"*`},
+		{`zelda OR metroid`, `"zelda" OR "metroid"`},
+		{`zelda NOT metroid`, `"zelda" NOT "metroid"`},
+		{`zelda AND NOT metroid`, `"zelda" NOT "metroid"`},
+		{`(zelda OR metroid) prime`, `( "zelda" OR "metroid" ) AND "prime"`},
+		{`prime (zelda)`, `"prime" AND ( "zelda" )`},
+		{`zelda and link`, `"zelda" AND "and" AND "link"`},
+		{`title:zelda`, `"title:zelda"`},
+		{`he said ""`, `"he" AND "said"`},
+		{`NEAR(a b)`, `"NEAR" AND ( "a" AND "b" )`},
+		{`** zelda`, `"zelda"`},
+		{`café`, `"café"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			got, err := buildFTSQuery(tt.q)
+			if err != nil {
+				t.Fatalf("buildFTSQuery(%q): %v", tt.q, err)
+			}
+			if got != tt.want {
+				t.Errorf("buildFTSQuery(%q) = %s, want %s", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildFTSQueryInvalid(t *testing.T) {
+	for _, q := range []string{
+		``,
+		`   `,
+		`*`,
+		`AND`,
+		`zelda AND`,
+		`OR zelda`,
+		`zelda AND OR link`,
+		`(zelda`,
+		`zelda)`,
+		`()`,
+		`(zelda OR)`,
+		`"unterminated`,
+		`zelda"link`,
+	} {
+		t.Run(q, func(t *testing.T) {
+			if got, err := buildFTSQuery(q); err != errInvalidSearchQuery {
+				t.Errorf("buildFTSQuery(%q) = %s, %v; want errInvalidSearchQuery", q, got, err)
+			}
+		})
+	}
+}
+
+// TestBuildFTSQueryParses runs the built expressions against a real FTS5
+// table: whatever the user types must either be rejected up front or parse.
+func TestBuildFTSQueryParses(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "fts.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE VIRTUAL TABLE docs USING fts5(title, body);
+		INSERT INTO docs (title, body) VALUES ('The Legend of Zelda', 'title:zelda and link'), ('Metroid Prime', 'NEAR(a b)')`); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, q := range []string{
+		`zelda`, `zel*`, `"legend of" zelda`, `zelda OR metroid`, `(zelda OR metroid) NOT prime`,
+		`title:zelda`, `NEAR(a b)`, `body:"x`, `zelda AND NOT link`, `^zelda`, `-prime`, `a+b`,
+	} {
+		match, err := buildFTSQuery(q)
+		if err != nil {
+			continue
+		}
+
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM docs WHERE docs MATCH ?`, match).Scan(&n); err != nil {
+			t.Errorf("q %q built %s, which FTS5 rejects: %v", q, match, err)
+		}
+	}
+}
+
+func TestNewHighlights(t *testing.T) {
+	marked := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+
+	tests := []struct {
+		name        string
+		thoughts    sql.NullString
+		description sql.NullString
+		want        *models.Highlights
+	}{
+		{
+			name:     "no match",
+			thoughts: marked("plain excerpt"),
+			want:     nil,
+		},
+		{
+			name:        "match",
+			thoughts:    marked("plain excerpt"),
+			description: marked("a " + snippetStart + "dragon" + snippetEnd + " game"),
+			want:        &models.Highlights{Description: "a <mark>dragon</mark> game"},
+		},
+		{
+			name:     "entry text is escaped",
+			thoughts: marked(`<script>alert(1)</script> ` + snippetStart + "dragon" + snippetEnd + ` & "co"`),
+			want:     &models.Highlights{MyThoughts: `&lt;script&gt;alert(1)&lt;/script&gt; <mark>dragon</mark> &amp; &#34;co&#34;`},
+		},
+		{
+			name:        "literal mark tags are not matches",
+			description: marked("<mark>not a match</mark>"),
+			want:        nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newHighlights(tt.thoughts, tt.description)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil:
+				t.Errorf("newHighlights = %+v, want %+v", got, tt.want)
+			case *got != *tt.want:
+				t.Errorf("newHighlights = %+v, want %+v", *got, *tt.want)
+			}
+		})
+	}
+}
+
+// searchTitles runs a search of h's type for userID and returns the titles
+// found, in title order.
+func searchTitles(t *testing.T, h *MediaHandler, userID int64, query string) []string {
+	t.Helper()
+
+	c, w := testContext(http.MethodGet, userID, models.RoleNormal, nil)
+	c.Request.URL.RawQuery = query + "&sort_by=title"
+	h.Search(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Search(%s): %d %s", query, w.Code, w.Body)
+	}
+
+	var response struct {
+		Results []struct {
+			Title string `json:"title"`
+		} `json:"results"`
+	}
+	decodeBody(t, w, &response)
+
+	titles := []string{}
+	for _, result := range response.Results {
+		titles = append(titles, result.Title)
+	}
+	return titles
+}
+
+func TestSearchGenresAndTags(t *testing.T) {
+	db := newTestDB(t, "owner", "other")
+	h := NewMediaHandler(db, newTestPipeline(t), nil, media.Game)
+	const owner, other = int64(1), int64(2)
+
+	newTestGame(t, db, owner, "RPG")
+	puzzle := newTestGame(t, db, owner, "Puzzle")
+	cozy := newTestGame(t, db, owner, "Cozy RPG")
+	trashed := newTestGame(t, db, owner, "Trashed RPG")
+	theirs := newTestGame(t, db, other, "Their puzzle")
+
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE games SET genres = '["puzzle"]' WHERE id = ?`, []any{puzzle}},
+		{`UPDATE games SET genres = '["rpg", "sim"]', tags = '["cozy"]' WHERE id = ?`, []any{cozy}},
+		{`UPDATE games SET tags = '["cozy"]', deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, []any{trashed}},
+		{`UPDATE games SET genres = '["puzzle"]', tags = '["cozy"]' WHERE id = ?`, []any{theirs}},
+	} {
+		if _, err := db.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"genres=rpg", []string{"Cozy RPG", "RPG"}},
+		{"genres=puzzle&genres=sim", []string{"Cozy RPG", "Puzzle"}},
+		{"tags=cozy", []string{"Cozy RPG"}},
+		{"genres=rpg&tags=cozy", []string{"Cozy RPG"}},
+		{"genres=puzzle&tags=cozy", []string{}},
+		{"genres=RPG", []string{}},
+		{"genres=missing", []string{}},
+		{"", []string{"Cozy RPG", "Puzzle", "RPG"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assertJSON(t, "titles", searchTitles(t, h, owner, tt.query), tt.want)
+		})
+	}
+
+	// Restored entries are back in the join tables.
+	if _, err := db.Exec(`UPDATE games SET deleted_at = NULL WHERE id = ?`, trashed); err != nil {
+		t.Fatal(err)
+	}
+	assertJSON(t, "after restore", searchTitles(t, h, owner, "tags=cozy"), []string{"Cozy RPG", "Trashed RPG"})
+	assertJSON(t, "other user", searchTitles(t, h, other, "genres=puzzle&tags=cozy"), []string{"Their puzzle"})
+}
@@ -0,0 +1,449 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+type SeriesHandler struct {
+	db *database.DB
+}
+
+func NewSeriesHandler(db *database.DB) *SeriesHandler {
+	return &SeriesHandler{db: db}
+}
+
+// seriesEntriesQuery reads a series' entries in order with a summary of
+// each. Trashed entries drop out.
+var seriesEntriesQuery = liveEntries(`
+			SELECT id, number, added_at, item_type, item_id FROM series_entries WHERE series_id = ?`) + `
+		ORDER BY number, id`
+
+func newSeriesProgress(completed, total int) models.SeriesProgress {
+	return models.SeriesProgress{
+		Completed: completed,
+		Total:     total,
+		Label:     fmt.Sprintf("%d of %d completed", completed, total),
+	}
+}
+
+// seriesProgress counts the live and completed entries of every series whose
+// ID the ids query returns. Series without live entries are missing from the
+// map; progressOf fills them in.
+func seriesProgress(ctx context.Context, q database.Querier, ids string, args ...any) (map[int64]models.SeriesProgress, error) {
+	query := `SELECT series_id, COUNT(*), SUM(status = ?) FROM (` +
+		liveEntries(`SELECT series_id, item_type, item_id FROM series_entries WHERE series_id IN (`+ids+`)`) +
+		`) GROUP BY series_id`
+
+	rows, err := q.QueryContext(ctx, query, append([]any{models.StatusCompleted}, liveArgs(args...)...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	progress := map[int64]models.SeriesProgress{}
+	for rows.Next() {
+		var id int64
+		var total, completed int
+		if err := rows.Scan(&id, &total, &completed); err != nil {
+			return nil, err
+		}
+		progress[id] = newSeriesProgress(completed, total)
+	}
+
+	return progress, rows.Err()
+}
+
+func progressOf(progress map[int64]models.SeriesProgress, id int64) models.SeriesProgress {
+	if p, ok := progress[id]; ok {
+		return p
+	}
+	return newSeriesProgress(0, 0)
+}
+
+func (h *SeriesHandler) getSeries(ctx context.Context, id int64, userID any) (*models.Series, error) {
+	var series models.Series
+	err := h.db.QueryRowContext(ctx, `
+		SELECT id, title, description, created_at, updated_at FROM series WHERE id = ? AND user_id = ?
+	`, id, userID).Scan(&series.ID, &series.Title, &series.Description, &series.CreatedAt, &series.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+// ownSeries loads a series of the current user, writing the error response
+// itself when there is none.
+func (h *SeriesHandler) ownSeries(c *gin.Context) (*models.Series, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return nil, false
+	}
+
+	audit.Target(c, "series", id)
+
+	userID, _ := c.Get("user_id")
+
+	series, err := h.getSeries(c.Request.Context(), id, userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return nil, false
+	}
+
+	return series, true
+}
+
+// ListSeries lists the current user's series by title, each with its
+// progress.
+func (h *SeriesHandler) ListSeries(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT id, title, description, created_at, updated_at FROM series WHERE user_id = ?
+		ORDER BY title COLLATE NOCASE, id
+	`, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+	defer rows.Close()
+
+	list := []models.Series{}
+	for rows.Next() {
+		var series models.Series
+		if err := rows.Scan(&series.ID, &series.Title, &series.Description, &series.CreatedAt, &series.UpdatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan series"})
+			return
+		}
+		list = append(list, series)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating series"})
+		return
+	}
+	rows.Close()
+
+	progress, err := seriesProgress(ctx, h.db, `SELECT id FROM series WHERE user_id = ?`, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch series progress", err))
+		return
+	}
+	for i := range list {
+		list[i].Progress = progressOf(progress, list[i].ID)
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// GetSeries returns a series with its entries in order.
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	series, err := h.getSeries(ctx, id, userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch series"})
+		return
+	}
+
+	rows, err := h.db.QueryContext(ctx, seriesEntriesQuery, liveArgs(id)...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to fetch series entries", err))
+		return
+	}
+	defer rows.Close()
+
+	completed := 0
+	series.Entries = []models.SeriesEntry{}
+	for rows.Next() {
+		var entry models.SeriesEntry
+		e := &entry.Entry
+		if err := rows.Scan(&entry.ID, &entry.Number, &entry.AddedAt, &e.ItemType, &e.ItemID,
+			&e.Title, &e.CoverImage, &e.Status, &e.Rating); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan series entry"})
+			return
+		}
+		if e.Status == models.StatusCompleted {
+			completed++
+		}
+		series.Entries = append(series.Entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating series entries"})
+		return
+	}
+
+	series.Progress = newSeriesProgress(completed, len(series.Entries))
+
+	c.JSON(http.StatusOK, series)
+}
+
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var req models.CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	var id int64
+	err := h.db.QueryRowContext(c.Request.Context(), `INSERT INTO series (user_id, title, description) VALUES (?, ?, ?) RETURNING id`,
+		userID, req.Title, req.Description).Scan(&id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create series"})
+		return
+	}
+
+	audit.Target(c, "series", id)
+
+	after, _ := h.getSeries(c.Request.Context(), id, userID)
+	audit.Change(c, nil, after)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":      id,
+		"message": "Series created successfully",
+	})
+}
+
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	var req models.UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before, ok := h.ownSeries(c)
+	if !ok {
+		return
+	}
+
+	setClauses := []string{}
+	args := []any{}
+
+	if req.Title != "" {
+		setClauses = append(setClauses, "title = ?")
+		args = append(args, req.Title)
+	}
+	if req.Description != nil {
+		setClauses = append(setClauses, "description = ?")
+		args = append(args, *req.Description)
+	}
+
+	if len(setClauses) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No fields to update"})
+		return
+	}
+
+	setClauses = append(setClauses, "updated_at = CURRENT_TIMESTAMP")
+	query := `UPDATE series SET ` + strings.Join(setClauses, ", ") + ` WHERE id = ?`
+	if _, err := h.db.ExecContext(c.Request.Context(), query, append(args, before.ID)...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update series"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	after, _ := h.getSeries(c.Request.Context(), before.ID, userID)
+	audit.Change(c, before, after)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series updated successfully"})
+}
+
+// DeleteSeries removes a series and its numbering. The entries themselves
+// are untouched.
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	before, ok := h.ownSeries(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.db.ExecContext(c.Request.Context(), `DELETE FROM series WHERE id = ?`, before.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete series"})
+		return
+	}
+
+	audit.Change(c, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Series deleted successfully"})
+}
+
+// AddEntry adds one of the current user's entries to a series.
+func (h *SeriesHandler) AddEntry(c *gin.Context) {
+	var req models.AddSeriesEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, ok := media.Lookup(req.ItemType)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item_type (expected one of " + media.Names() + ")"})
+		return
+	}
+
+	series, ok := h.ownSeries(c)
+	if !ok {
+		return
+	}
+
+	audit.Action(c, audit.ActionUpdate)
+
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add entry"})
+		return
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	err = tx.QueryRowContext(ctx, "SELECT 1 FROM "+target.Table+" WHERE id = ? AND user_id = ? AND deleted_at IS NULL",
+		req.ItemID, userID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": target.Label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add entry"})
+		return
+	}
+
+	var number float64
+	if req.Number != nil {
+		number = *req.Number
+	} else {
+		var highest float64
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(number), 0) FROM series_entries WHERE series_id = ?`, series.ID).Scan(&highest); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add entry"})
+			return
+		}
+		number = math.Floor(highest) + 1
+	}
+
+	var entryID int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO series_entries (series_id, item_type, item_id, number) VALUES (?, ?, ?, ?)
+		ON CONFLICT DO NOTHING RETURNING id
+	`, series.ID, target.Name, req.ItemID, number).Scan(&entryID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusConflict, gin.H{"error": target.Label + " is already in this series"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add entry"})
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE series SET updated_at = CURRENT_TIMESTAMP WHERE id = ?`, series.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add entry"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add entry"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":      entryID,
+		"number":  number,
+		"message": "Entry added successfully",
+	})
+}
+
+// UpdateEntry renumbers an entry of a series.
+func (h *SeriesHandler) UpdateEntry(c *gin.Context) {
+	entryID, err := strconv.ParseInt(c.Param("entry"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entry ID"})
+		return
+	}
+
+	var req models.UpdateSeriesEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	series, ok := h.ownSeries(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.db.ExecContext(c.Request.Context(), `UPDATE series_entries SET number = ? WHERE id = ? AND series_id = ?`,
+		req.Number, entryID, series.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update entry"})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Entry updated successfully"})
+}
+
+// RemoveEntry takes an entry out of a series.
+func (h *SeriesHandler) RemoveEntry(c *gin.Context) {
+	entryID, err := strconv.ParseInt(c.Param("entry"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entry ID"})
+		return
+	}
+
+	series, ok := h.ownSeries(c)
+	if !ok {
+		return
+	}
+
+	audit.Action(c, audit.ActionUpdate)
+
+	result, err := h.db.ExecContext(c.Request.Context(), `DELETE FROM series_entries WHERE id = ? AND series_id = ?`, entryID, series.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove entry"})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Entry removed successfully"})
+}
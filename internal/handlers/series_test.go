@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// newTestSeries creates a series for userID and adds the games, numbered in
+// the order given.
+func newTestSeries(t *testing.T, h *SeriesHandler, userID int64, title string, games ...int64) int64 {
+	t.Helper()
+
+	w := call(h.CreateSeries, http.MethodPost, userID, models.RoleNormal, nil, models.CreateSeriesRequest{Title: title})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateSeries: %d %s", w.Code, w.Body)
+	}
+	var created struct {
+		ID int64 `json:"id"`
+	}
+	decodeBody(t, w, &created)
+
+	for _, id := range games {
+		w := call(h.AddEntry, http.MethodPost, userID, models.RoleNormal, param(idParam(created.ID)...),
+			models.AddSeriesEntryRequest{ItemType: "game", ItemID: id})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("AddEntry: %d %s", w.Code, w.Body)
+		}
+	}
+	return created.ID
+}
+
+func TestSeriesProgressIgnoresTrashedEntries(t *testing.T) {
+	db := newTestDB(t, "owner")
+	h := NewSeriesHandler(db)
+	games := NewMediaHandler(db, newTestPipeline(t), nil, media.Game)
+	const owner = int64(1)
+
+	done := newTestGame(t, db, owner, "Done")
+	planned := newTestGame(t, db, owner, "Planned")
+	trashed := newTestGame(t, db, owner, "Trashed")
+	id := newTestSeries(t, h, owner, "Saga", done, planned, trashed)
+	empty := newTestSeries(t, h, owner, "Empty")
+
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE games SET status = ? WHERE id IN (?, ?)`, []any{models.StatusCompleted, done, trashed}},
+		{`UPDATE games SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, []any{trashed}},
+	} {
+		if _, err := db.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := models.SeriesProgress{Completed: 1, Total: 2, Label: "1 of 2 completed"}
+
+	w := call(h.GetSeries, http.MethodGet, owner, models.RoleNormal, param(idParam(id)...), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetSeries: %d %s", w.Code, w.Body)
+	}
+	var series models.Series
+	decodeBody(t, w, &series)
+	assertJSON(t, "GetSeries progress", series.Progress, want)
+	entries := []int64{}
+	for _, entry := range series.Entries {
+		entries = append(entries, entry.Entry.ItemID)
+	}
+	assertJSON(t, "entries", entries, []int64{done, planned})
+
+	w = call(h.ListSeries, http.MethodGet, owner, models.RoleNormal, nil, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListSeries: %d %s", w.Code, w.Body)
+	}
+	var list []models.Series
+	decodeBody(t, w, &list)
+	progress := map[int64]models.SeriesProgress{}
+	for _, s := range list {
+		progress[s.ID] = s.Progress
+	}
+	assertJSON(t, "ListSeries progress", progress, map[int64]models.SeriesProgress{
+		id:    want,
+		empty: {Completed: 0, Total: 0, Label: "0 of 0 completed"},
+	})
+
+	memberships := related(t, games, owner, planned).Series
+	if len(memberships) != 1 || memberships[0].SeriesID != id || memberships[0].Number != 2 {
+		t.Fatalf("series of the entry = %+v", memberships)
+	}
+	assertJSON(t, "Related progress", memberships[0].Progress, want)
+}
+
+func TestSeriesEntriesOwnOnly(t *testing.T) {
+	db := newTestDB(t, "owner", "other")
+	h := NewSeriesHandler(db)
+	const owner, other = int64(1), int64(2)
+
+	theirs := newTestGame(t, db, other, "Theirs")
+	id := newTestSeries(t, h, owner, "Saga")
+
+	w := call(h.AddEntry, http.MethodPost, owner, models.RoleNormal, param(idParam(id)...),
+		models.AddSeriesEntryRequest{ItemType: "game", ItemID: theirs})
+	if w.Code != http.StatusNotFound {
+		t.Errorf("adding another user's entry: %d, want 404", w.Code)
+	}
+
+	if w := call(h.GetSeries, http.MethodGet, other, models.RoleNormal, param(idParam(id)...), nil); w.Code != http.StatusNotFound {
+		t.Errorf("another user's GetSeries: %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// GetStatuses describes the status vocabulary, its transitions, and the
+// statuses of each media type.
+func GetStatuses(c *gin.Context) {
+	types := map[string][]models.TypeStatus{}
+	for _, t := range media.Types {
+		types[t.Name] = t.Statuses
+	}
+
+	c.JSON(http.StatusOK, models.StatusVocabulary{
+		Statuses:    models.Statuses,
+		Transitions: models.StatusTransitions,
+		Types:       types,
+	})
+}
+
+// changeStatus applies the side effects of moving an entry between statuses,
+// after the new status has been written: started_at is set the first time
+// the entry goes in progress, completed_at holds the latest completion while
+// it stays completed, and the move is added to the history. from is empty
+// for new entries.
+func changeStatus(ctx context.Context, q database.Querier, s revisionSubject, id int64, from, to string, authorID any) error {
+	if from == to {
+		return nil
+	}
+
+	_, err := q.ExecContext(ctx, `
+		UPDATE `+s.table+` SET
+			started_at = CASE WHEN ? = 'in_progress' THEN COALESCE(started_at, CURRENT_TIMESTAMP) ELSE started_at END,
+			completed_at = CASE WHEN ? = 'completed' THEN CURRENT_TIMESTAMP END
+		WHERE id = ?
+	`, to, to, id)
+	if err != nil {
+		return err
+	}
+
+	return logStatus(ctx, q, s, id, from, to, authorID)
+}
+
+// logStatus adds a move to the entry's status history without touching the
+// entry itself, for restores that bring back their own timestamps.
+func logStatus(ctx context.Context, q database.Querier, s revisionSubject, id int64, from, to string, authorID any) error {
+	if from == to {
+		return nil
+	}
+
+	var fromStatus any
+	if from != "" {
+		fromStatus = from
+	}
+
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO status_history (item_type, item_id, user_id, author_id, from_status, to_status)
+		SELECT ?, id, user_id, ?, ?, ? FROM `+s.table+` WHERE id = ?
+	`, s.itemType, authorID, fromStatus, to, id)
+	return err
+}
+
+// StatusHistory lists the entry's status changes, oldest first.
+func (h *MediaHandler) StatusHistory(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	rows, err := h.db.QueryContext(c.Request.Context(), `
+		SELECT s.id, s.item_type, s.item_id, COALESCE(s.from_status, ''), s.to_status, s.author_id,
+		       COALESCE(u.username, ''), s.created_at
+		FROM status_history s LEFT JOIN users u ON u.id = s.author_id
+		WHERE s.item_type = ? AND s.item_id = ? AND s.user_id = ?
+		ORDER BY s.id
+	`, h.typ.Name, id, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch status history"})
+		return
+	}
+	defer rows.Close()
+
+	history := []models.StatusChange{}
+	for rows.Next() {
+		var change models.StatusChange
+		if err := rows.Scan(&change.ID, &change.ItemType, &change.ItemID, &change.From, &change.To,
+			&change.AuthorID, &change.Author, &change.CreatedAt); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan status change"})
+			return
+		}
+		history = append(history, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating status history"})
+		return
+	}
+
+	if len(history) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.typ.Label + " not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// TestStatusTriggersMatchTypes checks that the status triggers left by the
+// migrations accept exactly the statuses each media type declares.
+func TestStatusTriggersMatchTypes(t *testing.T) {
+	db := newTestDB(t)
+
+	for _, typ := range media.Types {
+		for _, trigger := range []string{typ.Table + "_status_insert", typ.Table + "_status_update"} {
+			t.Run(trigger, func(t *testing.T) {
+				var sql string
+				if err := db.QueryRow(`SELECT sql FROM sqlite_master WHERE type = 'trigger' AND name = ?`, trigger).Scan(&sql); err != nil {
+					t.Fatalf("trigger: %v", err)
+				}
+
+				for _, status := range models.Statuses {
+					if allowed := strings.Contains(sql, "'"+status+"'"); allowed != typ.HasStatus(status) {
+						t.Errorf("trigger allows %s: %v, type allows it: %v", status, allowed, typ.HasStatus(status))
+					}
+				}
+			})
+		}
+	}
+}
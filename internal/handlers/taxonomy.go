@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// TaxonomyHandler serves one taxonomy (tags or genres). Entries keep their
+// names in a JSON array column; renames, merges and deletes rewrite those
+// arrays and the triggers on the media tables update the join tables.
+type TaxonomyHandler struct {
+	db  *database.DB
+	tax *media.Taxonomy
+}
+
+func NewTaxonomyHandler(db *database.DB, tax *media.Taxonomy) *TaxonomyHandler {
+	return &TaxonomyHandler{db: db, tax: tax}
+}
+
+// List returns the terms in use, by name, with their usage counts.
+func (h *TaxonomyHandler) List(c *gin.Context) {
+	var params models.TermQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	terms, ok := h.terms(c, params)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// Autocomplete returns the terms starting with prefix, most used first.
+func (h *TaxonomyHandler) Autocomplete(c *gin.Context) {
+	var params models.TermQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	terms, ok := h.terms(c, params)
+	if !ok {
+		return
+	}
+
+	sort.SliceStable(terms, func(i, j int) bool { return terms[i].Count > terms[j].Count })
+
+	limit := 10
+	if params.Limit > 0 && params.Limit <= 100 {
+		limit = params.Limit
+	}
+	if len(terms) > limit {
+		terms = terms[:limit]
+	}
+
+	c.JSON(http.StatusOK, terms)
+}
+
+// terms loads the user's terms in use, sorted by name, writing the error
+// response itself.
+func (h *TaxonomyHandler) terms(c *gin.Context, params models.TermQuery) ([]models.Term, bool) {
+	userID, _ := c.Get("user_id")
+
+	whereClauses := []string{"x.user_id = ?"}
+	args := []any{userID}
+
+	if params.Prefix != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(params.Prefix)
+		whereClauses = append(whereClauses, `x.name LIKE ? ESCAPE '\'`)
+		args = append(args, escaped+"%")
+	}
+
+	if params.Type != "" {
+		if _, ok := media.Lookup(params.Type); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type (expected one of " + media.Names() + ")"})
+			return nil, false
+		}
+		whereClauses = append(whereClauses, "m.item_type = ?")
+		args = append(args, params.Type)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT x.id, x.name, m.item_type, COUNT(*)
+		FROM %s x JOIN %s m ON m.%s = x.id
+		WHERE %s
+		GROUP BY x.id, m.item_type
+		ORDER BY x.name COLLATE NOCASE, x.id
+	`, h.tax.Table, h.tax.Join, h.tax.Key, strings.Join(whereClauses, " AND "))
+
+	rows, err := h.db.QueryContext(c.Request.Context(), query, args...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + h.tax.Plural})
+		return nil, false
+	}
+	defer rows.Close()
+
+	terms := []models.Term{}
+	for rows.Next() {
+		var id int64
+		var name, itemType string
+		var count int
+		if err := rows.Scan(&id, &name, &itemType, &count); err != nil {
+			c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to scan "+h.tax.Name, err))
+			return nil, false
+		}
+
+		if len(terms) == 0 || terms[len(terms)-1].ID != id {
+			terms = append(terms, models.Term{ID: id, Name: name, Usage: map[string]int{}})
+		}
+		term := &terms[len(terms)-1]
+		term.Usage[itemType] = count
+		term.Count += count
+	}
+
+	if err := rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating " + h.tax.Plural})
+		return nil, false
+	}
+
+	return terms, true
+}
+
+// Rename renames a term on every entry. Renaming onto an existing name is a
+// merge and has to be asked for as one.
+func (h *TaxonomyHandler) Rename(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req models.RenameTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Invalid JSON", err))
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must not be blank"})
+		return
+	}
+
+	audit.Target(c, h.tax.Name, id)
+
+	userID, _ := c.Get("user_id")
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename " + h.tax.Name})
+		return
+	}
+	defer tx.Rollback()
+
+	old, err := h.termName(ctx, tx, id, userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.tax.Label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename " + h.tax.Name})
+		return
+	}
+
+	var existing int64
+	err = tx.QueryRowContext(ctx, "SELECT id FROM "+h.tax.Table+" WHERE user_id = ? AND name = ?", userID, name).Scan(&existing)
+	if err == nil && existing != id {
+		c.JSON(http.StatusConflict, gin.H{
+			"error": fmt.Sprintf("A %s named %q already exists; merge the two instead", h.tax.Name, name),
+			"id":    existing,
+		})
+		return
+	}
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename " + h.tax.Name})
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE "+h.tax.Table+" SET name = ? WHERE id = ?", name, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename " + h.tax.Name})
+		return
+	}
+
+	updated, err := rewriteTerms(ctx, tx, h.tax, userID, []string{old}, name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to rename "+h.tax.Name, err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rename " + h.tax.Name})
+		return
+	}
+
+	audit.Change(c, gin.H{"name": old}, gin.H{"name": name})
+
+	c.JSON(http.StatusOK, models.TermChangeResponse{
+		Updated: updated,
+		Message: fmt.Sprintf("%s renamed on %d entries", h.tax.Label, updated),
+	})
+}
+
+// Merge replaces the From terms with Into on every entry and removes them.
+func (h *TaxonomyHandler) Merge(c *gin.Context) {
+	var req models.MergeTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, utils.GenErr("Invalid JSON", err))
+		return
+	}
+
+	audit.Target(c, h.tax.Name, req.Into)
+
+	userID, _ := c.Get("user_id")
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge " + h.tax.Plural})
+		return
+	}
+	defer tx.Rollback()
+
+	into, err := h.termName(ctx, tx, req.Into, userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.tax.Label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge " + h.tax.Plural})
+		return
+	}
+
+	var ids []any
+	var names []string
+	for _, id := range req.From {
+		if id == req.Into {
+			continue
+		}
+
+		name, err := h.termName(ctx, tx, id, userID)
+		if err == sql.ErrNoRows {
+			c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s %d not found", h.tax.Label, id)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge " + h.tax.Plural})
+			return
+		}
+		ids = append(ids, id)
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to merge: from only names the target"})
+		return
+	}
+
+	updated, err := rewriteTerms(ctx, tx, h.tax, userID, names, into)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to merge "+h.tax.Plural, err))
+		return
+	}
+
+	query := "DELETE FROM " + h.tax.Table + " WHERE id IN (" + placeholders(len(ids)) + ")"
+	if _, err := tx.ExecContext(ctx, query, ids...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge " + h.tax.Plural})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge " + h.tax.Plural})
+		return
+	}
+
+	audit.Change(c, gin.H{"names": append(names, into)}, gin.H{"name": into})
+
+	c.JSON(http.StatusOK, models.TermChangeResponse{
+		Updated: updated,
+		Message: fmt.Sprintf("%d %s merged into %q on %d entries", len(names), h.tax.Plural, into, updated),
+	})
+}
+
+// Delete removes a term from every entry.
+func (h *TaxonomyHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	audit.Target(c, h.tax.Name, id)
+
+	userID, _ := c.Get("user_id")
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + h.tax.Name})
+		return
+	}
+	defer tx.Rollback()
+
+	name, err := h.termName(ctx, tx, id, userID)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": h.tax.Label + " not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + h.tax.Name})
+		return
+	}
+
+	updated, err := rewriteTerms(ctx, tx, h.tax, userID, []string{name}, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to delete "+h.tax.Name, err))
+		return
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+h.tax.Table+" WHERE id = ?", id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + h.tax.Name})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete " + h.tax.Name})
+		return
+	}
+
+	audit.Change(c, gin.H{"name": name}, nil)
+
+	c.JSON(http.StatusOK, models.TermChangeResponse{
+		Updated: updated,
+		Message: fmt.Sprintf("%s removed from %d entries", h.tax.Label, updated),
+	})
+}
+
+func (h *TaxonomyHandler) termName(ctx context.Context, q database.Querier, id int64, userID any) (string, error) {
+	var name string
+	err := q.QueryRowContext(ctx, "SELECT name FROM "+h.tax.Table+" WHERE id = ? AND user_id = ?", id, userID).Scan(&name)
+	return name, err
+}
+
+// rewriteTerms replaces names with replacement in the taxonomy column of
+// every entry of the user's that carries one of them, trashed entries
+// included, and records a revision for each. The arrays are rebuilt here
+// rather than in SQL so every name keeps its position; duplicates the
+// replacement creates are dropped, and an empty replacement removes the
+// names. It returns the number of entries rewritten.
+func rewriteTerms(ctx context.Context, tx *sql.Tx, tax *media.Taxonomy, userID any, names []string, replacement string) (int, error) {
+	nameArgs := make([]any, len(names))
+	for i, name := range names {
+		nameArgs[i] = name
+	}
+
+	updated := 0
+	for _, t := range media.Types {
+		query := fmt.Sprintf("SELECT id, %[2]s FROM %[1]s WHERE user_id = ? AND EXISTS (SELECT 1 FROM json_each(%[1]s.%[2]s) WHERE value IN (%[3]s))",
+			t.Table, tax.Column, placeholders(len(names)))
+		rows, err := tx.QueryContext(ctx, query, append([]any{userID}, nameArgs...)...)
+		if err != nil {
+			return 0, err
+		}
+
+		type entry struct {
+			id    int64
+			terms models.StringArray
+		}
+		var entries []entry
+		for rows.Next() {
+			var e entry
+			if err := rows.Scan(&e.id, &e.terms); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			entries = append(entries, e)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+
+		update := fmt.Sprintf("UPDATE %s SET %s = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?", t.Table, tax.Column)
+		for _, e := range entries {
+			if _, err := tx.ExecContext(ctx, update, replaceTerms(e.terms, names, replacement), e.id); err != nil {
+				return 0, err
+			}
+			if _, err := recordRevision(ctx, tx, mediaRevisions(t), e.id, models.RevisionUpdate, userID); err != nil {
+				return 0, err
+			}
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+// replaceTerms returns terms with names swapped for replacement, or dropped
+// when it is empty, keeping each remaining term where it first appears.
+func replaceTerms(terms []string, names []string, replacement string) models.StringArray {
+	result := models.StringArray{}
+	seen := map[string]bool{}
+	for _, term := range terms {
+		if slices.Contains(names, term) {
+			if replacement == "" {
+				continue
+			}
+			term = replacement
+		}
+		if !seen[term] {
+			seen[term] = true
+			result = append(result, term)
+		}
+	}
+	return result
+}
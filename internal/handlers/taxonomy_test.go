@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func TestReplaceTerms(t *testing.T) {
+	tests := []struct {
+		name        string
+		terms       []string
+		names       []string
+		replacement string
+		want        []string
+	}{
+		{"rename", []string{"a", "b", "c"}, []string{"b"}, "z", []string{"a", "z", "c"}},
+		{"rename onto a later term", []string{"a", "b", "c"}, []string{"a"}, "c", []string{"c", "b"}},
+		{"merge", []string{"x", "a", "y", "b"}, []string{"a", "b"}, "y", []string{"x", "y"}},
+		{"remove", []string{"a", "b", "c", "b"}, []string{"b"}, "", []string{"a", "c"}},
+		{"remove the last", []string{"a"}, []string{"a"}, "", []string{}},
+		{"nothing to do", []string{"a", "b"}, []string{"z"}, "y", []string{"a", "b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertJSON(t, "terms", replaceTerms(tt.terms, tt.names, tt.replacement), tt.want)
+		})
+	}
+}
+
+// termID returns the ID of the user's tag called name.
+func termID(t *testing.T, db *database.DB, userID int64, name string) int64 {
+	t.Helper()
+
+	var id int64
+	if err := db.QueryRow(`SELECT id FROM tags WHERE user_id = ? AND name = ?`, userID, name).Scan(&id); err != nil {
+		t.Fatalf("tag %s: %v", name, err)
+	}
+	return id
+}
+
+func gameTags(t *testing.T, db *database.DB, id int64) models.StringArray {
+	t.Helper()
+
+	var tags models.StringArray
+	if err := db.QueryRow(`SELECT tags FROM games WHERE id = ?`, id).Scan(&tags); err != nil {
+		t.Fatal(err)
+	}
+	return tags
+}
+
+func TestRenameAndMergeKeepOrder(t *testing.T) {
+	db := newTestDB(t, "owner", "other")
+	h := NewTaxonomyHandler(db, media.Tags)
+	const owner, other = int64(1), int64(2)
+
+	// Enough tags that ordering by anything but position would show.
+	tags := []string{"k", "j", "i", "h", "g", "f", "e", "d", "c", "b", "a", "z"}
+	long := newTestGame(t, db, owner, "Long")
+	short := newTestGame(t, db, owner, "Short")
+	trashed := newTestGame(t, db, owner, "Trashed")
+	theirs := newTestGame(t, db, other, "Theirs")
+
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE games SET tags = ? WHERE id = ?`, []any{models.StringArray(tags), long}},
+		{`UPDATE games SET tags = '["b", "x", "a"]' WHERE id = ?`, []any{short}},
+		{`UPDATE games SET tags = '["a", "x"]' WHERE id = ?`, []any{trashed}},
+		{`UPDATE games SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, []any{trashed}},
+		{`UPDATE games SET tags = '["b", "a"]' WHERE id = ?`, []any{theirs}},
+	} {
+		if _, err := db.Exec(stmt.query, stmt.args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := call(h.Rename, http.MethodPut, owner, models.RoleNormal, param(idParam(termID(t, db, owner, "e"))...),
+		models.RenameTermRequest{Name: "renamed"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Rename: %d %s", w.Code, w.Body)
+	}
+	assertJSON(t, "after rename", gameTags(t, db, long),
+		[]string{"k", "j", "i", "h", "g", "f", "renamed", "d", "c", "b", "a", "z"})
+
+	// a and b fold into x, which sits where the first of the three did.
+	w = call(h.Merge, http.MethodPost, owner, models.RoleNormal, nil, models.MergeTermsRequest{
+		From: []int64{termID(t, db, owner, "a"), termID(t, db, owner, "b")},
+		Into: termID(t, db, owner, "z"),
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("Merge: %d %s", w.Code, w.Body)
+	}
+	var response models.TermChangeResponse
+	decodeBody(t, w, &response)
+	if response.Updated != 3 {
+		t.Errorf("merge updated %d entries, want 3", response.Updated)
+	}
+
+	assertJSON(t, "long after merge", gameTags(t, db, long),
+		[]string{"k", "j", "i", "h", "g", "f", "renamed", "d", "c", "z"})
+	assertJSON(t, "short after merge", gameTags(t, db, short), []string{"z", "x"})
+	assertJSON(t, "trashed after merge", gameTags(t, db, trashed), []string{"z", "x"})
+	assertJSON(t, "other user's entry", gameTags(t, db, theirs), []string{"b", "a"})
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/trash"
+	"github.com/thebearodactyl/apiodactyl/internal/utils"
+)
+
+// trashSubject returns the subject used to snapshot a trash kind, and
+// whether the kind keeps a revision history.
+func trashSubject(kind trash.Kind) (revisionSubject, bool) {
+	if t, ok := media.Lookup(kind.Name); ok {
+		return mediaRevisions(t), true
+	}
+	return resourceSnapshots, false
+}
+
+type TrashHandler struct {
+	db        *database.DB
+	retention time.Duration
+}
+
+func NewTrashHandler(db *database.DB, retention time.Duration) *TrashHandler {
+	return &TrashHandler{db: db, retention: retention}
+}
+
+func (h *TrashHandler) GetTrash(c *gin.Context) {
+	var params models.TrashParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if _, ok := trash.Lookup(params.Type); params.Type != "" && !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type (expected one of " + trash.Names() + ")"})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+
+	items := []models.TrashItem{}
+	for _, kind := range trash.Kinds {
+		if params.Type != "" && params.Type != kind.Name {
+			continue
+		}
+
+		rows, err := h.db.QueryContext(c.Request.Context(), `
+			SELECT id, `+kind.TitleColumn+`, deleted_at FROM `+kind.Table+`
+			WHERE user_id = ? AND deleted_at IS NOT NULL
+		`, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+			return
+		}
+
+		for rows.Next() {
+			item := models.TrashItem{Type: kind.Name}
+			if err := rows.Scan(&item.ID, &item.Title, &item.DeletedAt); err != nil {
+				rows.Close()
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan trash item"})
+				return
+			}
+			item.PurgeAt = item.DeletedAt.Add(h.retention)
+			items = append(items, item)
+		}
+
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating trash"})
+			return
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *TrashHandler) RestoreTrashItem(c *gin.Context) {
+	kind, id, ok := trashParams(c)
+	if !ok {
+		return
+	}
+
+	audit.Target(c, kind.Name, id)
+	audit.Action(c, audit.ActionRestore)
+
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore item"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE `+kind.Table+` SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL
+	`, id, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore item"})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": kind.Label + " not found in trash"})
+		return
+	}
+
+	var after *models.RevisionContent
+	if subject, versioned := trashSubject(kind); versioned {
+		after, err = recordRevision(ctx, tx, subject, id, models.RevisionRestore, userID)
+	} else {
+		after, err = snapshotItem(ctx, tx, subject, id)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.GenErr("Failed to record revision", err))
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore item"})
+		return
+	}
+
+	audit.Change(c, nil, after)
+
+	c.JSON(http.StatusOK, gin.H{"message": kind.Label + " restored from trash"})
+}
+
+// PurgeTrashItem permanently deletes a trashed entry along with its links
+// and comments. Media entries can still be recreated from their revisions.
+func (h *TrashHandler) PurgeTrashItem(c *gin.Context) {
+	kind, id, ok := trashParams(c)
+	if !ok {
+		return
+	}
+
+	audit.Target(c, kind.Name, id)
+	audit.Action(c, audit.ActionPurge)
+
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge item"})
+		return
+	}
+	defer tx.Rollback()
+
+	subject, _ := trashSubject(kind)
+	before, err := snapshotItem(ctx, tx, subject, id)
+	if err != nil && err != sql.ErrNoRows {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge item"})
+		return
+	}
+
+	result, err := tx.ExecContext(ctx,
+		"DELETE FROM "+kind.Table+" WHERE id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge item"})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": kind.Label + " not found in trash"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge item"})
+		return
+	}
+
+	audit.Change(c, before, nil)
+
+	c.JSON(http.StatusOK, gin.H{"message": kind.Label + " permanently deleted"})
+}
+
+func (h *TrashHandler) EmptyTrash(c *gin.Context) {
+	audit.Action(c, audit.ActionPurge)
+
+	userID, _ := c.Get("user_id")
+	ctx := c.Request.Context()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to empty trash"})
+		return
+	}
+	defer tx.Rollback()
+
+	var purged int64
+	for _, kind := range trash.Kinds {
+		result, err := tx.ExecContext(ctx,
+			"DELETE FROM "+kind.Table+" WHERE user_id = ? AND deleted_at IS NOT NULL", userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to empty trash"})
+			return
+		}
+
+		n, _ := result.RowsAffected()
+		purged += n
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to empty trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trash emptied",
+		"purged":  purged,
+	})
+}
+
+func trashParams(c *gin.Context) (trash.Kind, int64, bool) {
+	kind, ok := trash.Lookup(c.Param("type"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type (expected one of " + trash.Names() + ")"})
+		return trash.Kind{}, 0, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return trash.Kind{}, 0, false
+	}
+
+	return kind, id, true
+}
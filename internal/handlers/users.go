@@ -0,0 +1,379 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/thebearodactyl/apiodactyl/internal/audit"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+	"github.com/thebearodactyl/apiodactyl/internal/trash"
+)
+
+const passwordResetTTL = 24 * time.Hour
+
+type UserHandler struct {
+	db *database.DB
+}
+
+func NewUserHandler(db *database.DB) *UserHandler {
+	return &UserHandler{db: db}
+}
+
+const userColumns = `id, username, email, role, disabled, password_reset_required, created_at, updated_at`
+
+func scanUser(row interface{ Scan(...any) error }, u *models.User) error {
+	return row.Scan(&u.ID, &u.Username, &u.Email, &u.Role,
+		&u.Disabled, &u.PasswordResetRequired, &u.CreatedAt, &u.UpdatedAt)
+}
+
+func (h *UserHandler) ListUsers(c *gin.Context) {
+	var params models.UserSearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	whereClauses := []string{"1 = 1"}
+	args := []any{}
+
+	if params.Query != "" {
+		whereClauses = append(whereClauses, "(username LIKE ? OR email LIKE ?)")
+		args = append(args, "%"+params.Query+"%", "%"+params.Query+"%")
+	}
+
+	if params.Role != "" {
+		whereClauses = append(whereClauses, "role = ?")
+		args = append(args, params.Role)
+	}
+
+	if params.Disabled != nil {
+		whereClauses = append(whereClauses, "disabled = ?")
+		args = append(args, *params.Disabled)
+	}
+
+	where := strings.Join(whereClauses, " AND ")
+
+	var total int
+	if err := h.db.QueryRowContext(c.Request.Context(), "SELECT COUNT(*) FROM users WHERE "+where, args...).Scan(&total); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count users"})
+		return
+	}
+
+	limit := 50
+	if params.Limit > 0 && params.Limit <= 100 {
+		limit = params.Limit
+	}
+
+	offset := max(params.Offset, 0)
+
+	query := fmt.Sprintf(`SELECT %s FROM users WHERE %s ORDER BY id LIMIT ? OFFSET ?`, userColumns, where)
+	rows, err := h.db.QueryContext(c.Request.Context(), query, append(args, limit, offset)...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var u models.User
+		if err := scanUser(rows, &u); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to scan user"})
+			return
+		}
+		users = append(users, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error iterating users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"results": users,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"count":   len(users),
+	})
+}
+
+func (h *UserHandler) GetUser(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var u models.User
+	err = scanUser(h.db.QueryRowContext(c.Request.Context(), "SELECT "+userColumns+" FROM users WHERE id = ?", id), &u)
+	if err == sql.ErrNoRows {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch user"})
+		return
+	}
+
+	var activeSessions int
+	err = h.db.QueryRowContext(c.Request.Context(), `
+		SELECT COUNT(*) FROM sessions
+		WHERE user_id = ? AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+	`, id).Scan(&activeSessions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":            u,
+		"active_sessions": activeSessions,
+	})
+}
+
+func (h *UserHandler) UpdateUserRole(c *gin.Context) {
+	id, ok := h.targetUserID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.db.ExecContext(c.Request.Context(),
+		`UPDATE users SET role = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, req.Role, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated successfully", "role": req.Role})
+}
+
+func (h *UserHandler) DisableUser(c *gin.Context) {
+	h.setDisabled(c, true)
+}
+
+func (h *UserHandler) EnableUser(c *gin.Context) {
+	h.setDisabled(c, false)
+}
+
+func (h *UserHandler) setDisabled(c *gin.Context, disabled bool) {
+	id, ok := h.targetUserID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE users SET disabled = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, disabled, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if disabled {
+		if _, err := tx.ExecContext(ctx, `UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL`, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+		return
+	}
+
+	message := "User enabled successfully"
+	if disabled {
+		message = "User disabled successfully"
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+// ForcePasswordReset locks the account until the user redeems the returned
+// one-time token via POST /auth/password-reset. Existing sessions are revoked.
+func (h *UserHandler) ForcePasswordReset(c *gin.Context) {
+	id, ok := h.targetUserID(c)
+	if !ok {
+		return
+	}
+
+	audit.Action(c, audit.ActionPasswordReset)
+
+	adminID, _ := c.Get("user_id")
+
+	token, err := randomToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate reset token"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to force password reset"})
+		return
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE users SET password_reset_required = 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to force password reset"})
+		return
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE password_resets SET used_at = CURRENT_TIMESTAMP WHERE user_id = ? AND used_at IS NULL`, []any{id}},
+		{`INSERT INTO password_resets (user_id, token_hash, created_by, expires_at) VALUES (?, ?, ?, datetime('now', ?))`, []any{id, hashToken(token), adminID, sqliteOffset(passwordResetTTL)}},
+		{`UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = ? AND revoked_at IS NULL`, []any{id}},
+	}
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to force password reset"})
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to force password reset"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Password reset required; hand the reset token to the user",
+		"reset_token": token,
+		"expires_at":  time.Now().Add(passwordResetTTL),
+	})
+}
+
+// DeleteUser deletes an account. Deleting a user deletes everything they
+// own, trash and revisions included, so accounts that still own entries are
+// only deleted with ?purge=true.
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	id, ok := h.targetUserID(c)
+	if !ok {
+		return
+	}
+
+	var params models.DeleteUserQuery
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		return
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE id = ?)`, id).Scan(&exists); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		return
+	}
+	if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	owned := 0
+	for _, kind := range trash.Kinds {
+		var n int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+kind.Table+` WHERE user_id = ?`, id).Scan(&n); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count the user's entries"})
+			return
+		}
+		owned += n
+	}
+
+	if owned > 0 && !params.Purge {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   fmt.Sprintf("The user owns %d entries (trashed ones included); delete them first, or pass purge=true to delete them for good along with the account", owned),
+			"entries": owned,
+		})
+		return
+	}
+
+	// Deleting the entries one table at a time, rather than through the
+	// users foreign keys, runs the per-type cleanup triggers.
+	for _, kind := range trash.Kinds {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM `+kind.Table+` WHERE user_id = ?`, id); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete the user's entries"})
+			return
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete user"})
+		return
+	}
+
+	audit.Change(c, nil, gin.H{"purged_entries": owned})
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
+
+// targetUserID parses the :id param and refuses operations an admin could use
+// to lock themselves out (demoting, disabling or deleting their own account).
+func (h *UserHandler) targetUserID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+
+	audit.Target(c, "user", id)
+
+	if userID, _ := c.Get("user_id"); userID == id {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change your own account from the admin API"})
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// addSessions opens a live session for userID under each of ids.
+func addSessions(t *testing.T, db *database.DB, userID int64, ids ...string) {
+	t.Helper()
+
+	for _, id := range ids {
+		if _, err := db.Exec(`INSERT INTO sessions (id, user_id, expires_at) VALUES (?, ?, datetime('now', '+1 day'))`, id, userID); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func activeSessions(t *testing.T, h *UserHandler, userID string) int {
+	t.Helper()
+
+	w := call(h.GetUser, http.MethodGet, 1, models.RoleAdmin, param("id", userID), nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetUser: %d %s", w.Code, w.Body)
+	}
+	var response struct {
+		User           models.User `json:"user"`
+		ActiveSessions int         `json:"active_sessions"`
+	}
+	decodeBody(t, w, &response)
+	return response.ActiveSessions
+}
+
+func TestUpdateUserRole(t *testing.T) {
+	db := newTestDB(t, "admin", "alice")
+	h := NewUserHandler(db)
+
+	w := call(h.UpdateUserRole, http.MethodPut, 1, models.RoleAdmin, param("id", "2"),
+		models.UpdateUserRoleRequest{Role: models.RoleAdmin})
+	if w.Code != http.StatusOK {
+		t.Fatalf("UpdateUserRole: %d %s", w.Code, w.Body)
+	}
+	var role string
+	db.QueryRow(`SELECT role FROM users WHERE id = 2`).Scan(&role)
+	if role != models.RoleAdmin {
+		t.Errorf("role = %q, want admin", role)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		role string
+		want int
+	}{
+		{"unknown role", "2", "owner", http.StatusBadRequest},
+		{"missing user", "99", models.RoleNormal, http.StatusNotFound},
+		{"yourself", "1", models.RoleNormal, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := call(h.UpdateUserRole, http.MethodPut, 1, models.RoleAdmin, param("id", tt.id),
+				models.UpdateUserRoleRequest{Role: tt.role})
+			if w.Code != tt.want {
+				t.Errorf("UpdateUserRole: %d %s, want %d", w.Code, w.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisableAndEnableUser(t *testing.T) {
+	db := newTestDB(t, "admin", "alice", "bob")
+	h := NewUserHandler(db)
+	addSessions(t, db, 2, "laptop", "phone")
+	addSessions(t, db, 3, "bob")
+
+	if n := activeSessions(t, h, "2"); n != 2 {
+		t.Fatalf("active sessions = %d, want 2", n)
+	}
+
+	if w := call(h.DisableUser, http.MethodPost, 1, models.RoleAdmin, param("id", "2"), nil); w.Code != http.StatusOK {
+		t.Fatalf("DisableUser: %d %s", w.Code, w.Body)
+	}
+	var disabled bool
+	db.QueryRow(`SELECT disabled FROM users WHERE id = 2`).Scan(&disabled)
+	if !disabled {
+		t.Error("user is not disabled")
+	}
+	if n := activeSessions(t, h, "2"); n != 0 {
+		t.Errorf("active sessions after disable = %d, want 0", n)
+	}
+	if n := activeSessions(t, h, "3"); n != 1 {
+		t.Errorf("another user's sessions after disable = %d, want 1", n)
+	}
+
+	// Enabling lets the user sign in again but brings no session back.
+	if w := call(h.EnableUser, http.MethodPost, 1, models.RoleAdmin, param("id", "2"), nil); w.Code != http.StatusOK {
+		t.Fatalf("EnableUser: %d %s", w.Code, w.Body)
+	}
+	db.QueryRow(`SELECT disabled FROM users WHERE id = 2`).Scan(&disabled)
+	if disabled {
+		t.Error("user is still disabled")
+	}
+	if n := activeSessions(t, h, "2"); n != 0 {
+		t.Errorf("active sessions after enable = %d, want 0", n)
+	}
+
+	if w := call(h.DisableUser, http.MethodPost, 1, models.RoleAdmin, param("id", "99"), nil); w.Code != http.StatusNotFound {
+		t.Errorf("disabling a missing user: %d, want 404", w.Code)
+	}
+}
+
+func TestAdminCannotActOnThemselves(t *testing.T) {
+	db := newTestDB(t, "admin")
+	h := NewUserHandler(db)
+	addSessions(t, db, 1, "admin")
+	if _, err := db.Exec(`UPDATE users SET role = ? WHERE id = 1`, models.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+
+	handlers := map[string]func() int{
+		"role": func() int {
+			return call(h.UpdateUserRole, http.MethodPut, 1, models.RoleAdmin, param("id", "1"),
+				models.UpdateUserRoleRequest{Role: models.RoleNormal}).Code
+		},
+		"disable": func() int {
+			return call(h.DisableUser, http.MethodPost, 1, models.RoleAdmin, param("id", "1"), nil).Code
+		},
+		"password reset": func() int {
+			return call(h.ForcePasswordReset, http.MethodPost, 1, models.RoleAdmin, param("id", "1"), nil).Code
+		},
+		"delete": func() int {
+			return call(h.DeleteUser, http.MethodDelete, 1, models.RoleAdmin, param("id", "1"), nil).Code
+		},
+	}
+	for name, run := range handlers {
+		if code := run(); code != http.StatusBadRequest {
+			t.Errorf("%s: %d, want 400", name, code)
+		}
+	}
+
+	var role string
+	var disabled, resetRequired bool
+	db.QueryRow(`SELECT role, disabled, password_reset_required FROM users WHERE id = 1`).Scan(&role, &disabled, &resetRequired)
+	if role != models.RoleAdmin || disabled || resetRequired {
+		t.Errorf("account changed: role %q, disabled %v, reset required %v", role, disabled, resetRequired)
+	}
+	if n := activeSessions(t, h, "1"); n != 1 {
+		t.Errorf("active sessions = %d, want 1", n)
+	}
+}
+
+func TestDeleteUserWithEntries(t *testing.T) {
+	db := newTestDB(t, "admin", "alice", "bob")
+	h := NewUserHandler(db)
+	newTestGame(t, db, 2, "Kept")
+	trashed := newTestGame(t, db, 2, "Trashed")
+	if _, err := db.Exec(`UPDATE games SET deleted_at = CURRENT_TIMESTAMP WHERE id = ?`, trashed); err != nil {
+		t.Fatal(err)
+	}
+	newTestGame(t, db, 3, "Bob's")
+
+	// Trashed entries count too.
+	w := call(h.DeleteUser, http.MethodDelete, 1, models.RoleAdmin, param("id", "2"), nil)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("DeleteUser without purge: %d %s, want 409", w.Code, w.Body)
+	}
+	var conflict struct {
+		Entries int `json:"entries"`
+	}
+	decodeBody(t, w, &conflict)
+	if conflict.Entries != 2 {
+		t.Errorf("entries = %d, want 2", conflict.Entries)
+	}
+
+	count := func(query string, args ...any) int {
+		var n int
+		if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	if count(`SELECT COUNT(*) FROM users WHERE id = 2`) != 1 || count(`SELECT COUNT(*) FROM games`) != 3 {
+		t.Fatal("refused delete changed the database")
+	}
+
+	c, w := testContext(http.MethodDelete, 1, models.RoleAdmin, param("id", "2"))
+	c.Request.URL.RawQuery = "purge=true"
+	h.DeleteUser(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("DeleteUser with purge: %d %s", w.Code, w.Body)
+	}
+	if n := count(`SELECT COUNT(*) FROM users WHERE id = 2`); n != 0 {
+		t.Error("user still exists")
+	}
+	if n := count(`SELECT COUNT(*) FROM games WHERE user_id = 2`); n != 0 {
+		t.Errorf("%d of the user's games left", n)
+	}
+	if n := count(`SELECT COUNT(*) FROM games WHERE user_id = 3`); n != 1 {
+		t.Errorf("another user's games = %d, want 1", n)
+	}
+
+	// Without entries, no purge is needed.
+	if _, err := db.Exec(`DELETE FROM games WHERE user_id = 3`); err != nil {
+		t.Fatal(err)
+	}
+	if w := call(h.DeleteUser, http.MethodDelete, 1, models.RoleAdmin, param("id", "3"), nil); w.Code != http.StatusOK {
+		t.Errorf("deleting a user without entries: %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,146 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+
+	_ "image/gif"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// VariantWidths is the allow-list of widths covers are resized to, both
+// eagerly on upload and lazily through /files/:key?w=.
+var VariantWidths = []int{160, 320, 640}
+
+// DefaultFormat is the format eager variants are generated in.
+const DefaultFormat = "jpeg"
+
+// formatContentTypes lists the formats we can encode.
+var formatContentTypes = map[string]string{
+	"jpeg": "image/jpeg",
+	"png":  "image/png",
+}
+
+var formatAliases = map[string]string{
+	"jpg": "jpeg",
+}
+
+// unencodableFormats are known image formats we cannot write: WebP and AVIF
+// have no pure-Go encoder.
+var unencodableFormats = map[string]bool{
+	"webp": true,
+	"avif": true,
+}
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format")
+	ErrUnencodableFormat = errors.New("image format cannot be encoded")
+	ErrUnsupportedImage  = errors.New("unsupported or corrupt image")
+)
+
+// ResolveFormat maps a requested output format onto one we can encode. Known
+// formats we cannot encode are reported as ErrUnencodableFormat rather than
+// silently served as something else.
+func ResolveFormat(format string) (string, error) {
+	if format == "" {
+		return DefaultFormat, nil
+	}
+	if _, ok := formatContentTypes[format]; ok {
+		return format, nil
+	}
+	if alias, ok := formatAliases[format]; ok {
+		return alias, nil
+	}
+	if unencodableFormats[format] {
+		return "", fmt.Errorf("%w %q", ErrUnencodableFormat, format)
+	}
+	return "", fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+}
+
+// Formats lists the format names ResolveFormat accepts.
+func Formats() []string {
+	formats := make([]string, 0, len(formatContentTypes)+len(formatAliases))
+	for format := range formatContentTypes {
+		formats = append(formats, format)
+	}
+	for alias := range formatAliases {
+		formats = append(formats, alias)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+func ContentType(format string) string {
+	return formatContentTypes[format]
+}
+
+func IsVariantWidth(width int) bool {
+	for _, w := range VariantWidths {
+		if w == width {
+			return true
+		}
+	}
+	return false
+}
+
+// Decode reads a PNG, JPEG, GIF or WebP image.
+func Decode(r io.Reader) (image.Image, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+	return img, nil
+}
+
+// Resize scales img to width, keeping its aspect ratio. Images already
+// narrower than width are returned unchanged rather than upscaled.
+func Resize(img image.Image, width int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= width {
+		return img
+	}
+
+	height := max(1, bounds.Dy()*width/bounds.Dx())
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
+
+func Encode(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpeg":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: 82})
+	case "png":
+		return png.Encode(w, img)
+	default:
+		return fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// Variant decodes src and returns it resized to width and encoded as format.
+func Variant(src io.Reader, width int, format string) ([]byte, error) {
+	img, err := Decode(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return Thumbnail(img, width, format)
+}
+
+// Thumbnail returns img resized to width and encoded as format.
+func Thumbnail(img image.Image, width int, format string) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := Encode(buf, Resize(img, width), format); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,100 @@
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"sort"
+)
+
+// analysisWidth is the size images are shrunk to before palette and BlurHash
+// extraction; both only need a rough picture of the colours.
+const analysisWidth = 64
+
+// Analysis is the colour information derived from a cover image.
+type Analysis struct {
+	Palette  []string
+	BlurHash string
+}
+
+// Analyze extracts a palette of up to paletteSize hex colours, most dominant
+// first, and a 4x3 BlurHash from img.
+func Analyze(img image.Image, paletteSize int) Analysis {
+	small := Resize(img, analysisWidth)
+	return Analysis{
+		Palette:  Palette(small, paletteSize),
+		BlurHash: BlurHash(small, 4, 3),
+	}
+}
+
+type colorBucket struct {
+	r, g, b, count int
+}
+
+// Palette buckets pixels into a 4-bit-per-channel histogram and returns the
+// average colour of the most populated buckets as #rrggbb strings. Buckets
+// too close to an already chosen colour are skipped so the palette is not
+// just shades of the dominant colour. Mostly transparent pixels are ignored.
+func Palette(img image.Image, size int) []string {
+	buckets := map[int]*colorBucket{}
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			r, g, b = r>>8, g>>8, b>>8
+
+			key := int(r>>4)<<8 | int(g>>4)<<4 | int(b>>4)
+			bucket, ok := buckets[key]
+			if !ok {
+				bucket = &colorBucket{}
+				buckets[key] = bucket
+			}
+			bucket.r += int(r)
+			bucket.g += int(g)
+			bucket.b += int(b)
+			bucket.count++
+		}
+	}
+
+	sorted := make([]*colorBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		bucket.r /= bucket.count
+		bucket.g /= bucket.count
+		bucket.b /= bucket.count
+		sorted = append(sorted, bucket)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].r<<16|sorted[i].g<<8|sorted[i].b < sorted[j].r<<16|sorted[j].g<<8|sorted[j].b
+	})
+
+	const minDistance = 48 * 48
+	var chosen []*colorBucket
+	for _, bucket := range sorted {
+		if len(chosen) == size {
+			break
+		}
+
+		distinct := true
+		for _, c := range chosen {
+			dr, dg, db := bucket.r-c.r, bucket.g-c.g, bucket.b-c.b
+			if dr*dr+dg*dg+db*db < minDistance {
+				distinct = false
+				break
+			}
+		}
+		if distinct {
+			chosen = append(chosen, bucket)
+		}
+	}
+
+	palette := make([]string, len(chosen))
+	for i, c := range chosen {
+		palette[i] = fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+	}
+	return palette
+}
@@ -0,0 +1,139 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+)
+
+// Goodreads reads the "Export Library" CSV from goodreads.com. The exclusive
+// shelf becomes the status and the other shelves become tags.
+var Goodreads = &Source{
+	Name:  "goodreads",
+	Label: "Goodreads CSV",
+	Type:  media.Book,
+	Parse: func(r io.Reader) ([]Row, error) {
+		return readCSV(r, []string{"Title", "Author"}, func(line int, record csvRecord) Row {
+			row := newRow(line, record.get("Title"))
+			row.set("author", record.get("Author"))
+
+			rating, _ := strconv.ParseFloat(record.get("My Rating"), 64)
+			row.set("rating", stars(rating, 5))
+
+			shelf := record.get("Exclusive Shelf")
+			row.set("status", shelfStatus(shelf))
+			row.set("tags", splitList(record.get("Bookshelves"), shelf))
+
+			if pages, err := strconv.Atoi(record.get("Number of Pages")); err == nil && pages > 0 {
+				row.set("page_count", pages)
+			}
+
+			row.set("my_thoughts", goodreadsText(record.get("My Review")))
+
+			if id := record.get("Book Id"); id != "" {
+				row.addLink("goodreads", "https://www.goodreads.com/book/show/"+id)
+			}
+			row.addLink("isbn", isbn(record.get("ISBN13")))
+
+			return row
+		})
+	},
+}
+
+// StoryGraph reads the CSV export from app.thestorygraph.com. Star ratings
+// come in quarter stars and are rounded.
+var StoryGraph = &Source{
+	Name:  "storygraph",
+	Label: "StoryGraph CSV",
+	Type:  media.Book,
+	Parse: func(r io.Reader) ([]Row, error) {
+		return readCSV(r, []string{"Title", "Authors", "Read Status"}, func(line int, record csvRecord) Row {
+			row := newRow(line, record.get("Title"))
+			row.set("author", record.get("Authors"))
+
+			rating, _ := strconv.ParseFloat(record.get("Star Rating"), 64)
+			row.set("rating", stars(rating, 5))
+
+			row.set("status", shelfStatus(record.get("Read Status")))
+			row.set("tags", splitList(record.get("Tags")))
+			row.set("my_thoughts", record.get("Review"))
+			row.addLink("isbn", isbn(record.get("ISBN/UID")))
+
+			return row
+		})
+	},
+}
+
+// csvRecord is a CSV row with its columns looked up by header.
+type csvRecord struct {
+	columns map[string]int
+	values  []string
+}
+
+func (r csvRecord) get(column string) string {
+	i, ok := r.columns[column]
+	if !ok || i >= len(r.values) {
+		return ""
+	}
+	return strings.TrimSpace(r.values[i])
+}
+
+// readCSV reads a CSV file with a header row, which must name every column
+// in required, and maps each record to a row.
+func readCSV(r io.Reader, required []string, mapRow func(line int, record csvRecord) Row) ([]Row, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, name := range required {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	rows := []Row{}
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, mapRow(line, csvRecord{columns: columns, values: values}))
+	}
+
+	return rows, nil
+}
+
+// isbn strips the ="..." Goodreads wraps ISBNs in to keep spreadsheets from
+// reading them as numbers.
+func isbn(value string) string {
+	return strings.Trim(value, `="`)
+}
+
+// goodreadsText turns the line breaks in Goodreads reviews back into text.
+func goodreadsText(value string) string {
+	for _, br := range []string{"<br/>", "<br />", "<br>"} {
+		value = strings.ReplaceAll(value, br, "\n")
+	}
+	return value
+}
@@ -0,0 +1,158 @@
+// Package importer reads the library exports of other services into rows
+// shaped like the media create requests. It only parses: validation,
+// duplicate detection and storage are up to the caller, so the same rows
+// serve a dry run and the real import.
+package importer
+
+import (
+	"io"
+	"math"
+	"strings"
+
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// Source is one kind of export file.
+type Source struct {
+	Name  string
+	Label string
+	// Type is the media type every row becomes.
+	Type  *media.Type
+	Parse func(r io.Reader) ([]Row, error)
+}
+
+// Row is one entry of an export as the JSON fields of the type's create
+// request. Fields the export doesn't have are left out, except genres, tags
+// and links, which are always present (possibly empty). Line is the row's
+// line in a CSV file or its 1-based position in a JSON list.
+type Row struct {
+	Line   int
+	Fields map[string]any
+}
+
+var Sources = []*Source{Goodreads, StoryGraph, Steam, Playnite}
+
+func Lookup(name string) (*Source, bool) {
+	for _, s := range Sources {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// Names lists the source names, for error messages.
+func Names() string {
+	names := make([]string, len(Sources))
+	for i, s := range Sources {
+		names[i] = s.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// shelfStatuses maps shelf and completion-status names, normalized by
+// shelfKey, onto the status vocabulary.
+var shelfStatuses = map[string]string{
+	"read":              models.StatusCompleted,
+	"completed":         models.StatusCompleted,
+	"beaten":            models.StatusCompleted,
+	"finished":          models.StatusCompleted,
+	"currently-reading": models.StatusInProgress,
+	"reading":           models.StatusInProgress,
+	"playing":           models.StatusInProgress,
+	"played":            models.StatusInProgress,
+	"in-progress":       models.StatusInProgress,
+	"to-read":           models.StatusPlanned,
+	"want-to-read":      models.StatusPlanned,
+	"plan-to-play":      models.StatusPlanned,
+	"not-played":        models.StatusPlanned,
+	"planned":           models.StatusPlanned,
+	"on-hold":           models.StatusPaused,
+	"paused":            models.StatusPaused,
+	"did-not-finish":    models.StatusDropped,
+	"dnf":               models.StatusDropped,
+	"abandoned":         models.StatusDropped,
+	"dropped":           models.StatusDropped,
+}
+
+func shelfKey(shelf string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(shelf, "_", " "))), "-")
+}
+
+// shelfStatus maps a shelf onto a status, or "" when it isn't one we know.
+func shelfStatus(shelf string) string {
+	return shelfStatuses[shelfKey(shelf)]
+}
+
+// stars turns a rating out of scale into whole stars out of five, or 0 when
+// there is no rating.
+func stars(rating, scale float64) int {
+	if rating <= 0 || scale <= 0 {
+		return 0
+	}
+	return min(max(int(math.Round(rating*5/scale)), 1), 5)
+}
+
+// splitList splits a comma-separated list, dropping blanks and the values in
+// skip.
+func splitList(list string, skip ...string) []string {
+	values := []string{}
+	for _, value := range strings.Split(list, ",") {
+		value = strings.TrimSpace(value)
+		if value == "" || contains(skip, value) {
+			continue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func link(key, value string) map[string]any {
+	return map[string]any{"key": key, "value": value}
+}
+
+// newRow starts a row with the fields every row carries.
+func newRow(line int, title string) Row {
+	return Row{Line: line, Fields: map[string]any{
+		"title":  strings.TrimSpace(title),
+		"genres": []string{},
+		"tags":   []string{},
+		"links":  []map[string]any{},
+	}}
+}
+
+// set adds a field unless its value is empty.
+func (r Row) set(key string, value any) {
+	switch v := value.(type) {
+	case string:
+		if v = strings.TrimSpace(v); v != "" {
+			r.Fields[key] = v
+		}
+	case int:
+		if v != 0 {
+			r.Fields[key] = v
+		}
+	case []string:
+		if len(v) > 0 {
+			r.Fields[key] = v
+		}
+	default:
+		r.Fields[key] = value
+	}
+}
+
+func (r Row) addLink(key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		r.Fields["links"] = append(r.Fields["links"].([]map[string]any), link(key, value))
+	}
+}
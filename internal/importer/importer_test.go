@@ -0,0 +1,240 @@
+package importer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type sourceTest struct {
+	name  string
+	input string
+	want  []Row
+	err   string
+}
+
+// runSourceTests parses each input with s and compares the rows through
+// their JSON encoding, which is how the import hands them on.
+func runSourceTests(t *testing.T, s *Source, tests []sourceTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := s.Parse(strings.NewReader(tt.input))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			got, _ := json.MarshalIndent(rows, "", "  ")
+			want, _ := json.MarshalIndent(tt.want, "", "  ")
+			if string(got) != string(want) {
+				t.Errorf("rows = %s\nwant %s", got, want)
+			}
+		})
+	}
+}
+
+func links(pairs ...string) []map[string]any {
+	list := []map[string]any{}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		list = append(list, link(pairs[i], pairs[i+1]))
+	}
+	return list
+}
+
+func TestGoodreads(t *testing.T) {
+	header := "\ufeffBook Id,Title,Author,ISBN13,My Rating,Number of Pages,Bookshelves,Exclusive Shelf,My Review\n"
+
+	runSourceTests(t, Goodreads, []sourceTest{
+		{
+			name:  "read book",
+			input: header + `4671,The Great Gatsby,F. Scott Fitzgerald,"=""9780743273565""",4,180,"classics, read",read,Loved it.<br/><br />Again.` + "\n",
+			want: []Row{{Line: 2, Fields: map[string]any{
+				"title":       "The Great Gatsby",
+				"author":      "F. Scott Fitzgerald",
+				"rating":      4,
+				"status":      "completed",
+				"page_count":  180,
+				"genres":      []string{},
+				"tags":        []string{"classics"},
+				"my_thoughts": "Loved it.\n\nAgain.",
+				"links":       links("goodreads", "https://www.goodreads.com/book/show/4671", "isbn", "9780743273565"),
+			}}},
+		},
+		{
+			name: "unrated, on a shelf we don't know",
+			input: header + `1,Dune,Frank Herbert,"=""""",0,,,favorites,` + "\n" +
+				`2,Emma,Jane Austen,,0,,,to-read,` + "\n",
+			want: []Row{
+				{Line: 2, Fields: map[string]any{
+					"title": "Dune", "author": "Frank Herbert", "genres": []string{}, "tags": []string{},
+					"links": links("goodreads", "https://www.goodreads.com/book/show/1"),
+				}},
+				{Line: 3, Fields: map[string]any{
+					"title": "Emma", "author": "Jane Austen", "status": "planned", "genres": []string{}, "tags": []string{},
+					"links": links("goodreads", "https://www.goodreads.com/book/show/2"),
+				}},
+			},
+		},
+		{name: "header only", input: header, want: []Row{}},
+		{name: "empty", input: "", err: "the file is empty"},
+		{name: "missing column", input: "Title,Rating\nDune,5\n", err: `missing column "Author"`},
+	})
+}
+
+func TestStoryGraph(t *testing.T) {
+	header := "Title,Authors,ISBN/UID,Read Status,Star Rating,Review,Tags\n"
+
+	runSourceTests(t, StoryGraph, []sourceTest{
+		{
+			name:  "quarter stars round",
+			input: header + `Piranesi,Susanna Clarke,9781635575637,read,4.75,Strange and lovely,"fantasy, , favourites"` + "\n",
+			want: []Row{{Line: 2, Fields: map[string]any{
+				"title":       "Piranesi",
+				"author":      "Susanna Clarke",
+				"rating":      5,
+				"status":      "completed",
+				"genres":      []string{},
+				"tags":        []string{"fantasy", "favourites"},
+				"my_thoughts": "Strange and lovely",
+				"links":       links("isbn", "9781635575637"),
+			}}},
+		},
+		{
+			name:  "statuses",
+			input: header + "A,X,,currently-reading,,,\nB,Y,,did-not-finish,0.25,,\nC,Z,,Paused,,,\n",
+			want: []Row{
+				{Line: 2, Fields: map[string]any{"title": "A", "author": "X", "status": "in_progress", "genres": []string{}, "tags": []string{}, "links": links()}},
+				{Line: 3, Fields: map[string]any{"title": "B", "author": "Y", "status": "dropped", "rating": 1, "genres": []string{}, "tags": []string{}, "links": links()}},
+				{Line: 4, Fields: map[string]any{"title": "C", "author": "Z", "status": "paused", "genres": []string{}, "tags": []string{}, "links": links()}},
+			},
+		},
+		{name: "missing column", input: "Title,Authors\nA,X\n", err: `missing column "Read Status"`},
+	})
+}
+
+func TestSteam(t *testing.T) {
+	portal := Row{Line: 1, Fields: map[string]any{
+		"title":       "Portal 2",
+		"status":      "in_progress",
+		"cover_image": "https://cdn.cloudflare.steamstatic.com/steam/apps/620/library_600x900.jpg",
+		"genres":      []string{},
+		"tags":        []string{},
+		"links":       links("steam", "https://store.steampowered.com/app/620"),
+	}}
+	unplayed := Row{Line: 2, Fields: map[string]any{
+		"title":  "Unplayed",
+		"status": "planned",
+		"genres": []string{},
+		"tags":   []string{},
+		"links":  links(),
+	}}
+	games := `[{"appid": 620, "name": "Portal 2", "playtime_forever": 1200}, {"name": "Unplayed"}]`
+
+	runSourceTests(t, Steam, []sourceTest{
+		{name: "whole response", input: `{"response": {"game_count": 2, "games": ` + games + `}}`, want: []Row{portal, unplayed}},
+		{name: "response object", input: `{"game_count": 2, "games": ` + games + `}`, want: []Row{portal, unplayed}},
+		{name: "games list", input: games, want: []Row{portal, unplayed}},
+		{name: "empty list", input: `[]`, want: []Row{}},
+		{name: "not JSON", input: `appid,name`, err: "invalid JSON"},
+		{name: "not a list", input: `{"response": {"games": 3}}`, err: "invalid JSON"},
+	})
+}
+
+func TestPlaynite(t *testing.T) {
+	runSourceTests(t, Playnite, []sourceTest{
+		{
+			name: "names and objects",
+			input: `[{
+				"Name": "Hades",
+				"Developers": [{"Id": "1", "Name": "Supergiant Games"}, "Other"],
+				"Genres": ["Roguelike", {"Name": "Action"}, ""],
+				"Tags": [],
+				"CompletionStatus": {"Name": "On Hold"},
+				"UserScore": 87,
+				"Description": "Escape the underworld.",
+				"Notes": "One more run",
+				"Links": [{"Name": "Steam", "Url": "https://store.steampowered.com/app/1145360"}, {"Name": "", "Url": "https://example.com"}]
+			}, {
+				"Name": "Celeste",
+				"CompletionStatus": "Beaten"
+			}]`,
+			want: []Row{
+				{Line: 1, Fields: map[string]any{
+					"title":       "Hades",
+					"developer":   "Supergiant Games",
+					"genres":      []string{"Roguelike", "Action"},
+					"tags":        []string{},
+					"status":      "paused",
+					"rating":      4,
+					"description": "Escape the underworld.",
+					"my_thoughts": "One more run",
+					"links":       links("Steam", "https://store.steampowered.com/app/1145360"),
+				}},
+				{Line: 2, Fields: map[string]any{
+					"title":  "Celeste",
+					"status": "completed",
+					"genres": []string{},
+					"tags":   []string{},
+					"links":  links(),
+				}},
+			},
+		},
+		{name: "not a list", input: `{"Name": "Hades"}`, err: "invalid JSON"},
+		{name: "bad reference", input: `[{"Name": "Hades", "Genres": [3]}]`, err: "invalid JSON"},
+	})
+}
+
+func TestStars(t *testing.T) {
+	tests := []struct {
+		rating, scale float64
+		want          int
+	}{
+		{0, 5, 0},
+		{-1, 5, 0},
+		{3, 0, 0},
+		{0.25, 5, 1},
+		{2.5, 5, 3},
+		{4.74, 5, 5},
+		{5, 5, 5},
+		{9, 5, 5},
+		{1, 100, 1},
+		{50, 100, 3},
+		{100, 100, 5},
+	}
+
+	for _, tt := range tests {
+		if got := stars(tt.rating, tt.scale); got != tt.want {
+			t.Errorf("stars(%v, %v) = %d, want %d", tt.rating, tt.scale, got, tt.want)
+		}
+	}
+}
+
+func TestShelfStatus(t *testing.T) {
+	tests := []struct {
+		shelf string
+		want  string
+	}{
+		{"read", "completed"},
+		{"currently-reading", "in_progress"},
+		{"Currently Reading", "in_progress"},
+		{"  want_to_read ", "planned"},
+		{"Plan to Play", "planned"},
+		{"On Hold", "paused"},
+		{"DNF", "dropped"},
+		{"favorites", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := shelfStatus(tt.shelf); got != tt.want {
+			t.Errorf("shelfStatus(%q) = %q, want %q", tt.shelf, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// Steam reads a Steam library as returned by the Web API's GetOwnedGames
+// (the whole response, its "response" object or just the games list).
+// Steam knows nothing of developers or ratings, so those come from the
+// import defaults; games with playtime are in progress, the rest planned.
+var Steam = &Source{
+	Name:  "steam",
+	Label: "Steam library JSON",
+	Type:  media.Game,
+	Parse: func(r io.Reader) ([]Row, error) {
+		var games []struct {
+			AppID           int64  `json:"appid"`
+			Name            string `json:"name"`
+			PlaytimeForever int    `json:"playtime_forever"`
+		}
+		if err := decodeList(r, &games, "response", "games"); err != nil {
+			return nil, err
+		}
+
+		rows := make([]Row, len(games))
+		for i, game := range games {
+			row := newRow(i+1, game.Name)
+
+			status := models.StatusPlanned
+			if game.PlaytimeForever > 0 {
+				status = models.StatusInProgress
+			}
+			row.set("status", status)
+
+			if game.AppID > 0 {
+				appID := strconv.FormatInt(game.AppID, 10)
+				row.set("cover_image", "https://cdn.cloudflare.steamstatic.com/steam/apps/"+appID+"/library_600x900.jpg")
+				row.addLink("steam", "https://store.steampowered.com/app/"+appID)
+			}
+
+			rows[i] = row
+		}
+
+		return rows, nil
+	},
+}
+
+// Playnite reads a library exported from Playnite as JSON, a list of games
+// with Playnite's own field names. User scores out of 100 become stars.
+var Playnite = &Source{
+	Name:  "playnite",
+	Label: "Playnite library JSON",
+	Type:  media.Game,
+	Parse: func(r io.Reader) ([]Row, error) {
+		var games []struct {
+			Name             string  `json:"Name"`
+			Developers       []named `json:"Developers"`
+			Genres           []named `json:"Genres"`
+			Tags             []named `json:"Tags"`
+			CompletionStatus named   `json:"CompletionStatus"`
+			UserScore        float64 `json:"UserScore"`
+			Description      string  `json:"Description"`
+			Notes            string  `json:"Notes"`
+			Links            []struct {
+				Name string `json:"Name"`
+				URL  string `json:"Url"`
+			} `json:"Links"`
+		}
+		if err := decodeList(r, &games); err != nil {
+			return nil, err
+		}
+
+		rows := make([]Row, len(games))
+		for i, game := range games {
+			row := newRow(i+1, game.Name)
+			if len(game.Developers) > 0 {
+				row.set("developer", string(game.Developers[0]))
+			}
+			row.set("genres", names(game.Genres))
+			row.set("tags", names(game.Tags))
+			row.set("status", shelfStatus(string(game.CompletionStatus)))
+			row.set("rating", stars(game.UserScore, 100))
+			row.set("description", game.Description)
+			row.set("my_thoughts", game.Notes)
+			for _, l := range game.Links {
+				if l.Name != "" {
+					row.addLink(l.Name, l.URL)
+				}
+			}
+
+			rows[i] = row
+		}
+
+		return rows, nil
+	},
+}
+
+// named is a Playnite reference such as a genre, which exports write either
+// as its name or as an object with a Name.
+type named string
+
+func (n *named) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*n = named(name)
+		return nil
+	}
+
+	var object struct {
+		Name string `json:"Name"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	*n = named(object.Name)
+	return nil
+}
+
+func names(values []named) []string {
+	list := []string{}
+	for _, value := range values {
+		if value != "" {
+			list = append(list, string(value))
+		}
+	}
+	return list
+}
+
+// decodeList decodes a JSON list into list. The list may also be nested in
+// objects under the keys in path, outermost first; each level is optional.
+func decodeList(r io.Reader, list any, path ...string) error {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	for _, key := range path {
+		var object map[string]json.RawMessage
+		if json.Unmarshal(raw, &object) != nil {
+			break
+		}
+		if inner, ok := object[key]; ok {
+			raw = inner
+		}
+	}
+
+	if err := json.Unmarshal(raw, list); err != nil {
+		return fmt.Errorf("invalid JSON: %w", err)
+	}
+	return nil
+}
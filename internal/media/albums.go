@@ -0,0 +1,47 @@
+package media
+
+import (
+	"strconv"
+
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+var Album = &Type{
+	Name:    "album",
+	Plural:  "albums",
+	Label:   "Album",
+	Table:   "albums",
+	FTS:     "albums_fts",
+	Creator: "artist",
+	Statuses: []models.TypeStatus{
+		{Status: models.StatusPlanned, Label: "Want to listen"},
+		{Status: models.StatusInProgress, Label: "Listening"},
+		{Status: models.StatusCompleted, Label: "Listened"},
+		{Status: models.StatusDropped, Label: "Dropped"},
+	},
+	Fields: []Field{
+		{Column: "artist", Filter: Contains, Sortable: true},
+		{Column: "release_year", Filter: Range, Sortable: true},
+		{Column: "tracklist"},
+	},
+	Conditions: []Condition{
+		{Param: "has_favourites", Where: hasFavourites},
+	},
+	New:       func() models.MediaEntry { return &models.Album{} },
+	NewCreate: func() models.MediaCreate { return &models.CreateAlbumRequest{} },
+	NewUpdate: func() models.MediaUpdate { return &models.UpdateAlbumRequest{} },
+	NewSearch: func() models.MediaSearch { return &models.AlbumSearchParams{} },
+}
+
+func hasFavourites(value string) (string, []any, bool) {
+	want, err := strconv.ParseBool(value)
+	if err != nil {
+		return "", nil, false
+	}
+
+	clause := "EXISTS (SELECT 1 FROM json_each(tracklist) WHERE json_extract(value, '$.favourite'))"
+	if !want {
+		clause = "NOT " + clause
+	}
+	return clause, nil, true
+}
@@ -0,0 +1,32 @@
+package media
+
+import "github.com/thebearodactyl/apiodactyl/internal/models"
+
+var Anime = &Type{
+	Name:    "anime",
+	Plural:  "anime",
+	Label:   "Anime",
+	Table:   "anime",
+	FTS:     "anime_fts",
+	Creator: "studio",
+	Statuses: []models.TypeStatus{
+		{Status: models.StatusPlanned, Label: "Plan to watch"},
+		{Status: models.StatusInProgress, Label: "Watching"},
+		{Status: models.StatusPaused, Label: "On hold"},
+		{Status: models.StatusCompleted, Label: "Completed"},
+		{Status: models.StatusDropped, Label: "Dropped"},
+	},
+	Fields: []Field{
+		{Column: "studio", Filter: Contains, Sortable: true},
+		{Column: "episodes_total", Filter: Range, Sortable: true},
+		{Column: "episodes_watched", Filter: Range, Sortable: true},
+		{Column: "airing_status", Filter: Equals},
+	},
+	Counters: []Counter{
+		{Unit: "episode", Current: "episodes_watched", Total: "episodes_total"},
+	},
+	New:       func() models.MediaEntry { return &models.Anime{} },
+	NewCreate: func() models.MediaCreate { return &models.CreateAnimeRequest{} },
+	NewUpdate: func() models.MediaUpdate { return &models.UpdateAnimeRequest{} },
+	NewSearch: func() models.MediaSearch { return &models.AnimeSearchParams{} },
+}
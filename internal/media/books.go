@@ -0,0 +1,47 @@
+package media
+
+import "github.com/thebearodactyl/apiodactyl/internal/models"
+
+var Book = &Type{
+	Name:    "book",
+	Plural:  "books",
+	Label:   "Book",
+	Table:   "books",
+	FTS:     "books_fts",
+	Creator: "author",
+	Statuses: []models.TypeStatus{
+		{Status: models.StatusPlanned, Label: "Want to read"},
+		{Status: models.StatusInProgress, Label: "Reading"},
+		{Status: models.StatusPaused, Label: "On hold"},
+		{Status: models.StatusCompleted, Label: "Read"},
+		{Status: models.StatusDropped, Label: "Did not finish"},
+	},
+	Fields: []Field{
+		{Column: "author", Filter: Contains, Sortable: true},
+		{Column: "page_count", Sortable: true},
+		{Column: "current_page"},
+		{
+			// Capped at 100 for rows saved before updates were checked
+			// against page_count.
+			Column:   "percent",
+			Expr:     "CASE WHEN page_count > 0 THEN MIN(current_page * 100 / page_count, 100) END",
+			Filter:   Range,
+			Sortable: true,
+		},
+		{
+			// Pages per hour over the sessions with an end time.
+			Column: "pages_per_hour",
+			Expr: `SELECT ROUND(SUM(end_page - start_page) * 3600.0 /
+				NULLIF(SUM(unixepoch(ended_at) - unixepoch(started_at)), 0), 1)
+				FROM reading_sessions WHERE book_id = books.id AND ended_at > started_at`,
+			Sortable: true,
+		},
+	},
+	Counters: []Counter{
+		{Unit: "page", Current: "current_page", Total: "page_count"},
+	},
+	New:       func() models.MediaEntry { return &models.Book{} },
+	NewCreate: func() models.MediaCreate { return &models.CreateBookRequest{} },
+	NewUpdate: func() models.MediaUpdate { return &models.UpdateBookRequest{} },
+	NewSearch: func() models.MediaSearch { return &models.BookSearchParams{} },
+}
@@ -0,0 +1,41 @@
+package media
+
+import "github.com/thebearodactyl/apiodactyl/internal/models"
+
+var Game = &Type{
+	Name:    "game",
+	Plural:  "games",
+	Label:   "Game",
+	Table:   "games",
+	FTS:     "games_fts",
+	Creator: "developer",
+	Statuses: []models.TypeStatus{
+		{Status: models.StatusPlanned, Label: "Want to play"},
+		{Status: models.StatusInProgress, Label: "Playing"},
+		{Status: models.StatusPaused, Label: "On hold"},
+		{Status: models.StatusCompleted, Label: "Finished"},
+		{Status: models.StatusDropped, Label: "Abandoned"},
+	},
+	Fields: []Field{
+		{Column: "developer", Filter: Contains, Sortable: true},
+		{Column: "percent", Filter: Range, Sortable: true},
+		{Column: "bad", Filter: Flag},
+		{
+			// Minutes played over the finished sessions.
+			Column: "playtime",
+			Expr: `SELECT COALESCE(SUM(unixepoch(ended_at) - unixepoch(started_at)), 0) / 60
+				FROM play_sessions WHERE game_id = games.id AND ended_at IS NOT NULL`,
+			Filter:   Range,
+			Sortable: true,
+		},
+		{
+			Column: "playing",
+			Expr:   "EXISTS (SELECT 1 FROM play_sessions WHERE game_id = games.id AND ended_at IS NULL)",
+			Filter: Flag,
+		},
+	},
+	New:       func() models.MediaEntry { return &models.Game{} },
+	NewCreate: func() models.MediaCreate { return &models.CreateGameRequest{} },
+	NewUpdate: func() models.MediaUpdate { return &models.UpdateGameRequest{} },
+	NewSearch: func() models.MediaSearch { return &models.GameSearchParams{} },
+}
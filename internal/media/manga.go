@@ -0,0 +1,35 @@
+package media
+
+import "github.com/thebearodactyl/apiodactyl/internal/models"
+
+var Manga = &Type{
+	Name:    "manga",
+	Plural:  "manga",
+	Label:   "Manga",
+	Table:   "manga",
+	FTS:     "manga_fts",
+	Creator: "author",
+	Statuses: []models.TypeStatus{
+		{Status: models.StatusPlanned, Label: "Plan to read"},
+		{Status: models.StatusInProgress, Label: "Reading"},
+		{Status: models.StatusPaused, Label: "On hold"},
+		{Status: models.StatusCompleted, Label: "Completed"},
+		{Status: models.StatusDropped, Label: "Dropped"},
+	},
+	Fields: []Field{
+		{Column: "author", Filter: Contains, Sortable: true},
+		{Column: "chapters_total"},
+		{Column: "chapters_read", Filter: Range, Sortable: true},
+		{Column: "volumes_total"},
+		{Column: "volumes_read", Filter: Range, Sortable: true},
+		{Column: "publishing_status", Filter: Equals},
+	},
+	Counters: []Counter{
+		{Unit: "chapter", Current: "chapters_read", Total: "chapters_total"},
+		{Unit: "volume", Current: "volumes_read", Total: "volumes_total"},
+	},
+	New:       func() models.MediaEntry { return &models.Manga{} },
+	NewCreate: func() models.MediaCreate { return &models.CreateMangaRequest{} },
+	NewUpdate: func() models.MediaUpdate { return &models.UpdateMangaRequest{} },
+	NewSearch: func() models.MediaSearch { return &models.MangaSearchParams{} },
+}
@@ -0,0 +1,197 @@
+// Package media registers the media types served by the generic media
+// handlers. Every type is a table holding the shared columns (see
+// models.MediaItem) plus its own, an FTS5 index over title, creator,
+// description and my_thoughts, and links in media_links under the type's
+// name; comments, status_history, collection_items, media_relations,
+// series_entries and the taxonomy join tables reference entries by the same
+// type name. Adding a type takes a migration for those (including the
+// triggers that check status, keep the taxonomies in step and clean up after
+// purged entries), its models, an entry in Types and its API docs.
+package media
+
+import (
+	"strings"
+
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// Filter is how the search endpoint filters on a type-specific column.
+type Filter int
+
+const (
+	NoFilter Filter = iota
+	// Contains matches ?column= as a substring.
+	Contains
+	// Equals matches ?column= exactly.
+	Equals
+	// Range matches ?min_column= and ?max_column=, both inclusive.
+	Range
+	// Flag matches ?column=true or false.
+	Flag
+)
+
+// Field is a type-specific column. A field with an Expr is derived: it is
+// read as Expr AS Column and filtered and sorted on Expr, but never written.
+type Field struct {
+	Column   string
+	Expr     string
+	Filter   Filter
+	Sortable bool
+}
+
+// Value is the SQL the field is read, filtered and sorted by.
+func (f Field) Value() string {
+	if f.Expr != "" {
+		return "(" + f.Expr + ")"
+	}
+	return f.Column
+}
+
+// Condition is a search filter that isn't a plain column match. Where
+// builds the clause from the query parameter's value, or reports false to
+// leave the search unfiltered.
+type Condition struct {
+	Param string
+	Where func(value string) (string, []any, bool)
+}
+
+// Counter is a progress counter such as episodes watched out of the total.
+// Total is nullable while the total is unknown.
+type Counter struct {
+	Unit    string
+	Current string
+	Total   string
+}
+
+type Type struct {
+	// Name is the singular used as item_type in media_links, revisions and
+	// the audit log; Plural is the route segment.
+	Name   string
+	Plural string
+	Label  string
+	Table  string
+	FTS    string
+
+	// Creator is the column naming who made an entry (its author, developer
+	// and so on), which with the title tells entries apart on import.
+	Creator string
+
+	// Statuses are the statuses the type's entries can take, in lifecycle
+	// order, with the type's own name for each ("Reading" for books,
+	// "Playing" for games). They are a subset of models.Statuses, and the
+	// type's status triggers allow the same list.
+	Statuses []models.TypeStatus
+
+	// Fields are the type's own columns, in the order the models' Extra
+	// methods return them. Create and update requests only cover the fields
+	// that are stored, so derived fields go last.
+	Fields     []Field
+	Conditions []Condition
+	// Counters enable the progress endpoint; the first is the default.
+	Counters []Counter
+
+	New       func() models.MediaEntry
+	NewCreate func() models.MediaCreate
+	NewUpdate func() models.MediaUpdate
+	NewSearch func() models.MediaSearch
+}
+
+// Columns returns the select list for the type's own columns.
+func (t *Type) Columns() []string {
+	columns := make([]string, len(t.Fields))
+	for i, field := range t.Fields {
+		columns[i] = field.Column
+		if field.Expr != "" {
+			columns[i] = field.Value() + " AS " + field.Column
+		}
+	}
+	return columns
+}
+
+// Stored returns the fields that are real columns, in the order create and
+// update requests' Extra methods return them.
+func (t *Type) Stored() []Field {
+	fields := []Field{}
+	for _, field := range t.Fields {
+		if field.Expr == "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+var Types = []*Type{Game, Book, Movie, Show, Album, Anime, Manga}
+
+// Names lists the type names, for error messages.
+func Names() string {
+	names := make([]string, len(Types))
+	for i, t := range Types {
+		names[i] = t.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// Counter returns the counter for unit, or the first when unit is empty.
+func (t *Type) Counter(unit string) (Counter, bool) {
+	for _, counter := range t.Counters {
+		if unit == "" || counter.Unit == unit {
+			return counter, true
+		}
+	}
+	return Counter{}, false
+}
+
+// Units lists the counter units, for error messages.
+func (t *Type) Units() string {
+	units := make([]string, len(t.Counters))
+	for i, counter := range t.Counters {
+		units[i] = counter.Unit
+	}
+	return strings.Join(units, ", ")
+}
+
+// HasStatus reports whether the type's entries can take status.
+func (t *Type) HasStatus(status string) bool {
+	for _, s := range t.Statuses {
+		if s.Status == status {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusNames lists the type's statuses, for error messages.
+func (t *Type) StatusNames() string {
+	names := make([]string, len(t.Statuses))
+	for i, s := range t.Statuses {
+		names[i] = s.Status
+	}
+	return strings.Join(names, ", ")
+}
+
+// CanTransition reports whether an entry may move from one status to
+// another: the move must be allowed by models.CanTransition and lead to one
+// of the type's statuses.
+func (t *Type) CanTransition(from, to string) bool {
+	return t.HasStatus(to) && models.CanTransition(from, to)
+}
+
+// Transitions lists the statuses an entry may move to from status.
+func (t *Type) Transitions(from string) []string {
+	next := []string{}
+	for _, to := range models.StatusTransitions[from] {
+		if t.HasStatus(to) {
+			next = append(next, to)
+		}
+	}
+	return next
+}
+
+func Lookup(name string) (*Type, bool) {
+	for _, t := range Types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
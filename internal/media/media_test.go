@@ -0,0 +1,87 @@
+package media
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+func TestTypeStatuses(t *testing.T) {
+	for _, typ := range Types {
+		t.Run(typ.Name, func(t *testing.T) {
+			if len(typ.Statuses) == 0 {
+				t.Fatal("no statuses")
+			}
+
+			// Statuses are listed in lifecycle order, so each one must come
+			// later in models.Statuses than the one before it.
+			last := -1
+			for _, s := range typ.Statuses {
+				i := slices.Index(models.Statuses, s.Status)
+				if i < 0 {
+					t.Errorf("%s is not in the vocabulary", s.Status)
+				}
+				if i <= last {
+					t.Errorf("%s is out of order", s.Status)
+				}
+				if s.Label == "" {
+					t.Errorf("%s has no label", s.Status)
+				}
+				last = i
+			}
+
+			if !typ.HasStatus(models.StatusPlanned) || !typ.HasStatus(models.StatusCompleted) {
+				t.Error("every type must start planned and end completed")
+			}
+		})
+	}
+}
+
+func TestTypeCanTransition(t *testing.T) {
+	tests := []struct {
+		typ      *Type
+		from, to string
+		allowed  bool
+	}{
+		{Game, models.StatusInProgress, models.StatusPaused, true},
+		{Game, models.StatusPaused, models.StatusCompleted, true},
+		{Book, models.StatusInProgress, models.StatusPaused, true},
+		{Movie, models.StatusInProgress, models.StatusPaused, false},
+		{Movie, models.StatusInProgress, models.StatusCompleted, true},
+		{Movie, models.StatusPlanned, models.StatusPlanned, true},
+		{Album, models.StatusInProgress, models.StatusPaused, false},
+		{Album, models.StatusCompleted, models.StatusInProgress, true},
+		{Album, models.StatusCompleted, models.StatusDropped, false},
+		{Game, models.StatusPlanned, "typo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ.Name+" "+tt.from+" to "+tt.to, func(t *testing.T) {
+			if got := tt.typ.CanTransition(tt.from, tt.to); got != tt.allowed {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestTypeTransitions(t *testing.T) {
+	tests := []struct {
+		typ  *Type
+		from string
+		want []string
+	}{
+		{Game, models.StatusInProgress, []string{models.StatusPaused, models.StatusCompleted, models.StatusDropped}},
+		{Movie, models.StatusInProgress, []string{models.StatusCompleted, models.StatusDropped}},
+		{Album, models.StatusCompleted, []string{models.StatusInProgress}},
+		{Movie, "typo", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.typ.Name+" "+tt.from, func(t *testing.T) {
+			if got := tt.typ.Transitions(tt.from); !slices.Equal(got, tt.want) {
+				t.Errorf("Transitions(%s) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
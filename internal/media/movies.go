@@ -0,0 +1,27 @@
+package media
+
+import "github.com/thebearodactyl/apiodactyl/internal/models"
+
+var Movie = &Type{
+	Name:    "movie",
+	Plural:  "movies",
+	Label:   "Movie",
+	Table:   "movies",
+	FTS:     "movies_fts",
+	Creator: "director",
+	Statuses: []models.TypeStatus{
+		{Status: models.StatusPlanned, Label: "Want to watch"},
+		{Status: models.StatusInProgress, Label: "Watching"},
+		{Status: models.StatusCompleted, Label: "Watched"},
+		{Status: models.StatusDropped, Label: "Dropped"},
+	},
+	Fields: []Field{
+		{Column: "director", Filter: Contains, Sortable: true},
+		{Column: "runtime", Filter: Range, Sortable: true},
+		{Column: "release_year", Filter: Range, Sortable: true},
+	},
+	New:       func() models.MediaEntry { return &models.Movie{} },
+	NewCreate: func() models.MediaCreate { return &models.CreateMovieRequest{} },
+	NewUpdate: func() models.MediaUpdate { return &models.UpdateMovieRequest{} },
+	NewSearch: func() models.MediaSearch { return &models.MovieSearchParams{} },
+}
@@ -0,0 +1,29 @@
+package media
+
+import "github.com/thebearodactyl/apiodactyl/internal/models"
+
+var Show = &Type{
+	Name:    "show",
+	Plural:  "shows",
+	Label:   "Show",
+	Table:   "shows",
+	FTS:     "shows_fts",
+	Creator: "creator",
+	Statuses: []models.TypeStatus{
+		{Status: models.StatusPlanned, Label: "Want to watch"},
+		{Status: models.StatusInProgress, Label: "Watching"},
+		{Status: models.StatusPaused, Label: "On hold"},
+		{Status: models.StatusCompleted, Label: "Watched"},
+		{Status: models.StatusDropped, Label: "Dropped"},
+	},
+	Fields: []Field{
+		{Column: "creator", Filter: Contains, Sortable: true},
+		{Column: "seasons", Filter: Range, Sortable: true},
+		{Column: "episodes_watched", Filter: Range, Sortable: true},
+		{Column: "season_progress"},
+	},
+	New:       func() models.MediaEntry { return &models.Show{} },
+	NewCreate: func() models.MediaCreate { return &models.CreateShowRequest{} },
+	NewUpdate: func() models.MediaUpdate { return &models.UpdateShowRequest{} },
+	NewSearch: func() models.MediaSearch { return &models.ShowSearchParams{} },
+}
@@ -0,0 +1,38 @@
+package media
+
+// Taxonomy is a vocabulary of names media entries carry as a JSON array
+// column, mirrored into a per-user table of names and a join table by
+// triggers on every media table.
+type Taxonomy struct {
+	Name   string
+	Plural string
+	Label  string
+	// Table holds the names; Join links them to entries through Key.
+	Table string
+	Join  string
+	Key   string
+	// Column is the JSON array column on the media tables.
+	Column string
+}
+
+var Tags = &Taxonomy{
+	Name:   "tag",
+	Plural: "tags",
+	Label:  "Tag",
+	Table:  "tags",
+	Join:   "media_tags",
+	Key:    "tag_id",
+	Column: "tags",
+}
+
+var Genres = &Taxonomy{
+	Name:   "genre",
+	Plural: "genres",
+	Label:  "Genre",
+	Table:  "genres",
+	Join:   "media_genres",
+	Key:    "genre_id",
+	Column: "genres",
+}
+
+var Taxonomies = []*Taxonomy{Tags, Genres}
@@ -0,0 +1,183 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// igdbFields are the game fields every IGDB query asks for.
+const igdbFields = "name,summary,url,first_release_date,genres.name,cover.image_id," +
+	"involved_companies.developer,involved_companies.company.name"
+
+// IGDB finds games through the IGDB API, signing in with a Twitch client's
+// credentials. Candidates are identified by their numeric IGDB ID.
+type IGDB struct {
+	client    *http.Client
+	cfg       config.IGDBConfig
+	baseURL   string
+	imagesURL string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func NewIGDB(client *http.Client, cfg config.IGDBConfig) *IGDB {
+	return &IGDB{
+		client:    client,
+		cfg:       cfg,
+		baseURL:   strings.TrimRight(cfg.URL, "/"),
+		imagesURL: strings.TrimRight(cfg.ImagesURL, "/"),
+	}
+}
+
+func (g *IGDB) Name() string  { return "igdb" }
+func (g *IGDB) Label() string { return "IGDB" }
+func (g *IGDB) Type() string  { return "game" }
+
+// Search matches titles only; games have no ISBN.
+func (g *IGDB) Search(ctx context.Context, q Query) ([]models.MetadataCandidate, error) {
+	if q.Title == "" {
+		return []models.MetadataCandidate{}, nil
+	}
+
+	title := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(q.Title)
+	return g.query(ctx, fmt.Sprintf(`search "%s"; fields %s; limit %d;`, title, igdbFields, q.Limit))
+}
+
+func (g *IGDB) Get(ctx context.Context, id string) (*models.MetadataCandidate, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n <= 0 {
+		return nil, ErrNotFound
+	}
+
+	candidates, err := g.query(ctx, fmt.Sprintf(`fields %s; where id = %d;`, igdbFields, n))
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, ErrNotFound
+	}
+	return &candidates[0], nil
+}
+
+func (g *IGDB) query(ctx context.Context, body string) ([]models.MetadataCandidate, error) {
+	token, err := g.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.baseURL+"/games", strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Client-ID", g.cfg.ClientID)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "text/plain")
+
+	var games []struct {
+		ID               int64  `json:"id"`
+		Name             string `json:"name"`
+		Summary          string `json:"summary"`
+		URL              string `json:"url"`
+		FirstReleaseDate int64  `json:"first_release_date"`
+		Genres           []struct {
+			Name string `json:"name"`
+		} `json:"genres"`
+		Cover struct {
+			ImageID string `json:"image_id"`
+		} `json:"cover"`
+		InvolvedCompanies []struct {
+			Developer bool `json:"developer"`
+			Company   struct {
+				Name string `json:"name"`
+			} `json:"company"`
+		} `json:"involved_companies"`
+	}
+	if err := doJSON(g.client, req, &games); err != nil {
+		return nil, err
+	}
+
+	candidates := []models.MetadataCandidate{}
+	for _, game := range games {
+		candidate := models.MetadataCandidate{
+			Provider:    g.Name(),
+			ID:          strconv.FormatInt(game.ID, 10),
+			ItemType:    g.Type(),
+			Title:       game.Name,
+			Description: strings.TrimSpace(game.Summary),
+			Genres:      []string{},
+			Links:       []models.MediaLink{},
+			Fields:      map[string]any{},
+		}
+
+		for _, genre := range game.Genres {
+			candidate.Genres = append(candidate.Genres, genre.Name)
+		}
+		for _, company := range game.InvolvedCompanies {
+			if company.Developer {
+				candidate.Creator = company.Company.Name
+				break
+			}
+		}
+		if game.Cover.ImageID != "" {
+			candidate.CoverURL = g.imagesURL + "/t_cover_big/" + game.Cover.ImageID + ".jpg"
+		}
+		if game.FirstReleaseDate > 0 {
+			candidate.Year = time.Unix(game.FirstReleaseDate, 0).UTC().Year()
+		}
+		if game.URL != "" {
+			candidate.Links = append(candidate.Links, models.MediaLink{Key: "igdb", Value: game.URL})
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// accessToken returns the app access token, signing in again a minute
+// before the current one expires.
+func (g *IGDB) accessToken(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.token != "" && time.Now().Before(g.expires) {
+		return g.token, nil
+	}
+
+	params := url.Values{
+		"client_id":     {g.cfg.ClientID},
+		"client_secret": {g.cfg.ClientSecret},
+		"grant_type":    {"client_credentials"},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.cfg.TokenURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := doJSON(g.client, req, &result); err != nil {
+		return "", fmt.Errorf("failed to sign in to IGDB: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("failed to sign in to IGDB: no access token")
+	}
+
+	g.token = result.AccessToken
+	g.expires = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return g.token, nil
+}
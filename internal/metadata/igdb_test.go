@@ -0,0 +1,201 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// igdbStandIn stands in for the Twitch token endpoint and the IGDB API.
+// It knows one game, 1942, and records the queries it was sent.
+type igdbStandIn struct {
+	server  *httptest.Server
+	signIns atomic.Int32
+	queries []string
+	// expiresIn is the token lifetime handed out, in seconds.
+	expiresIn int
+}
+
+func newIGDBStandIn(t *testing.T) *igdbStandIn {
+	t.Helper()
+
+	s := &igdbStandIn{expiresIn: 3600}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth2/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != "client" || r.FormValue("client_secret") != "secret" || r.FormValue("grant_type") != "client_credentials" {
+			http.Error(w, `{"message": "invalid client"}`, http.StatusBadRequest)
+			return
+		}
+		n := s.signIns.Add(1)
+		fmt.Fprintf(w, `{"access_token": "token%d", "expires_in": %d, "token_type": "bearer"}`, n, s.expiresIn)
+	})
+	mux.HandleFunc("POST /v4/games", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Client-ID") != "client" || !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer token") {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		s.queries = append(s.queries, string(body))
+
+		if !strings.Contains(string(body), `search "the witcher`) && !strings.Contains(string(body), "where id = 1942;") {
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.Write([]byte(`[{
+			"id": 1942,
+			"name": "The Witcher 3: Wild Hunt",
+			"summary": " Geralt hunts. ",
+			"url": "https://www.igdb.com/games/the-witcher-3-wild-hunt",
+			"first_release_date": 1431993600,
+			"genres": [{"id": 12, "name": "Role-playing (RPG)"}, {"id": 31, "name": "Adventure"}],
+			"cover": {"id": 89386, "image_id": "co1wyy"},
+			"involved_companies": [
+				{"id": 1, "developer": false, "company": {"id": 2, "name": "Bandai Namco"}},
+				{"id": 3, "developer": true, "company": {"id": 4, "name": "CD Projekt RED"}}
+			]
+		}]`))
+	})
+
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *igdbStandIn) provider(clientID string) *IGDB {
+	return NewIGDB(s.server.Client(), config.IGDBConfig{
+		ClientID:     clientID,
+		ClientSecret: "secret",
+		URL:          s.server.URL + "/v4/",
+		TokenURL:     s.server.URL + "/oauth2/token",
+		ImagesURL:    "https://images.example.com/",
+	})
+}
+
+var witcher = models.MetadataCandidate{
+	Provider:    "igdb",
+	ID:          "1942",
+	ItemType:    "game",
+	Title:       "The Witcher 3: Wild Hunt",
+	Creator:     "CD Projekt RED",
+	Description: "Geralt hunts.",
+	Genres:      []string{"Role-playing (RPG)", "Adventure"},
+	CoverURL:    "https://images.example.com/t_cover_big/co1wyy.jpg",
+	Year:        2015,
+	Links:       []models.MediaLink{{Key: "igdb", Value: "https://www.igdb.com/games/the-witcher-3-wild-hunt"}},
+	Fields:      map[string]any{},
+}
+
+func TestIGDBSearch(t *testing.T) {
+	s := newIGDBStandIn(t)
+	g := s.provider("client")
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		query Query
+		sent  string
+		want  []models.MetadataCandidate
+	}{
+		{
+			name:  "title",
+			query: Query{Title: "the witcher", Limit: 5},
+			sent:  `search "the witcher"; fields ` + igdbFields + `; limit 5;`,
+			want:  []models.MetadataCandidate{witcher},
+		},
+		{
+			name:  "quotes are escaped",
+			query: Query{Title: `say "hi" \o/`, Limit: 1},
+			sent:  `search "say \"hi\" \\o/"; fields ` + igdbFields + `; limit 1;`,
+			want:  []models.MetadataCandidate{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := g.Search(ctx, tt.query)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+			if last := s.queries[len(s.queries)-1]; last != tt.sent {
+				t.Errorf("sent %s, want %s", last, tt.sent)
+			}
+		})
+	}
+
+	// ISBN-only searches mean nothing to IGDB and aren't sent.
+	sent := len(s.queries)
+	if got, err := g.Search(ctx, Query{ISBN: "9780747532699", Limit: 5}); err != nil || len(got) != 0 {
+		t.Errorf("ISBN search = %v, %v; want nothing", got, err)
+	}
+	if len(s.queries) != sent {
+		t.Error("ISBN search was sent to IGDB")
+	}
+
+	// The token is reused until it is about to expire.
+	if n := s.signIns.Load(); n != 1 {
+		t.Errorf("signed in %d times, want once", n)
+	}
+}
+
+func TestIGDBGet(t *testing.T) {
+	s := newIGDBStandIn(t)
+	g := s.provider("client")
+	ctx := context.Background()
+
+	got, err := g.Get(ctx, "1942")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	assertJSON(t, got, &witcher)
+
+	for _, id := range []string{"1", "0", "-3", "abc", "1942; fields *"} {
+		t.Run(id, func(t *testing.T) {
+			if _, err := g.Get(ctx, id); !errors.Is(err, ErrNotFound) {
+				t.Errorf("Get(%q) = %v, want ErrNotFound", id, err)
+			}
+		})
+	}
+}
+
+func TestIGDBToken(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("refreshed before it expires", func(t *testing.T) {
+		s := newIGDBStandIn(t)
+		// Tokens that expire within a minute are treated as expired.
+		s.expiresIn = 30
+		g := s.provider("client")
+
+		for range 2 {
+			if _, err := g.Get(ctx, "1942"); err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+		}
+		if n := s.signIns.Load(); n != 2 {
+			t.Errorf("signed in %d times, want twice", n)
+		}
+	})
+
+	t.Run("bad credentials", func(t *testing.T) {
+		s := newIGDBStandIn(t)
+		g := s.provider("someone-else")
+
+		_, err := g.Search(ctx, Query{Title: "the witcher", Limit: 5})
+		if err == nil || !strings.Contains(err.Error(), "failed to sign in to IGDB") {
+			t.Errorf("Search = %v, want a sign-in error", err)
+		}
+		if len(s.queries) != 0 {
+			t.Error("queried IGDB without a token")
+		}
+	})
+}
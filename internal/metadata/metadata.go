@@ -0,0 +1,132 @@
+// Package metadata looks up entries in external catalogs such as Open
+// Library and IGDB, so new entries can be filled in rather than typed. Every
+// provider serves one media type and normalizes its matches into
+// models.MetadataCandidate; which providers exist depends on the config.
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// ErrNotFound is returned by Get for IDs the provider doesn't know.
+var ErrNotFound = errors.New("not found")
+
+// maxResponseBytes caps the provider responses we read.
+const maxResponseBytes = 4 << 20
+
+// Query is a search. A query with an ISBN ignores the title.
+type Query struct {
+	Title string
+	ISBN  string
+	Limit int
+}
+
+type Provider interface {
+	Name() string
+	Label() string
+	// Type is the name of the media type the provider's candidates are for.
+	Type() string
+	Search(ctx context.Context, q Query) ([]models.MetadataCandidate, error)
+	// Get looks up a candidate by the provider's own ID.
+	Get(ctx context.Context, id string) (*models.MetadataCandidate, error)
+}
+
+// Registry holds the configured providers.
+type Registry struct {
+	providers []Provider
+}
+
+// New registers the providers cfg enables.
+func New(cfg config.MetadataConfig) *Registry {
+	client := &http.Client{Timeout: cfg.Timeout()}
+
+	r := &Registry{}
+	if cfg.OpenLibrary.Enabled {
+		r.providers = append(r.providers, NewOpenLibrary(client, cfg.OpenLibrary))
+	}
+	if cfg.IGDB.ClientID != "" {
+		r.providers = append(r.providers, NewIGDB(client, cfg.IGDB))
+	}
+	return r
+}
+
+// Providers lists the providers for itemType, or all of them when it is "".
+func (r *Registry) Providers(itemType string) []Provider {
+	providers := []Provider{}
+	for _, p := range r.providers {
+		if itemType == "" || p.Type() == itemType {
+			providers = append(providers, p)
+		}
+	}
+	return providers
+}
+
+func (r *Registry) Lookup(name string) (Provider, bool) {
+	for _, p := range r.providers {
+		if p.Name() == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// Names lists the providers for itemType, for error messages.
+func (r *Registry) Names(itemType string) string {
+	names := []string{}
+	for _, p := range r.Providers(itemType) {
+		names = append(names, p.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+// doJSON sends req and decodes a JSON response into v. A 404 is reported as
+// ErrNotFound.
+func doJSON(client *http.Client, req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "apiodactyl/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded %s", req.URL.Host, resp.Status)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", req.URL.Host, err)
+	}
+	return nil
+}
+
+// firstN returns at most n of values, dropping blanks and repeats.
+func firstN(values []string, n int) []string {
+	list := []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[strings.ToLower(value)] {
+			continue
+		}
+		seen[strings.ToLower(value)] = true
+		list = append(list, value)
+		if len(list) == n {
+			break
+		}
+	}
+	return list
+}
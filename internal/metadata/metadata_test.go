@@ -0,0 +1,115 @@
+package metadata
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   config.MetadataConfig
+		names []string
+		books string
+		games string
+	}{
+		{
+			name: "none",
+		},
+		{
+			name:  "open library",
+			cfg:   config.MetadataConfig{OpenLibrary: config.OpenLibraryConfig{Enabled: true}},
+			names: []string{"openlibrary"},
+			books: "openlibrary",
+		},
+		{
+			name:  "igdb needs a client ID",
+			cfg:   config.MetadataConfig{IGDB: config.IGDBConfig{ClientSecret: "secret"}},
+			names: []string{},
+		},
+		{
+			name: "both",
+			cfg: config.MetadataConfig{
+				OpenLibrary: config.OpenLibraryConfig{Enabled: true},
+				IGDB:        config.IGDBConfig{ClientID: "id", ClientSecret: "secret"},
+			},
+			names: []string{"openlibrary", "igdb"},
+			books: "openlibrary",
+			games: "igdb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(tt.cfg)
+
+			names := []string{}
+			for _, p := range r.Providers("") {
+				names = append(names, p.Name())
+			}
+			if !slices.Equal(names, append([]string{}, tt.names...)) {
+				t.Errorf("providers = %v, want %v", names, tt.names)
+			}
+			if got := r.Names("book"); got != tt.books {
+				t.Errorf("book providers = %q, want %q", got, tt.books)
+			}
+			if got := r.Names("game"); got != tt.games {
+				t.Errorf("game providers = %q, want %q", got, tt.games)
+			}
+			if len(r.Providers("movie")) != 0 {
+				t.Error("no provider serves movies")
+			}
+
+			for _, name := range tt.names {
+				if p, ok := r.Lookup(name); !ok || p.Name() != name {
+					t.Errorf("Lookup(%s) = %v, %v", name, p, ok)
+				}
+			}
+			if _, ok := r.Lookup("missing"); ok {
+				t.Error("Lookup(missing) found a provider")
+			}
+		})
+	}
+}
+
+func TestFirstN(t *testing.T) {
+	tests := []struct {
+		values []string
+		n      int
+		want   []string
+	}{
+		{nil, 3, []string{}},
+		{[]string{"Fantasy", "Magic"}, 5, []string{"Fantasy", "Magic"}},
+		{[]string{"a", "b", "c", "d"}, 2, []string{"a", "b"}},
+		{[]string{" Fantasy ", "", "fantasy", "FANTASY", "Magic"}, 5, []string{"Fantasy", "Magic"}},
+		{[]string{"", " ", "a"}, 1, []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		if got := firstN(tt.values, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("firstN(%q, %d) = %q, want %q", tt.values, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestOpenLibraryText(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`"A novel. "`, "A novel."},
+		{`{"type": "/type/text", "value": " A novel.\n"}`, "A novel."},
+		{`null`, ""},
+		{`42`, ""},
+		{``, ""},
+	}
+
+	for _, tt := range tests {
+		if got := openLibraryText(json.RawMessage(tt.raw)); got != tt.want {
+			t.Errorf("openLibraryText(%s) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
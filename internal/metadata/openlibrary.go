@@ -0,0 +1,187 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// maxSubjects is how many Open Library subjects become genres; works often
+// have dozens.
+const maxSubjects = 5
+
+// workID matches Open Library work IDs such as OL45804W.
+var workID = regexp.MustCompile(`^OL\d+W$`)
+
+// OpenLibrary finds books through the Open Library search and works APIs.
+// Candidates are identified by their work ID.
+type OpenLibrary struct {
+	client    *http.Client
+	baseURL   string
+	coversURL string
+}
+
+func NewOpenLibrary(client *http.Client, cfg config.OpenLibraryConfig) *OpenLibrary {
+	return &OpenLibrary{
+		client:    client,
+		baseURL:   strings.TrimRight(cfg.URL, "/"),
+		coversURL: strings.TrimRight(cfg.CoversURL, "/"),
+	}
+}
+
+func (o *OpenLibrary) Name() string  { return "openlibrary" }
+func (o *OpenLibrary) Label() string { return "Open Library" }
+func (o *OpenLibrary) Type() string  { return "book" }
+
+func (o *OpenLibrary) Search(ctx context.Context, q Query) ([]models.MetadataCandidate, error) {
+	params := url.Values{
+		"fields": {"key,title,author_name,first_publish_year,cover_i,number_of_pages_median,subject,isbn"},
+		"limit":  {strconv.Itoa(q.Limit)},
+	}
+	if q.ISBN != "" {
+		params.Set("isbn", q.ISBN)
+	} else {
+		params.Set("title", q.Title)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL+"/search.json?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Docs []struct {
+			Key              string   `json:"key"`
+			Title            string   `json:"title"`
+			AuthorName       []string `json:"author_name"`
+			FirstPublishYear int      `json:"first_publish_year"`
+			CoverID          int64    `json:"cover_i"`
+			Pages            int      `json:"number_of_pages_median"`
+			Subject          []string `json:"subject"`
+			ISBN             []string `json:"isbn"`
+		} `json:"docs"`
+	}
+	if err := doJSON(o.client, req, &result); err != nil {
+		return nil, err
+	}
+
+	candidates := []models.MetadataCandidate{}
+	for _, doc := range result.Docs {
+		candidate := o.candidate(strings.TrimPrefix(doc.Key, "/works/"), doc.Title, doc.Subject, doc.CoverID)
+		if len(doc.AuthorName) > 0 {
+			candidate.Creator = doc.AuthorName[0]
+		}
+		candidate.Year = doc.FirstPublishYear
+		if doc.Pages > 0 {
+			candidate.Fields["page_count"] = doc.Pages
+		}
+
+		isbn := q.ISBN
+		if isbn == "" && len(doc.ISBN) > 0 {
+			isbn = doc.ISBN[0]
+		}
+		if isbn != "" {
+			candidate.Links = append(candidate.Links, models.MediaLink{Key: "isbn", Value: isbn})
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+// Get reads a work and its first author. Works have no page count, which
+// belongs to each edition.
+func (o *OpenLibrary) Get(ctx context.Context, id string) (*models.MetadataCandidate, error) {
+	if !workID.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	var work struct {
+		Title       string          `json:"title"`
+		Description json.RawMessage `json:"description"`
+		Subjects    []string        `json:"subjects"`
+		Covers      []int64         `json:"covers"`
+		Authors     []struct {
+			Author struct {
+				Key string `json:"key"`
+			} `json:"author"`
+		} `json:"authors"`
+	}
+	if err := o.get(ctx, "/works/"+id+".json", &work); err != nil {
+		return nil, err
+	}
+
+	var cover int64
+	if len(work.Covers) > 0 {
+		cover = work.Covers[0]
+	}
+
+	candidate := o.candidate(id, work.Title, work.Subjects, cover)
+	candidate.Description = openLibraryText(work.Description)
+
+	if len(work.Authors) > 0 && work.Authors[0].Author.Key != "" {
+		var author struct {
+			Name string `json:"name"`
+		}
+		// A missing author leaves the creator blank rather than reporting
+		// the work itself as not found.
+		err := o.get(ctx, work.Authors[0].Author.Key+".json", &author)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, fmt.Errorf("failed to read author: %w", err)
+		}
+		candidate.Creator = author.Name
+	}
+
+	return &candidate, nil
+}
+
+func (o *OpenLibrary) get(ctx context.Context, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	return doJSON(o.client, req, v)
+}
+
+func (o *OpenLibrary) candidate(id, title string, subjects []string, cover int64) models.MetadataCandidate {
+	candidate := models.MetadataCandidate{
+		Provider: o.Name(),
+		ID:       id,
+		ItemType: o.Type(),
+		Title:    title,
+		Genres:   firstN(subjects, maxSubjects),
+		Links:    []models.MediaLink{{Key: "openlibrary", Value: o.baseURL + "/works/" + id}},
+		Fields:   map[string]any{},
+	}
+	if cover > 0 {
+		candidate.CoverURL = fmt.Sprintf("%s/b/id/%d-L.jpg", o.coversURL, cover)
+	}
+	return candidate
+}
+
+// openLibraryText reads a text field, which Open Library writes either as a
+// string or as {"type": "/type/text", "value": ...}.
+func openLibraryText(raw json.RawMessage) string {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return strings.TrimSpace(text)
+	}
+
+	var typed struct {
+		Value string `json:"value"`
+	}
+	if json.Unmarshal(raw, &typed) == nil {
+		return strings.TrimSpace(typed.Value)
+	}
+	return ""
+}
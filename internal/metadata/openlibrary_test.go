@@ -0,0 +1,203 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/models"
+)
+
+// newTestOpenLibrary stands in for Open Library. The search knows one title
+// and one ISBN; of the works, OL1W is complete, OL5W's author is missing,
+// OL6W fails and OL7W isn't JSON.
+func newTestOpenLibrary(t *testing.T) (*OpenLibrary, *httptest.Server) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search.json", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("fields") == "" || q.Get("limit") != "2" {
+			http.Error(w, "bad query", http.StatusBadRequest)
+			return
+		}
+
+		docs := []map[string]any{}
+		switch {
+		case q.Get("isbn") == "9780747532699":
+			docs = append(docs, map[string]any{
+				"key": "/works/OL1W", "title": "Harry Potter and the Philosopher's Stone", "author_name": []string{"J. K. Rowling"},
+				"isbn": []string{"0747532699"},
+			})
+		case q.Get("title") == "piranesi":
+			docs = append(docs, map[string]any{
+				"key":                    "/works/OL3W",
+				"title":                  "Piranesi",
+				"author_name":            []string{"Susanna Clarke", "Someone Else"},
+				"first_publish_year":     2020,
+				"cover_i":                10521270,
+				"number_of_pages_median": 272,
+				"subject":                []string{"Fantasy", "fantasy", "Labyrinths", "Mystery", "Magic", "Fiction", "Statues"},
+				"isbn":                   []string{"9781635575637", "1635575633"},
+			}, map[string]any{"key": "/works/OL4W", "title": "Piranesi (Illustrated)"})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"numFound": len(docs), "docs": docs})
+	})
+	mux.HandleFunc("/works/OL1W.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"title": "Harry Potter and the Philosopher's Stone",
+			"description": {"type": "/type/text", "value": "A boy learns he is a wizard."},
+			"subjects": ["Magic", "Schools"],
+			"covers": [10521270, 8],
+			"authors": [{"author": {"key": "/authors/OL2A"}, "type": {"key": "/type/author_role"}}]
+		}`))
+	})
+	mux.HandleFunc("/authors/OL2A.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name": "J. K. Rowling"}`))
+	})
+	mux.HandleFunc("/works/OL5W.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"title": "Orphan", "description": "No author.", "authors": [{"author": {"key": "/authors/OL9A"}}]}`))
+	})
+	mux.HandleFunc("/works/OL6W.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down for maintenance", http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/works/OL7W.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html>`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	o := NewOpenLibrary(server.Client(), config.OpenLibraryConfig{
+		Enabled:   true,
+		URL:       server.URL + "/",
+		CoversURL: "https://covers.example.com/",
+	})
+	return o, server
+}
+
+func TestOpenLibrarySearch(t *testing.T) {
+	o, server := newTestOpenLibrary(t)
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []models.MetadataCandidate
+	}{
+		{
+			name:  "title",
+			query: Query{Title: "piranesi", Limit: 2},
+			want: []models.MetadataCandidate{
+				{
+					Provider: "openlibrary", ID: "OL3W", ItemType: "book", Title: "Piranesi", Creator: "Susanna Clarke",
+					Genres:   []string{"Fantasy", "Labyrinths", "Mystery", "Magic", "Fiction"},
+					CoverURL: "https://covers.example.com/b/id/10521270-L.jpg",
+					Year:     2020,
+					Links:    []models.MediaLink{{Key: "openlibrary", Value: server.URL + "/works/OL3W"}, {Key: "isbn", Value: "9781635575637"}},
+					Fields:   map[string]any{"page_count": 272},
+				},
+				{
+					Provider: "openlibrary", ID: "OL4W", ItemType: "book", Title: "Piranesi (Illustrated)",
+					Genres: []string{},
+					Links:  []models.MediaLink{{Key: "openlibrary", Value: server.URL + "/works/OL4W"}},
+					Fields: map[string]any{},
+				},
+			},
+		},
+		{
+			// The ISBN searched for is the one linked, not the first the
+			// work lists.
+			name:  "isbn wins over title",
+			query: Query{Title: "piranesi", ISBN: "9780747532699", Limit: 2},
+			want: []models.MetadataCandidate{{
+				Provider: "openlibrary", ID: "OL1W", ItemType: "book", Title: "Harry Potter and the Philosopher's Stone", Creator: "J. K. Rowling",
+				Genres: []string{},
+				Links:  []models.MediaLink{{Key: "openlibrary", Value: server.URL + "/works/OL1W"}, {Key: "isbn", Value: "9780747532699"}},
+				Fields: map[string]any{},
+			}},
+		},
+		{
+			name:  "no matches",
+			query: Query{Title: "nothing", Limit: 2},
+			want:  []models.MetadataCandidate{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := o.Search(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Search: %v", err)
+			}
+			assertJSON(t, got, tt.want)
+		})
+	}
+
+	if _, err := o.Search(context.Background(), Query{Title: "piranesi", Limit: 3}); err == nil {
+		t.Error("Search should report the provider's error status")
+	}
+}
+
+func TestOpenLibraryGet(t *testing.T) {
+	o, server := newTestOpenLibrary(t)
+
+	got, err := o.Get(context.Background(), "OL1W")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	assertJSON(t, got, &models.MetadataCandidate{
+		Provider: "openlibrary", ID: "OL1W", ItemType: "book", Title: "Harry Potter and the Philosopher's Stone", Creator: "J. K. Rowling",
+		Description: "A boy learns he is a wizard.",
+		Genres:      []string{"Magic", "Schools"},
+		CoverURL:    "https://covers.example.com/b/id/10521270-L.jpg",
+		Links:       []models.MediaLink{{Key: "openlibrary", Value: server.URL + "/works/OL1W"}},
+		Fields:      map[string]any{},
+	})
+
+	orphan, err := o.Get(context.Background(), "OL5W")
+	if err != nil {
+		t.Fatalf("Get with a missing author: %v", err)
+	}
+	if orphan.Creator != "" || orphan.Description != "No author." {
+		t.Errorf("Get with a missing author = %+v", orphan)
+	}
+
+	tests := []struct {
+		id       string
+		notFound bool
+	}{
+		{id: "OL404W", notFound: true},
+		{id: "OL1A", notFound: true},
+		{id: "../authors/OL2A", notFound: true},
+		{id: "OL6W"},
+		{id: "OL7W"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			_, err := o.Get(context.Background(), tt.id)
+			if err == nil {
+				t.Fatal("Get succeeded")
+			}
+			if errors.Is(err, ErrNotFound) != tt.notFound {
+				t.Errorf("Get = %v, not found: %v", err, tt.notFound)
+			}
+		})
+	}
+}
+
+// assertJSON compares got and want through their JSON encodings, which is
+// how candidates reach clients.
+func assertJSON(t *testing.T, got, want any) {
+	t.Helper()
+
+	gotJSON, _ := json.MarshalIndent(got, "", "  ")
+	wantJSON, _ := json.MarshalIndent(want, "", "  ")
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("got %s\nwant %s", gotJSON, wantJSON)
+	}
+}
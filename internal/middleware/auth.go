@@ -1,22 +1,27 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
 )
 
 type Claims struct {
-	UserID   int64  `json:"user_id"`
-	Username string `json:"username"`
-	Role     string `json:"role"`
+	UserID    int64  `json:"user_id"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func JWTAuth(jwtSecret string) gin.HandlerFunc {
+func JWTAuth(jwtSecret string, db *database.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -53,22 +58,56 @@ func JWTAuth(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		// The role is re-read from the database so promotions and demotions
+		// apply without waiting for the access token to expire.
+		var active, disabled bool
+		var role string
+		err = db.QueryRowContext(c.Request.Context(), `
+			SELECT s.revoked_at IS NULL AND s.expires_at > CURRENT_TIMESTAMP, u.disabled, u.role
+			FROM sessions s
+			JOIN users u ON u.id = s.user_id
+			WHERE s.id = ? AND s.user_id = ?
+		`, claims.SessionID, claims.UserID).Scan(&active, &disabled, &role)
+		if err == sql.ErrNoRows || (err == nil && !active) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has been revoked"})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify session"})
+			c.Abort()
+			return
+		}
+		if disabled {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account disabled"})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
-		c.Set("user_role", claims.Role)
+		c.Set("user_role", role)
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
 }
 
-func GenerateToken(userID int64, username string, role string, jwtSecret string, expirationHours int) (string, time.Time, error) {
-	expirationTime := time.Now().Add(time.Duration(expirationHours) * time.Hour)
+func GenerateToken(userID int64, username string, role string, sessionID string, jwtSecret string, ttl time.Duration) (string, time.Time, error) {
+	expirationTime := time.Now().Add(ttl)
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", time.Time{}, err
+	}
 
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		Role:     role,
+		UserID:    userID,
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(jti),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+)
+
+const testSecret = "test-secret"
+
+// newTestServer serves GET /me behind JWTAuth, answering with what the
+// middleware put in the context. The database has one user, alice (1), with
+// a live session "live", a revoked one and an expired one.
+func newTestServer(t *testing.T) (*gin.Engine, *database.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.InitDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("InitDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for _, stmt := range []string{
+		`INSERT INTO users (username, email, password_hash, role) VALUES ('alice', 'alice@example.com', 'x', 'normal')`,
+		`INSERT INTO sessions (id, user_id, expires_at) VALUES ('live', 1, datetime('now', '+1 day'))`,
+		`INSERT INTO sessions (id, user_id, expires_at, revoked_at) VALUES ('revoked', 1, datetime('now', '+1 day'), CURRENT_TIMESTAMP)`,
+		`INSERT INTO sessions (id, user_id, expires_at) VALUES ('expired', 1, datetime('now', '-1 minute'))`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := gin.New()
+	r.GET("/me", JWTAuth(testSecret, db), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"user_id":    c.GetInt64("user_id"),
+			"user_role":  c.GetString("user_role"),
+			"session_id": c.GetString("session_id"),
+		})
+	})
+	return r, db
+}
+
+func get(r *gin.Engine, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func token(t *testing.T, userID int64, sessionID string, secret string, ttl time.Duration) string {
+	t.Helper()
+
+	token, _, err := GenerateToken(userID, "alice", "admin", sessionID, secret, ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJWTAuth(t *testing.T) {
+	r, _ := newTestServer(t)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, &Claims{UserID: 1, Role: "admin", SessionID: "live"}).
+		SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{"no header", "", http.StatusUnauthorized},
+		{"not a bearer token", "Basic " + token(t, 1, "live", testSecret, time.Hour), http.StatusUnauthorized},
+		{"wrong secret", "Bearer " + token(t, 1, "live", "other-secret", time.Hour), http.StatusUnauthorized},
+		{"unsigned", "Bearer " + unsigned, http.StatusUnauthorized},
+		{"expired token", "Bearer " + token(t, 1, "live", testSecret, -time.Minute), http.StatusUnauthorized},
+		{"revoked session", "Bearer " + token(t, 1, "revoked", testSecret, time.Hour), http.StatusUnauthorized},
+		{"expired session", "Bearer " + token(t, 1, "expired", testSecret, time.Hour), http.StatusUnauthorized},
+		{"unknown session", "Bearer " + token(t, 1, "missing", testSecret, time.Hour), http.StatusUnauthorized},
+		{"someone else's session", "Bearer " + token(t, 2, "live", testSecret, time.Hour), http.StatusUnauthorized},
+		{"live session", "Bearer " + token(t, 1, "live", testSecret, time.Hour), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(r, tt.authorization)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.status, w.Body)
+			}
+		})
+	}
+}
+
+func TestJWTAuthReadsTheUser(t *testing.T) {
+	r, db := newTestServer(t)
+	bearer := "Bearer " + token(t, 1, "live", testSecret, time.Hour)
+
+	// The role comes from the database, not the admin claim in the token.
+	w := get(r, bearer)
+	if want := `{"session_id":"live","user_id":1,"user_role":"normal"}`; w.Body.String() != want {
+		t.Errorf("body = %s, want %s", w.Body, want)
+	}
+
+	if _, err := db.Exec(`UPDATE users SET role = 'admin' WHERE id = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if w := get(r, bearer); w.Body.String() != `{"session_id":"live","user_id":1,"user_role":"admin"}` {
+		t.Errorf("after promotion, body = %s", w.Body)
+	}
+
+	if _, err := db.Exec(`UPDATE users SET disabled = 1 WHERE id = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if w := get(r, bearer); w.Code != http.StatusForbidden {
+		t.Errorf("disabled user: status = %d, want 403", w.Code)
+	}
+
+	// Revoking the session turns away tokens issued for it right away.
+	if _, err := db.Exec(`UPDATE users SET disabled = 0 WHERE id = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`UPDATE sessions SET revoked_at = CURRENT_TIMESTAMP WHERE id = 'live'`); err != nil {
+		t.Fatal(err)
+	}
+	if w := get(r, bearer); w.Code != http.StatusUnauthorized {
+		t.Errorf("revoked session: status = %d, want 401", w.Code)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
 	"time"
 )
 
@@ -13,13 +15,15 @@ const (
 )
 
 type User struct {
-	ID           int64     `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	Role         string    `json:"role"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID                    int64     `json:"id"`
+	Username              string    `json:"username"`
+	Email                 string    `json:"email"`
+	PasswordHash          string    `json:"-"`
+	Role                  string    `json:"role"`
+	Disabled              bool      `json:"disabled"`
+	PasswordResetRequired bool      `json:"password_reset_required"`
+	CreatedAt             time.Time `json:"created_at"`
+	UpdatedAt             time.Time `json:"updated_at"`
 }
 
 type RegisterRequest struct {
@@ -33,11 +37,40 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type PasswordResetRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+type UserSearchParams struct {
+	Query    string `form:"q"`
+	Role     string `form:"role"`
+	Disabled *bool  `form:"disabled"`
+	Limit    int    `form:"limit"`
+	Offset   int    `form:"offset"`
+}
+
+// DeleteUserQuery confirms deleting an account that still owns entries,
+// along with everything in it.
+type DeleteUserQuery struct {
+	Purge bool `form:"purge"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=admin normal"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
 type AuthResponse struct {
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
-	User      UserInfo  `json:"user"`
-	Role      string    `json:"role"`
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+	User             UserInfo  `json:"user"`
+	Role             string    `json:"role"`
 }
 
 type UserInfo struct {
@@ -94,129 +127,178 @@ func (s StringArray) Value() (driver.Value, error) {
 	return string(bytes), err
 }
 
-type Game struct {
-	ID          int64       `json:"id"`
-	Title       string      `json:"title" binding:"required"`
-	Developer   string      `json:"developer" binding:"required"`
-	Genres      StringArray `json:"genres" binding:"required"`
-	Tags        StringArray `json:"tags" binding:"required"`
-	Rating      int         `json:"rating" binding:"required,min=1,max=5"`
-	Status      string      `json:"status" binding:"required"`
-	Description string      `json:"description" binding:"required"`
-	MyThoughts  string      `json:"my_thoughts" binding:"required"`
-	Links       []GameLink  `json:"links" binding:"required"`
-	CoverImage  string      `json:"cover_image" binding:"required"`
-	Explicit    bool        `json:"explicit"`
-	Color       string      `json:"color" binding:"required"`
-	Percent     int         `json:"percent" binding:"required,min=0,max=100"`
-	Bad         bool        `json:"bad"`
-	UserID      int64       `json:"user_id"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
+// MediaItem holds the columns shared by every media type. Each type's model
+// embeds it and adds its own fields; see the media package.
+type MediaItem struct {
+	ID            int64             `json:"id"`
+	Title         string            `json:"title"`
+	Genres        StringArray       `json:"genres"`
+	Tags          StringArray       `json:"tags"`
+	Rating        int               `json:"rating"`
+	Status        string            `json:"status"`
+	Description   string            `json:"description"`
+	MyThoughts    string            `json:"my_thoughts"`
+	Links         []MediaLink       `json:"links"`
+	CoverImage    string            `json:"cover_image"`
+	CoverVariants map[string]string `json:"cover_variants,omitempty"`
+	Explicit      bool              `json:"explicit"`
+	Color         string            `json:"color"`
+	Palette       StringArray       `json:"palette"`
+	BlurHash      string            `json:"blurhash,omitempty"`
+	UserID        int64             `json:"user_id"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	StartedAt     *time.Time        `json:"started_at"`
+	CompletedAt   *time.Time        `json:"completed_at"`
+	Highlights    *Highlights       `json:"highlights,omitempty"`
 }
 
-type Book struct {
-	ID          int64       `json:"id"`
-	Title       string      `json:"title" binding:"required"`
-	Author      string      `json:"author" binding:"required"`
-	Genres      StringArray `json:"genres" binding:"required"`
-	Tags        StringArray `json:"tags" binding:"required"`
-	Rating      int         `json:"rating" binding:"required,min=1,max=5"`
-	Status      string      `json:"status" binding:"required"`
-	Description string      `json:"description" binding:"required"`
-	MyThoughts  string      `json:"my_thoughts" binding:"required"`
-	Links       []BookLink  `json:"links" binding:"required"`
-	CoverImage  string      `json:"cover_image" binding:"required"`
-	Explicit    bool        `json:"explicit"`
-	Color       string      `json:"color" binding:"required"`
-	UserID      int64       `json:"user_id"`
-	CreatedAt   time.Time   `json:"created_at"`
-	UpdatedAt   time.Time   `json:"updated_at"`
-}
-
-type GameLink struct {
-	ID     int64  `json:"id"`
-	Key    string `json:"key" binding:"required"`
-	Value  string `json:"value" binding:"required"`
-	GameID int64  `json:"game_id,omitempty"`
-}
-
-type BookLink struct {
-	ID     int64  `json:"id"`
-	Key    string `json:"key" binding:"required"`
-	Value  string `json:"value" binding:"required"`
-	BookID int64  `json:"book_id,omitempty"`
+func (m *MediaItem) Common() *MediaItem { return m }
+
+// The status vocabulary. Every media type allows some or all of it (see
+// media.Type.Statuses). Binding tags spell the list out again as
+// oneof=planned in_progress paused completed dropped.
+const (
+	StatusPlanned    = "planned"
+	StatusInProgress = "in_progress"
+	StatusPaused     = "paused"
+	StatusCompleted  = "completed"
+	StatusDropped    = "dropped"
+)
+
+var Statuses = []string{StatusPlanned, StatusInProgress, StatusPaused, StatusCompleted, StatusDropped}
+
+// StatusTransitions lists the statuses each status may move to. Staying put
+// is always allowed.
+var StatusTransitions = map[string][]string{
+	StatusPlanned:    {StatusInProgress, StatusCompleted, StatusDropped},
+	StatusInProgress: {StatusPaused, StatusCompleted, StatusDropped},
+	StatusPaused:     {StatusInProgress, StatusCompleted, StatusDropped},
+	StatusCompleted:  {StatusInProgress},
+	StatusDropped:    {StatusPlanned, StatusInProgress},
 }
 
-type CreateGameRequest struct {
+func CanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range StatusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeStatus is a status a media type's entries can take, with the type's
+// own label for it.
+type TypeStatus struct {
+	Status string `json:"status"`
+	Label  string `json:"label"`
+}
+
+// StatusVocabulary describes the statuses, their transitions, and which
+// statuses each media type allows; a type's transitions are limited to its
+// own statuses.
+type StatusVocabulary struct {
+	Statuses    []string                `json:"statuses"`
+	Transitions map[string][]string     `json:"transitions"`
+	Types       map[string][]TypeStatus `json:"types"`
+}
+
+// StatusChange is one entry of a media entry's status history. From is
+// empty for the status it was created with.
+type StatusChange struct {
+	ID        int64     `json:"id"`
+	ItemType  string    `json:"item_type"`
+	ItemID    int64     `json:"item_id"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to"`
+	AuthorID  *int64    `json:"author_id"`
+	Author    string    `json:"author,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MediaEntry is a media type's model. Extra returns pointers to the
+// type-specific fields, in the order of the type's columns.
+type MediaEntry interface {
+	Common() *MediaItem
+	Extra() []any
+}
+
+type MediaLink struct {
+	ID    int64  `json:"id"`
+	Key   string `json:"key" binding:"required"`
+	Value string `json:"value" binding:"required"`
+}
+
+// Highlights carries FTS snippets of the fields that matched a q= search,
+// HTML-escaped, with matches wrapped in <mark> tags.
+type Highlights struct {
+	MyThoughts  string `json:"my_thoughts,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type CreateMediaRequest struct {
 	Title         string      `json:"title" binding:"required"`
-	Developer     string      `json:"developer" binding:"required"`
 	Genres        StringArray `json:"genres" binding:"required"`
 	Tags          StringArray `json:"tags" binding:"required"`
 	Rating        int         `json:"rating" binding:"required,min=1,max=5"`
-	Status        string      `json:"status" binding:"required"`
+	Status        string      `json:"status" binding:"required,oneof=planned in_progress paused completed dropped"`
 	Description   string      `json:"description" binding:"required"`
 	MyThoughts    string      `json:"my_thoughts" binding:"required"`
-	Links         []GameLink  `json:"links" binding:"required"`
+	Links         []MediaLink `json:"links" binding:"required"`
 	CoverImage    string      `json:"cover_image"`
 	CoverImageURL string      `json:"cover_image_url"`
 	Explicit      bool        `json:"explicit"`
-	Color         string      `json:"color" binding:"required"`
-	Percent       int         `json:"percent" binding:"required,min=0,max=100"`
-	Bad           bool        `json:"bad"`
+	Color         string      `json:"color"`
 }
 
-type CreateBookRequest struct {
-	Title         string      `json:"title" binding:"required"`
-	Author        string      `json:"author" binding:"required"`
-	Genres        StringArray `json:"genres" binding:"required"`
-	Tags          StringArray `json:"tags" binding:"required"`
-	Rating        int         `json:"rating" binding:"required,min=1,max=5"`
-	Status        string      `json:"status" binding:"required"`
-	Description   string      `json:"description" binding:"required"`
-	MyThoughts    string      `json:"my_thoughts" binding:"required"`
-	Links         []BookLink  `json:"links" binding:"required"`
-	CoverImage    string      `json:"cover_image" binding:"required"`
-	CoverImageURL string      `json:"cover_image_url" binding:"required"`
-	Explicit      bool        `json:"explicit"`
-	Color         string      `json:"color" binding:"required"`
+func (r *CreateMediaRequest) Common() *CreateMediaRequest { return r }
+
+// MediaCreate is a media type's create request. Extra returns the values of
+// the type-specific fields, in the order of the type's columns.
+type MediaCreate interface {
+	Common() *CreateMediaRequest
+	Extra() []any
 }
 
-type UpdateGameRequest struct {
+type UpdateMediaRequest struct {
 	Title       string      `json:"title"`
-	Developer   string      `json:"developer"`
 	Genres      StringArray `json:"genres"`
 	Tags        StringArray `json:"tags"`
 	Rating      int         `json:"rating" binding:"omitempty,min=1,max=5"`
-	Status      string      `json:"status"`
+	Status      string      `json:"status" binding:"omitempty,oneof=planned in_progress paused completed dropped"`
 	Description string      `json:"description"`
 	MyThoughts  string      `json:"my_thoughts"`
-	Links       []GameLink  `json:"links"`
+	Links       []MediaLink `json:"links"`
 	CoverImage  string      `json:"cover_image"`
 	Explicit    *bool       `json:"explicit"`
 	Color       string      `json:"color"`
-	Percent     int         `json:"percent" binding:"omitempty,min=0,max=100"`
-	Bad         *bool       `json:"bad"`
 }
 
-type UpdateBookRequest struct {
-	Title       string      `json:"title" binding:"required"`
-	Author      string      `json:"author" binding:"required"`
-	Genres      StringArray `json:"genres" binding:"required"`
-	Tags        StringArray `json:"tags" binding:"required"`
-	Rating      int         `json:"rating" binding:"required,min=1,max=5"`
-	Status      string      `json:"status" binding:"required"`
-	Description string      `json:"description" binding:"required"`
-	MyThoughts  string      `json:"my_thoughts" binding:"required"`
-	Links       []BookLink  `json:"links" binding:"required"`
-	CoverImage  string      `json:"cover_image" binding:"required"`
-	Explicit    *bool       `json:"explicit"`
-	Color       string      `json:"color" binding:"required"`
+func (r *UpdateMediaRequest) Common() *UpdateMediaRequest { return r }
+
+// MediaNormalizer is implemented by create and update requests with checks
+// binding tags can't express or fields derived from others. Handlers call it
+// right after binding.
+type MediaNormalizer interface {
+	Normalize() error
 }
 
-type GameSearchParams struct {
+// MediaUpdate is a media type's update request. Extra returns the
+// type-specific fields in column order; empty strings and nil pointers are
+// left unchanged.
+type MediaUpdate interface {
+	Common() *UpdateMediaRequest
+	Extra() []any
+}
+
+// MediaSearchParams are the filters every media type supports. Types embed
+// it and add query parameters for their own filterable columns.
+type MediaSearchParams struct {
+	Query         string   `form:"q"`
 	Title         string   `form:"title"`
-	Developer     string   `form:"developer"`
 	Genres        []string `form:"genres"`
 	Tags          []string `form:"tags"`
 	Description   string   `form:"description"`
@@ -227,36 +309,576 @@ type GameSearchParams struct {
 	MaxRating     int      `form:"max_rating"`
 	CreatedAfter  string   `form:"created_after"`
 	CreatedBefore string   `form:"created_before"`
-	Status        string   `form:"status"`
+	Status        string   `form:"status" binding:"omitempty,oneof=planned in_progress paused completed dropped"`
 	Explicit      *bool    `form:"explicit"`
-	Bad           *bool    `form:"bad"`
-	MinPercent    int      `form:"min_percent"`
-	MaxPercent    int      `form:"max_percent"`
 	SortBy        string   `form:"sort_by"`
 	SortOrder     string   `form:"sort_order"`
 	Limit         int      `form:"limit"`
 	Offset        int      `form:"offset"`
 }
 
+func (p *MediaSearchParams) Common() *MediaSearchParams { return p }
+
+type MediaSearch interface {
+	Common() *MediaSearchParams
+}
+
+// Game playtime is derived from its finished play sessions, in minutes;
+// Playing reports a session still running.
+type Game struct {
+	MediaItem
+	Developer string `json:"developer"`
+	Percent   int    `json:"percent"`
+	Bad       bool   `json:"bad"`
+	Playtime  int    `json:"playtime"`
+	Playing   bool   `json:"playing"`
+}
+
+func (g *Game) Extra() []any {
+	return []any{&g.Developer, &g.Percent, &g.Bad, &g.Playtime, &g.Playing}
+}
+
+type CreateGameRequest struct {
+	CreateMediaRequest
+	Developer string `json:"developer" binding:"required"`
+	Percent   int    `json:"percent" binding:"min=0,max=100"`
+	Bad       bool   `json:"bad"`
+}
+
+func (r *CreateGameRequest) Extra() []any { return []any{r.Developer, r.Percent, r.Bad} }
+
+type UpdateGameRequest struct {
+	UpdateMediaRequest
+	Developer string `json:"developer"`
+	Percent   *int   `json:"percent" binding:"omitempty,min=0,max=100"`
+	Bad       *bool  `json:"bad"`
+}
+
+func (r *UpdateGameRequest) Extra() []any { return []any{r.Developer, r.Percent, r.Bad} }
+
+type GameSearchParams struct {
+	MediaSearchParams
+	Developer  string `form:"developer"`
+	Bad        *bool  `form:"bad"`
+	MinPercent int    `form:"min_percent"`
+	MaxPercent int    `form:"max_percent"`
+	// Playtime bounds are in minutes.
+	MinPlaytime int   `form:"min_playtime"`
+	MaxPlaytime int   `form:"max_playtime"`
+	Playing     *bool `form:"playing"`
+}
+
+// Book progress is current_page out of page_count. Percent and PagesPerHour
+// are derived: percent from the two, pages per hour from the book's timed
+// reading sessions.
+type Book struct {
+	MediaItem
+	Author       string   `json:"author"`
+	PageCount    *int     `json:"page_count"`
+	CurrentPage  int      `json:"current_page"`
+	Percent      *int     `json:"percent"`
+	PagesPerHour *float64 `json:"pages_per_hour"`
+}
+
+func (b *Book) Extra() []any {
+	return []any{&b.Author, &b.PageCount, &b.CurrentPage, &b.Percent, &b.PagesPerHour}
+}
+
+type CreateBookRequest struct {
+	CreateMediaRequest
+	Author      string `json:"author" binding:"required"`
+	PageCount   *int   `json:"page_count" binding:"omitempty,min=1"`
+	CurrentPage int    `json:"current_page" binding:"min=0"`
+}
+
+func (r *CreateBookRequest) Extra() []any { return []any{r.Author, r.PageCount, r.CurrentPage} }
+
+func (r *CreateBookRequest) Normalize() error {
+	return checkProgress("page", r.CurrentPage, r.PageCount)
+}
+
+type UpdateBookRequest struct {
+	UpdateMediaRequest
+	Author      string `json:"author"`
+	PageCount   *int   `json:"page_count" binding:"omitempty,min=1"`
+	CurrentPage *int   `json:"current_page" binding:"omitempty,min=0"`
+}
+
+func (r *UpdateBookRequest) Extra() []any { return []any{r.Author, r.PageCount, r.CurrentPage} }
+
 type BookSearchParams struct {
-	Title         string   `form:"title"`
-	Author        string   `form:"author"`
-	Genres        []string `form:"genres"`
-	Tags          []string `form:"tags"`
-	Description   string   `form:"description"`
-	MyThoughts    string   `form:"my_thoughts"`
-	Color         string   `form:"color"`
-	Rating        int      `form:"rating"`
-	MinRating     int      `form:"min_rating"`
-	MaxRating     int      `form:"max_rating"`
-	CreatedAfter  string   `form:"created_after"`
-	CreatedBefore string   `form:"created_before"`
-	Status        string   `form:"status"`
-	Explicit      *bool    `form:"explicit"`
-	SortBy        string   `form:"sort_by"`
-	SortOrder     string   `form:"sort_order"`
-	Limit         int      `form:"limit"`
-	Offset        int      `form:"offset"`
+	MediaSearchParams
+	Author     string `form:"author"`
+	MinPercent int    `form:"min_percent"`
+	MaxPercent int    `form:"max_percent"`
+}
+
+// ReadingSession is a stretch of reading from StartPage to EndPage. Sessions
+// without an end time count towards progress but not reading speed.
+type ReadingSession struct {
+	ID        int64      `json:"id"`
+	BookID    int64      `json:"book_id"`
+	StartPage int        `json:"start_page"`
+	EndPage   int        `json:"end_page"`
+	Pages     int        `json:"pages"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Minutes   *float64   `json:"minutes"`
+	Notes     string     `json:"notes"`
+	UserID    int64      `json:"user_id"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CreateReadingSessionRequest logs a session; start_page defaults to the
+// book's current page, and the book moves on to end_page.
+type CreateReadingSessionRequest struct {
+	StartPage *int       `json:"start_page" binding:"omitempty,min=0"`
+	EndPage   int        `json:"end_page" binding:"min=0"`
+	StartedAt time.Time  `json:"started_at" binding:"required"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Notes     string     `json:"notes"`
+}
+
+// ReadingSessionResponse is the logged session and where it left the book.
+type ReadingSessionResponse struct {
+	Session     ReadingSession `json:"session"`
+	CurrentPage int            `json:"current_page"`
+	PageCount   *int           `json:"page_count"`
+	Status      string         `json:"status"`
+	Message     string         `json:"message"`
+}
+
+// PlaySession is a stretch of play; a session without an end time is still
+// running.
+type PlaySession struct {
+	ID        int64      `json:"id"`
+	GameID    int64      `json:"game_id"`
+	Platform  string     `json:"platform"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Minutes   *float64   `json:"minutes"`
+	Notes     string     `json:"notes"`
+	UserID    int64      `json:"user_id"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CreatePlaySessionRequest logs a finished session after the fact.
+type CreatePlaySessionRequest struct {
+	Platform  string    `json:"platform"`
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required"`
+	Notes     string    `json:"notes"`
+}
+
+// StartPlaySessionRequest starts a session now unless started_at is given.
+type StartPlaySessionRequest struct {
+	Platform  string     `json:"platform"`
+	StartedAt *time.Time `json:"started_at"`
+	Notes     string     `json:"notes"`
+}
+
+// StopPlaySessionRequest stops the running session now unless ended_at is
+// given; notes, if set, replace the session's.
+type StopPlaySessionRequest struct {
+	EndedAt *time.Time `json:"ended_at"`
+	Notes   string     `json:"notes"`
+}
+
+type Movie struct {
+	MediaItem
+	Director    string `json:"director"`
+	Runtime     int    `json:"runtime"`
+	ReleaseYear *int   `json:"release_year"`
+}
+
+func (m *Movie) Extra() []any { return []any{&m.Director, &m.Runtime, &m.ReleaseYear} }
+
+// CreateMovieRequest takes the runtime in minutes.
+type CreateMovieRequest struct {
+	CreateMediaRequest
+	Director    string `json:"director" binding:"required"`
+	Runtime     int    `json:"runtime" binding:"min=0"`
+	ReleaseYear *int   `json:"release_year" binding:"omitempty,min=1870,max=2100"`
+}
+
+func (r *CreateMovieRequest) Extra() []any { return []any{r.Director, r.Runtime, r.ReleaseYear} }
+
+type UpdateMovieRequest struct {
+	UpdateMediaRequest
+	Director    string `json:"director"`
+	Runtime     *int   `json:"runtime" binding:"omitempty,min=0"`
+	ReleaseYear *int   `json:"release_year" binding:"omitempty,min=1870,max=2100"`
+}
+
+func (r *UpdateMovieRequest) Extra() []any { return []any{r.Director, r.Runtime, r.ReleaseYear} }
+
+type MovieSearchParams struct {
+	MediaSearchParams
+	Director       string `form:"director"`
+	MinRuntime     int    `form:"min_runtime"`
+	MaxRuntime     int    `form:"max_runtime"`
+	MinReleaseYear int    `form:"min_release_year"`
+	MaxReleaseYear int    `form:"max_release_year"`
+}
+
+// SeasonProgress is how far into one season of a show the user is.
+type SeasonProgress struct {
+	Season   int `json:"season" binding:"min=1"`
+	Episodes int `json:"episodes" binding:"min=0"`
+	Watched  int `json:"watched" binding:"min=0"`
+}
+
+type SeasonProgressList []SeasonProgress
+
+func (s *SeasonProgressList) Scan(value any) error {
+	if value == nil {
+		*s = SeasonProgressList{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		str, ok := value.(string)
+		if !ok {
+			return errors.New("failed to scan SeasonProgressList: invalid type")
+		}
+		bytes = []byte(str)
+	}
+
+	return json.Unmarshal(bytes, s)
+}
+
+func (s SeasonProgressList) Value() (driver.Value, error) {
+	if s == nil {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal(s)
+	return string(bytes), err
+}
+
+// Normalize checks that seasons are listed once and that no more episodes
+// are watched than a season has.
+func (s SeasonProgressList) Normalize() error {
+	seen := map[int]bool{}
+	for _, season := range s {
+		if seen[season.Season] {
+			return fmt.Errorf("season %d is listed more than once", season.Season)
+		}
+		seen[season.Season] = true
+
+		if season.Episodes > 0 && season.Watched > season.Episodes {
+			return fmt.Errorf("season %d has only %d episodes but %d are marked watched", season.Season, season.Episodes, season.Watched)
+		}
+	}
+	return nil
+}
+
+// Watched totals the episodes watched across seasons.
+func (s SeasonProgressList) Watched() int {
+	total := 0
+	for _, season := range s {
+		total += season.Watched
+	}
+	return total
+}
+
+type Show struct {
+	MediaItem
+	Creator         string             `json:"creator"`
+	Seasons         int                `json:"seasons"`
+	EpisodesWatched int                `json:"episodes_watched"`
+	SeasonProgress  SeasonProgressList `json:"season_progress"`
+}
+
+func (s *Show) Extra() []any {
+	return []any{&s.Creator, &s.Seasons, &s.EpisodesWatched, &s.SeasonProgress}
+}
+
+// CreateShowRequest derives episodes_watched from season_progress when only
+// the latter is given.
+type CreateShowRequest struct {
+	CreateMediaRequest
+	Creator         string             `json:"creator" binding:"required"`
+	Seasons         int                `json:"seasons" binding:"min=0"`
+	EpisodesWatched int                `json:"episodes_watched" binding:"min=0"`
+	SeasonProgress  SeasonProgressList `json:"season_progress" binding:"omitempty,dive"`
+}
+
+func (r *CreateShowRequest) Extra() []any {
+	return []any{r.Creator, r.Seasons, r.EpisodesWatched, r.SeasonProgress}
+}
+
+func (r *CreateShowRequest) Normalize() error {
+	if err := r.SeasonProgress.Normalize(); err != nil {
+		return err
+	}
+	if r.EpisodesWatched == 0 {
+		r.EpisodesWatched = r.SeasonProgress.Watched()
+	}
+	return nil
+}
+
+type UpdateShowRequest struct {
+	UpdateMediaRequest
+	Creator         string             `json:"creator"`
+	Seasons         *int               `json:"seasons" binding:"omitempty,min=0"`
+	EpisodesWatched *int               `json:"episodes_watched" binding:"omitempty,min=0"`
+	SeasonProgress  SeasonProgressList `json:"season_progress" binding:"omitempty,dive"`
+}
+
+func (r *UpdateShowRequest) Extra() []any {
+	return []any{r.Creator, r.Seasons, r.EpisodesWatched, r.SeasonProgress}
+}
+
+func (r *UpdateShowRequest) Normalize() error {
+	if err := r.SeasonProgress.Normalize(); err != nil {
+		return err
+	}
+	if r.EpisodesWatched == nil && r.SeasonProgress != nil {
+		watched := r.SeasonProgress.Watched()
+		r.EpisodesWatched = &watched
+	}
+	return nil
+}
+
+type ShowSearchParams struct {
+	MediaSearchParams
+	Creator            string `form:"creator"`
+	MinSeasons         int    `form:"min_seasons"`
+	MaxSeasons         int    `form:"max_seasons"`
+	MinEpisodesWatched int    `form:"min_episodes_watched"`
+	MaxEpisodesWatched int    `form:"max_episodes_watched"`
+}
+
+// Track is one entry of an album's tracklist. Duration is in seconds.
+type Track struct {
+	Number    int    `json:"number" binding:"min=0"`
+	Title     string `json:"title" binding:"required"`
+	Duration  int    `json:"duration" binding:"min=0"`
+	Favourite bool   `json:"favourite"`
+}
+
+type Tracklist []Track
+
+func (t *Tracklist) Scan(value any) error {
+	if value == nil {
+		*t = Tracklist{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		str, ok := value.(string)
+		if !ok {
+			return errors.New("failed to scan Tracklist: invalid type")
+		}
+		bytes = []byte(str)
+	}
+
+	return json.Unmarshal(bytes, t)
+}
+
+func (t Tracklist) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	bytes, err := json.Marshal(t)
+	return string(bytes), err
+}
+
+// Normalize numbers unnumbered tracks by their position, rejects duplicate
+// numbers and sorts the list by number.
+func (t Tracklist) Normalize() error {
+	seen := map[int]bool{}
+	for i := range t {
+		if t[i].Number == 0 {
+			t[i].Number = i + 1
+		}
+		if seen[t[i].Number] {
+			return fmt.Errorf("track %d is listed more than once", t[i].Number)
+		}
+		seen[t[i].Number] = true
+	}
+
+	sort.SliceStable(t, func(i, j int) bool { return t[i].Number < t[j].Number })
+	return nil
+}
+
+type Album struct {
+	MediaItem
+	Artist      string    `json:"artist"`
+	ReleaseYear *int      `json:"release_year"`
+	Tracklist   Tracklist `json:"tracklist"`
+}
+
+func (a *Album) Extra() []any { return []any{&a.Artist, &a.ReleaseYear, &a.Tracklist} }
+
+type CreateAlbumRequest struct {
+	CreateMediaRequest
+	Artist      string    `json:"artist" binding:"required"`
+	ReleaseYear *int      `json:"release_year" binding:"omitempty,min=1860,max=2100"`
+	Tracklist   Tracklist `json:"tracklist" binding:"omitempty,dive"`
+}
+
+func (r *CreateAlbumRequest) Extra() []any { return []any{r.Artist, r.ReleaseYear, r.Tracklist} }
+
+func (r *CreateAlbumRequest) Normalize() error { return r.Tracklist.Normalize() }
+
+// UpdateAlbumRequest replaces the whole tracklist when one is given.
+type UpdateAlbumRequest struct {
+	UpdateMediaRequest
+	Artist      string    `json:"artist"`
+	ReleaseYear *int      `json:"release_year" binding:"omitempty,min=1860,max=2100"`
+	Tracklist   Tracklist `json:"tracklist" binding:"omitempty,dive"`
+}
+
+func (r *UpdateAlbumRequest) Extra() []any { return []any{r.Artist, r.ReleaseYear, r.Tracklist} }
+
+func (r *UpdateAlbumRequest) Normalize() error { return r.Tracklist.Normalize() }
+
+type AlbumSearchParams struct {
+	MediaSearchParams
+	Artist         string `form:"artist"`
+	MinReleaseYear int    `form:"min_release_year"`
+	MaxReleaseYear int    `form:"max_release_year"`
+	HasFavourites  *bool  `form:"has_favourites"`
+}
+
+// ProgressRequest moves one progress counter, either to Current or by
+// Increment (which may be negative). Unit picks the counter and defaults to
+// the type's first.
+type ProgressRequest struct {
+	Unit      string `json:"unit"`
+	Current   *int   `json:"current" binding:"omitempty,min=0"`
+	Increment int    `json:"increment"`
+}
+
+type ProgressResponse struct {
+	ID      int64  `json:"id"`
+	Unit    string `json:"unit"`
+	Current int    `json:"current"`
+	Total   *int   `json:"total"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// checkProgress rejects a current count past a known total.
+func checkProgress(unit string, current int, total *int) error {
+	if total != nil && current > *total {
+		return fmt.Errorf("%d %ss is past the total of %d", current, unit, *total)
+	}
+	return nil
+}
+
+type Anime struct {
+	MediaItem
+	Studio          string `json:"studio"`
+	EpisodesTotal   *int   `json:"episodes_total"`
+	EpisodesWatched int    `json:"episodes_watched"`
+	AiringStatus    string `json:"airing_status"`
+}
+
+func (a *Anime) Extra() []any {
+	return []any{&a.Studio, &a.EpisodesTotal, &a.EpisodesWatched, &a.AiringStatus}
+}
+
+// CreateAnimeRequest leaves episodes_total empty for series still airing
+// without a known length.
+type CreateAnimeRequest struct {
+	CreateMediaRequest
+	Studio          string `json:"studio" binding:"required"`
+	EpisodesTotal   *int   `json:"episodes_total" binding:"omitempty,min=1"`
+	EpisodesWatched int    `json:"episodes_watched" binding:"min=0"`
+	AiringStatus    string `json:"airing_status" binding:"omitempty,oneof=upcoming airing finished"`
+}
+
+func (r *CreateAnimeRequest) Extra() []any {
+	return []any{r.Studio, r.EpisodesTotal, r.EpisodesWatched, r.AiringStatus}
+}
+
+func (r *CreateAnimeRequest) Normalize() error {
+	return checkProgress("episode", r.EpisodesWatched, r.EpisodesTotal)
+}
+
+type UpdateAnimeRequest struct {
+	UpdateMediaRequest
+	Studio          string `json:"studio"`
+	EpisodesTotal   *int   `json:"episodes_total" binding:"omitempty,min=1"`
+	EpisodesWatched *int   `json:"episodes_watched" binding:"omitempty,min=0"`
+	AiringStatus    string `json:"airing_status" binding:"omitempty,oneof=upcoming airing finished"`
+}
+
+func (r *UpdateAnimeRequest) Extra() []any {
+	return []any{r.Studio, r.EpisodesTotal, r.EpisodesWatched, r.AiringStatus}
+}
+
+type AnimeSearchParams struct {
+	MediaSearchParams
+	Studio             string `form:"studio"`
+	MinEpisodesTotal   int    `form:"min_episodes_total"`
+	MaxEpisodesTotal   int    `form:"max_episodes_total"`
+	MinEpisodesWatched int    `form:"min_episodes_watched"`
+	MaxEpisodesWatched int    `form:"max_episodes_watched"`
+	AiringStatus       string `form:"airing_status"`
+}
+
+type Manga struct {
+	MediaItem
+	Author           string `json:"author"`
+	ChaptersTotal    *int   `json:"chapters_total"`
+	ChaptersRead     int    `json:"chapters_read"`
+	VolumesTotal     *int   `json:"volumes_total"`
+	VolumesRead      int    `json:"volumes_read"`
+	PublishingStatus string `json:"publishing_status"`
+}
+
+func (m *Manga) Extra() []any {
+	return []any{&m.Author, &m.ChaptersTotal, &m.ChaptersRead, &m.VolumesTotal, &m.VolumesRead, &m.PublishingStatus}
+}
+
+type CreateMangaRequest struct {
+	CreateMediaRequest
+	Author           string `json:"author" binding:"required"`
+	ChaptersTotal    *int   `json:"chapters_total" binding:"omitempty,min=1"`
+	ChaptersRead     int    `json:"chapters_read" binding:"min=0"`
+	VolumesTotal     *int   `json:"volumes_total" binding:"omitempty,min=1"`
+	VolumesRead      int    `json:"volumes_read" binding:"min=0"`
+	PublishingStatus string `json:"publishing_status" binding:"omitempty,oneof=upcoming publishing hiatus finished cancelled"`
+}
+
+func (r *CreateMangaRequest) Extra() []any {
+	return []any{r.Author, r.ChaptersTotal, r.ChaptersRead, r.VolumesTotal, r.VolumesRead, r.PublishingStatus}
+}
+
+func (r *CreateMangaRequest) Normalize() error {
+	if err := checkProgress("chapter", r.ChaptersRead, r.ChaptersTotal); err != nil {
+		return err
+	}
+	return checkProgress("volume", r.VolumesRead, r.VolumesTotal)
+}
+
+type UpdateMangaRequest struct {
+	UpdateMediaRequest
+	Author           string `json:"author"`
+	ChaptersTotal    *int   `json:"chapters_total" binding:"omitempty,min=1"`
+	ChaptersRead     *int   `json:"chapters_read" binding:"omitempty,min=0"`
+	VolumesTotal     *int   `json:"volumes_total" binding:"omitempty,min=1"`
+	VolumesRead      *int   `json:"volumes_read" binding:"omitempty,min=0"`
+	PublishingStatus string `json:"publishing_status" binding:"omitempty,oneof=upcoming publishing hiatus finished cancelled"`
+}
+
+func (r *UpdateMangaRequest) Extra() []any {
+	return []any{r.Author, r.ChaptersTotal, r.ChaptersRead, r.VolumesTotal, r.VolumesRead, r.PublishingStatus}
+}
+
+type MangaSearchParams struct {
+	MediaSearchParams
+	Author           string `form:"author"`
+	MinChaptersRead  int    `form:"min_chapters_read"`
+	MaxChaptersRead  int    `form:"max_chapters_read"`
+	MinVolumesRead   int    `form:"min_volumes_read"`
+	MaxVolumesRead   int    `form:"max_volumes_read"`
+	PublishingStatus string `form:"publishing_status"`
 }
 
 type RouteInfo struct {
@@ -276,9 +898,13 @@ type RouteGroup struct {
 	Routes      []RouteInfo `json:"routes"`
 }
 
+// Comment is attached to a media entry. GameID and BookID repeat ItemID for
+// games and books, for clients predating the other media types.
 type Comment struct {
 	ID        int64     `json:"id"`
 	Content   string    `json:"content" binding:"required,min=1,max=1000"`
+	ItemType  string    `json:"item_type"`
+	ItemID    int64     `json:"item_id"`
 	GameID    *int64    `json:"game_id,omitempty"`
 	BookID    *int64    `json:"book_id,omitempty"`
 	UserID    int64     `json:"user_id"`
@@ -287,12 +913,546 @@ type Comment struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// CreateCommentRequest names its entry with item_type and item_id; game_id
+// and book_id are accepted as shorthands.
 type CreateCommentRequest struct {
-	Content string `json:"content" binding:"required,min=1,max=1000"`
-	GameID  *int64 `json:"game_id"`
-	BookID  *int64 `json:"book_id"`
+	Content  string `json:"content" binding:"required,min=1,max=1000"`
+	ItemType string `json:"item_type"`
+	ItemID   int64  `json:"item_id"`
+	GameID   *int64 `json:"game_id"`
+	BookID   *int64 `json:"book_id"`
+}
+
+type CommentQuery struct {
+	ItemType string `form:"item_type"`
+	ItemID   int64  `form:"item_id"`
+	GameID   *int64 `form:"game_id"`
+	BookID   *int64 `form:"book_id"`
 }
 
 type UpdateCommentRequest struct {
 	Content string `json:"content" binding:"required,min=1,max=1000"`
 }
+
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+)
+
+// Revision is a full snapshot of a game or book taken after every change
+// (before, for deletes).
+type Revision struct {
+	ID        int64            `json:"id"`
+	ItemType  string           `json:"item_type"`
+	ItemID    int64            `json:"item_id"`
+	Action    string           `json:"action"`
+	AuthorID  *int64           `json:"author_id"`
+	Author    string           `json:"author,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	Snapshot  *RevisionContent `json:"snapshot,omitempty"`
+}
+
+// RevisionContent holds the entry's row, column by column, and its links.
+type RevisionContent struct {
+	Row   map[string]any `json:"row"`
+	Links []RevisionLink `json:"links"`
+}
+
+type RevisionLink struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type RevisionChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type RevisionDiff struct {
+	From         Revision         `json:"from"`
+	To           Revision         `json:"to"`
+	Changes      []RevisionChange `json:"changes"`
+	LinksAdded   []RevisionLink   `json:"links_added"`
+	LinksRemoved []RevisionLink   `json:"links_removed"`
+}
+
+type RevisionDiffParams struct {
+	From int64 `form:"from" binding:"required"`
+	To   int64 `form:"to"`
+}
+
+// TrashItem is a soft-deleted media entry or resource.
+type TrashItem struct {
+	Type      string    `json:"type"`
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+type TrashParams struct {
+	Type string `form:"type"`
+}
+
+// AuditEntry records one mutating API call. Before and After hold the
+// entity's state for catalog entries (games, books, resources).
+type AuditEntry struct {
+	ID            int64           `json:"id"`
+	ActorID       *int64          `json:"actor_id"`
+	ActorUsername string          `json:"actor_username,omitempty"`
+	ActorRole     string          `json:"actor_role,omitempty"`
+	IP            string          `json:"ip"`
+	Method        string          `json:"method"`
+	Route         string          `json:"route"`
+	Path          string          `json:"path"`
+	Action        string          `json:"action"`
+	EntityType    string          `json:"entity_type,omitempty"`
+	EntityID      string          `json:"entity_id,omitempty"`
+	Before        json.RawMessage `json:"before,omitempty"`
+	After         json.RawMessage `json:"after,omitempty"`
+	Status        int             `json:"status"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// AuditSearchParams filters the audit log. Since and Until accept RFC 3339
+// timestamps or plain dates; Until is inclusive of the whole day. From and To
+// are aliases for them.
+type AuditSearchParams struct {
+	ActorID    int64  `form:"actor_id"`
+	Actor      string `form:"actor"`
+	EntityType string `form:"entity_type"`
+	EntityID   string `form:"entity_id"`
+	Action     string `form:"action"`
+	Method     string `form:"method" binding:"omitempty,oneof=POST PUT PATCH DELETE"`
+	Status     int    `form:"status"`
+	Since      string `form:"since"`
+	Until      string `form:"until"`
+	From       string `form:"from"`
+	To         string `form:"to"`
+	Limit      int    `form:"limit"`
+	Offset     int    `form:"offset"`
+}
+
+// Term is a tag or genre with the number of live entries using it, overall
+// and per media type.
+type Term struct {
+	ID    int64          `json:"id"`
+	Name  string         `json:"name"`
+	Count int            `json:"count"`
+	Usage map[string]int `json:"usage"`
+}
+
+type TermQuery struct {
+	Prefix string `form:"prefix"`
+	Type   string `form:"type"`
+	Limit  int    `form:"limit"`
+}
+
+type RenameTermRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// MergeTermsRequest folds the From terms into Into on every entry.
+type MergeTermsRequest struct {
+	From []int64 `json:"from" binding:"required,min=1"`
+	Into int64   `json:"into" binding:"required"`
+}
+
+// TermChangeResponse reports how many entries a rename, merge or delete
+// rewrote.
+type TermChangeResponse struct {
+	Updated int    `json:"updated"`
+	Message string `json:"message"`
+}
+
+const (
+	VisibilityPrivate  = "private"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPublic   = "public"
+)
+
+// Collection is an ordered, curated list of media entries of any type.
+// Private collections are only visible to their owner; unlisted ones to
+// anyone with the share token; public ones to everyone. ShareToken is only
+// shown to the owner.
+type Collection struct {
+	ID          int64            `json:"id"`
+	UserID      int64            `json:"user_id"`
+	Username    string           `json:"username"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	CoverImage  string           `json:"cover_image"`
+	Visibility  string           `json:"visibility"`
+	ShareToken  string           `json:"share_token,omitempty"`
+	ItemCount   int              `json:"item_count"`
+	Items       []CollectionItem `json:"items,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// CollectionItem is an entry's place in a collection, with enough of the
+// entry to show it. Trashed entries are left out until they are restored.
+type CollectionItem struct {
+	ID         int64     `json:"id"`
+	ItemType   string    `json:"item_type"`
+	ItemID     int64     `json:"item_id"`
+	Position   int       `json:"position"`
+	Notes      string    `json:"notes"`
+	AddedAt    time.Time `json:"added_at"`
+	Title      string    `json:"title"`
+	CoverImage string    `json:"cover_image"`
+	Status     string    `json:"status"`
+	Rating     int       `json:"rating"`
+}
+
+type CollectionQuery struct {
+	Limit  int `form:"limit"`
+	Offset int `form:"offset"`
+}
+
+type CreateCollectionRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	CoverImage  string `json:"cover_image"`
+	Visibility  string `json:"visibility" binding:"omitempty,oneof=private unlisted public"`
+}
+
+type UpdateCollectionRequest struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+	CoverImage  *string `json:"cover_image"`
+	Visibility  string  `json:"visibility" binding:"omitempty,oneof=private unlisted public"`
+}
+
+type CollectionItemRef struct {
+	ItemType string `json:"item_type" binding:"required"`
+	ItemID   int64  `json:"item_id" binding:"required"`
+	Notes    string `json:"notes"`
+}
+
+// AddCollectionItemsRequest appends entries in the order given; entries
+// already in the collection are skipped.
+type AddCollectionItemsRequest struct {
+	Items []CollectionItemRef `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// RemoveCollectionItemsRequest removes entries by item_type and item_id;
+// notes are ignored.
+type RemoveCollectionItemsRequest struct {
+	Items []CollectionItemRef `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+// ReorderCollectionRequest lists item IDs of the collection in their new
+// order. Items left out keep their relative order after the listed ones.
+type ReorderCollectionRequest struct {
+	Items []int64 `json:"items" binding:"required,min=1"`
+}
+
+type UpdateCollectionItemRequest struct {
+	Notes string `json:"notes"`
+}
+
+type CollectionItemsResponse struct {
+	Added   int    `json:"added,omitempty"`
+	Removed int    `json:"removed,omitempty"`
+	Skipped int    `json:"skipped,omitempty"`
+	Message string `json:"message"`
+}
+
+// Relation kinds. A relation reads "entry is <kind> other entry".
+const (
+	RelationSequelOf     = "sequel_of"
+	RelationPrequelOf    = "prequel_of"
+	RelationAdaptationOf = "adaptation_of"
+	RelationSameSeries   = "same_series"
+	RelationDLCOf        = "dlc_of"
+	RelationRemakeOf     = "remake_of"
+)
+
+var RelationKinds = []string{
+	RelationSequelOf, RelationPrequelOf, RelationAdaptationOf, RelationSameSeries, RelationDLCOf, RelationRemakeOf,
+}
+
+// EntrySummary is enough of a media entry to show it next to another.
+type EntrySummary struct {
+	ItemType   string `json:"item_type"`
+	ItemID     int64  `json:"item_id"`
+	Title      string `json:"title"`
+	CoverImage string `json:"cover_image"`
+	Status     string `json:"status"`
+	Rating     int    `json:"rating"`
+}
+
+// Relation links two entries. Direction is "outgoing" when the requested
+// entry is <kind> Entry and "incoming" when Entry is <kind> the requested
+// entry.
+type Relation struct {
+	ID        int64        `json:"id"`
+	Kind      string       `json:"kind"`
+	Direction string       `json:"direction"`
+	Entry     EntrySummary `json:"entry"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// CreateRelationRequest records that the entry in the path is <kind> the
+// entry named by item_type and item_id.
+type CreateRelationRequest struct {
+	Kind     string `json:"kind" binding:"required,oneof=sequel_of prequel_of adaptation_of same_series dlc_of remake_of"`
+	ItemType string `json:"item_type" binding:"required"`
+	ItemID   int64  `json:"item_id" binding:"required"`
+}
+
+// SeriesProgress counts the completed entries of a series, leaving trashed
+// entries out.
+type SeriesProgress struct {
+	Completed int    `json:"completed"`
+	Total     int    `json:"total"`
+	Label     string `json:"label"`
+}
+
+type Series struct {
+	ID          int64          `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Progress    SeriesProgress `json:"progress"`
+	Entries     []SeriesEntry  `json:"entries,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// SeriesEntry is an entry's place in a series. Numbers need not be whole or
+// unique, so 2.5 sits between 2 and 3 and an omnibus can share a number.
+type SeriesEntry struct {
+	ID      int64        `json:"id"`
+	Number  float64      `json:"number"`
+	Entry   EntrySummary `json:"entry"`
+	AddedAt time.Time    `json:"added_at"`
+}
+
+// SeriesMembership is a series an entry belongs to, from the entry's side.
+type SeriesMembership struct {
+	SeriesID int64          `json:"series_id"`
+	Title    string         `json:"title"`
+	Number   float64        `json:"number"`
+	Progress SeriesProgress `json:"progress"`
+}
+
+type RelatedResponse struct {
+	ItemType  string             `json:"item_type"`
+	ItemID    int64              `json:"item_id"`
+	Relations []Relation         `json:"relations"`
+	Series    []SeriesMembership `json:"series"`
+}
+
+type CreateSeriesRequest struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+}
+
+type UpdateSeriesRequest struct {
+	Title       string  `json:"title"`
+	Description *string `json:"description"`
+}
+
+// AddSeriesEntryRequest adds an entry to a series; number defaults to one
+// past the highest number in the series.
+type AddSeriesEntryRequest struct {
+	ItemType string   `json:"item_type" binding:"required"`
+	ItemID   int64    `json:"item_id" binding:"required"`
+	Number   *float64 `json:"number" binding:"omitempty,gte=0"`
+}
+
+type UpdateSeriesEntryRequest struct {
+	Number float64 `json:"number" binding:"gte=0"`
+}
+
+// ImportRequest is the form of an import; the export itself is the "file"
+// field. Defaults is a JSON object of create request fields that fill in
+// whatever a row leaves empty.
+type ImportRequest struct {
+	Source      string `form:"source" binding:"required"`
+	Defaults    string `form:"defaults"`
+	DryRun      bool   `form:"dry_run"`
+	SkipInvalid bool   `form:"skip_invalid"`
+}
+
+// Import row results.
+const (
+	ImportValid     = "valid"
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+)
+
+// ImportRow is the outcome for one row of an export. Entry is the create
+// request the row maps to, defaults included. DuplicateOf is the existing
+// entry a duplicate matches, or nil when it repeats an earlier row.
+type ImportRow struct {
+	Line        int            `json:"line"`
+	ItemType    string         `json:"item_type,omitempty"`
+	Title       string         `json:"title"`
+	Result      string         `json:"result"`
+	ID          *int64         `json:"id,omitempty"`
+	DuplicateOf *int64         `json:"duplicate_of,omitempty"`
+	Errors      []string       `json:"errors,omitempty"`
+	Entry       map[string]any `json:"entry"`
+}
+
+type ImportResponse struct {
+	Source     string      `json:"source"`
+	ItemType   string      `json:"item_type"`
+	DryRun     bool        `json:"dry_run"`
+	Total      int         `json:"total"`
+	Valid      int         `json:"valid"`
+	Created    int         `json:"created"`
+	Duplicates int         `json:"duplicates"`
+	Invalid    int         `json:"invalid"`
+	Rows       []ImportRow `json:"rows"`
+	Message    string      `json:"message"`
+}
+
+// ExportVersion is the version of the catalog export's JSON layout. It only
+// changes when a field changes meaning or goes away; readers reject versions
+// they don't know.
+const ExportVersion = 1
+
+// Catalog export formats.
+const (
+	ExportJSON     = "json"
+	ExportCSV      = "csv"
+	ExportMarkdown = "md"
+)
+
+// ExportQuery selects the export format. Covers bundles the catalog with
+// the cover files we store in a zip archive.
+type ExportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=json csv md"`
+	Covers bool   `form:"covers"`
+}
+
+// CatalogExport is the JSON export of one user's catalog, which
+// POST /import/catalog reads back.
+type CatalogExport struct {
+	Version    int           `json:"version"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Username   string        `json:"username"`
+	Entries    []ExportEntry `json:"entries"`
+}
+
+// ExportEntry is one entry of any media type. Fields holds the type's own
+// stored columns by name. CoverFile is the cover's path inside a zip export,
+// set only when the cover was bundled.
+type ExportEntry struct {
+	ItemType    string          `json:"item_type"`
+	ID          int64           `json:"id"`
+	Title       string          `json:"title"`
+	Genres      StringArray     `json:"genres"`
+	Tags        StringArray     `json:"tags"`
+	Rating      int             `json:"rating"`
+	Status      string          `json:"status"`
+	Description string          `json:"description"`
+	MyThoughts  string          `json:"my_thoughts"`
+	CoverImage  string          `json:"cover_image"`
+	CoverFile   string          `json:"cover_file,omitempty"`
+	Explicit    bool            `json:"explicit"`
+	Color       string          `json:"color"`
+	Fields      map[string]any  `json:"fields"`
+	Links       []ExportLink    `json:"links"`
+	Comments    []ExportComment `json:"comments"`
+	StartedAt   *time.Time      `json:"started_at"`
+	CompletedAt *time.Time      `json:"completed_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type ExportLink struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// ExportComment is a comment on an exported entry, by any user. Author is
+// the commenter's username.
+type ExportComment struct {
+	Author    string    `json:"author"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CatalogImportRequest is the form of a catalog import; the JSON export, or
+// a zip export with its covers, is the "file" field.
+type CatalogImportRequest struct {
+	DryRun      bool `form:"dry_run"`
+	SkipInvalid bool `form:"skip_invalid"`
+}
+
+// CatalogImportResponse reports a catalog import. Rows are numbered by
+// their 1-based position in the export's entries. Imported comments belong
+// to the importing user.
+type CatalogImportResponse struct {
+	Version    int         `json:"version"`
+	DryRun     bool        `json:"dry_run"`
+	Total      int         `json:"total"`
+	Valid      int         `json:"valid"`
+	Created    int         `json:"created"`
+	Duplicates int         `json:"duplicates"`
+	Invalid    int         `json:"invalid"`
+	Covers     int         `json:"covers"`
+	Comments   int         `json:"comments"`
+	Rows       []ImportRow `json:"rows"`
+	Message    string      `json:"message"`
+}
+
+// MetadataCandidate is a match from a metadata provider, normalized onto the
+// create request fields. Creator fills the type's creator column (author,
+// developer and so on); Fields holds other create request fields the
+// provider knows, such as page_count.
+type MetadataCandidate struct {
+	Provider    string         `json:"provider"`
+	ID          string         `json:"id"`
+	ItemType    string         `json:"item_type"`
+	Title       string         `json:"title"`
+	Creator     string         `json:"creator"`
+	Description string         `json:"description"`
+	Genres      []string       `json:"genres"`
+	CoverURL    string         `json:"cover_url"`
+	Year        int            `json:"year,omitempty"`
+	Links       []MediaLink    `json:"links"`
+	Fields      map[string]any `json:"fields"`
+}
+
+type MetadataProvider struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	ItemType string `json:"item_type"`
+}
+
+// MetadataSearchParams searches the providers for a media type by title
+// (q), ISBN or a provider's own ID. Provider limits the search to one of
+// them.
+type MetadataSearchParams struct {
+	Type     string `form:"type" binding:"required"`
+	Q        string `form:"q"`
+	ISBN     string `form:"isbn"`
+	ID       string `form:"id"`
+	Provider string `form:"provider"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=25"`
+}
+
+// MetadataSearchResponse lists the candidates of every provider searched.
+// Errors holds the providers that failed, by name, when others answered.
+type MetadataSearchResponse struct {
+	Results []MetadataCandidate `json:"results"`
+	Errors  map[string]string   `json:"errors,omitempty"`
+}
+
+// FromMetadataRequest names the candidate to create an entry from. Any other
+// create request field in the body, such as rating, status or my_thoughts,
+// fills in or overrides what the candidate provides.
+type FromMetadataRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	ID       string `json:"id" binding:"required"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{StatusPlanned, StatusPlanned, true},
+		{StatusPlanned, StatusInProgress, true},
+		{StatusPlanned, StatusCompleted, true},
+		{StatusPlanned, StatusDropped, true},
+		{StatusPlanned, StatusPaused, false},
+		{StatusInProgress, StatusPaused, true},
+		{StatusInProgress, StatusCompleted, true},
+		{StatusInProgress, StatusDropped, true},
+		{StatusInProgress, StatusPlanned, false},
+		{StatusPaused, StatusInProgress, true},
+		{StatusPaused, StatusCompleted, true},
+		{StatusPaused, StatusPlanned, false},
+		{StatusCompleted, StatusInProgress, true},
+		{StatusCompleted, StatusCompleted, true},
+		{StatusCompleted, StatusPlanned, false},
+		{StatusCompleted, StatusDropped, false},
+		{StatusDropped, StatusPlanned, true},
+		{StatusDropped, StatusInProgress, true},
+		{StatusDropped, StatusCompleted, false},
+		{"typo", StatusPlanned, false},
+		{StatusPlanned, "typo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+" to "+tt.to, func(t *testing.T) {
+			if got := CanTransition(tt.from, tt.to); got != tt.allowed {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestStatusTransitionsStayInVocabulary(t *testing.T) {
+	known := map[string]bool{}
+	for _, status := range Statuses {
+		known[status] = true
+	}
+
+	for _, status := range Statuses {
+		if _, ok := StatusTransitions[status]; !ok {
+			t.Errorf("%s has no transitions", status)
+		}
+	}
+	for from, next := range StatusTransitions {
+		if !known[from] {
+			t.Errorf("transitions from unknown status %s", from)
+		}
+		for _, to := range next {
+			if !known[to] {
+				t.Errorf("%s moves to unknown status %s", from, to)
+			}
+		}
+	}
+}
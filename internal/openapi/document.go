@@ -0,0 +1,223 @@
+package openapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Servers    []Server                        `json:"servers,omitempty"`
+	Tags       []Tag                           `json:"tags,omitempty"`
+	Paths      map[string]map[string]*OpObject `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type OpObject struct {
+	OperationID  string                `json:"operationId"`
+	Summary      string                `json:"summary,omitempty"`
+	Description  string                `json:"description,omitempty"`
+	Tags         []string              `json:"tags,omitempty"`
+	Parameters   []Parameter           `json:"parameters,omitempty"`
+	RequestBody  *RequestBody          `json:"requestBody,omitempty"`
+	Responses    map[string]*Response  `json:"responses"`
+	Security     []map[string][]string `json:"security,omitempty"`
+	RequiredRole string                `json:"x-required-role,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+const bearerAuth = "bearerAuth"
+
+// errorSchema covers both error shapes handlers return: a bare string and
+// utils.GenErr's {title, message} object.
+var errorSchema = &Schema{
+	Type: "object",
+	Properties: map[string]*Schema{
+		"error": {AnyOf: []*Schema{
+			{Type: "string"},
+			{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"title":   {Type: "string"},
+					"message": {Type: "string"},
+				},
+			},
+		}},
+	},
+	Required: []string{"error"},
+}
+
+// Document builds an OpenAPI 3.1 document for the engine's routes.
+func (r *Registry) Document(routes gin.RoutesInfo, serverURL string) *Document {
+	builder := newSchemaBuilder()
+	builder.components["Error"] = errorSchema
+
+	doc := &Document{
+		OpenAPI: "3.1.0",
+		Info:    Info{Title: r.Title, Version: r.Version},
+		Paths:   map[string]map[string]*OpObject{},
+		Components: Components{
+			Schemas: builder.components,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	if serverURL != "" {
+		doc.Servers = []Server{{URL: strings.TrimRight(serverURL, "/")}}
+	}
+
+	merged := r.Routes(routes)
+	for _, key := range r.GroupKeys(merged) {
+		group := r.Group(key)
+		doc.Tags = append(doc.Tags, Tag{Name: group.Key, Description: group.Description})
+	}
+
+	seenIDs := map[string]bool{}
+	for _, route := range merged {
+		path := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*OpObject{}
+		}
+		id := route.Operation.OperationID
+		if id == "" {
+			id = operationID(route)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = builder.operation(route, uniqueOperationID(id, route.Method, seenIDs))
+	}
+
+	return doc
+}
+
+func (b *schemaBuilder) operation(route Route, id string) *OpObject {
+	op := route.Operation
+	obj := &OpObject{
+		OperationID: id,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        []string{op.Group},
+		Responses:   map[string]*Response{},
+	}
+
+	for _, name := range route.Params {
+		obj.Parameters = append(obj.Parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	obj.Parameters = append(obj.Parameters, b.queryParameters(op.Query)...)
+
+	if op.Request != nil || op.Upload != "" {
+		obj.RequestBody = &RequestBody{Required: true, Content: map[string]*MediaType{}}
+	}
+	if op.Request != nil {
+		obj.RequestBody.Content["application/json"] = &MediaType{Schema: b.schemaOf(op.Request)}
+	}
+	if op.Upload != "" {
+		form := &Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{op.Upload: {Type: "string", Format: "binary"}},
+			Required:   []string{op.Upload},
+		}
+		for _, field := range b.queryParameters(op.Form) {
+			form.Properties[field.Name] = field.Schema
+			if field.Required {
+				form.Required = append(form.Required, field.Name)
+			}
+		}
+		obj.RequestBody.Content["multipart/form-data"] = &MediaType{Schema: form}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case op.Produces != "":
+		success.Content = map[string]*MediaType{op.Produces: {}}
+	case op.Response != nil:
+		success.Content = map[string]*MediaType{"application/json": {Schema: b.schemaOf(op.Response)}}
+	}
+	obj.Responses[strconv.Itoa(status)] = success
+
+	if op.Request != nil || op.Form != nil || op.Query != nil || len(route.Params) > 0 {
+		obj.Responses["400"] = errorResponse(http.StatusBadRequest)
+	}
+	if op.Auth != Public {
+		obj.Security = []map[string][]string{{bearerAuth: {}}}
+		obj.Responses["401"] = errorResponse(http.StatusUnauthorized)
+	}
+	if op.Auth == Admin {
+		obj.RequiredRole = "admin"
+		obj.Responses["403"] = errorResponse(http.StatusForbidden)
+	}
+	if len(route.Params) > 0 {
+		obj.Responses["404"] = errorResponse(http.StatusNotFound)
+	}
+	for _, status := range op.Errors {
+		obj.Responses[strconv.Itoa(status)] = errorResponse(status)
+	}
+
+	return obj
+}
+
+func errorResponse(status int) *Response {
+	return &Response{
+		Description: http.StatusText(status),
+		Content: map[string]*MediaType{
+			"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}},
+		},
+	}
+}
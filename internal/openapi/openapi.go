@@ -0,0 +1,219 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Auth is the access level a route requires.
+type Auth int
+
+const (
+	Public Auth = iota
+	User
+	Admin
+)
+
+// Operation documents a single route. Request, Query and Response are
+// example values (usually zero-valued structs) whose types are reflected into
+// schemas, so the spec follows the models package automatically.
+type Operation struct {
+	Group       string
+	Summary     string
+	Description string
+	Auth        Auth
+
+	// Query is a struct whose `form` tags describe the query string.
+	Query any
+	// Request is the JSON body. Upload names the multipart/form-data file
+	// field for routes that accept a file instead.
+	Request any
+	Upload  string
+	// Form is a struct whose `form` tags describe the other multipart fields
+	// sent along with Upload.
+	Form any
+
+	Response any
+	// Status is the success status code; it defaults to 200.
+	Status int
+	// Errors lists error statuses the route responds with beyond the ones
+	// implied by its parameters and auth level.
+	Errors []int
+	// Produces overrides the success content type for non-JSON responses.
+	Produces string
+	// OperationID overrides the ID derived from the handler name, for
+	// handlers shared by several routes.
+	OperationID string
+}
+
+type Group struct {
+	Key         string
+	Name        string
+	Description string
+}
+
+// Registry holds the documentation for routes registered on a gin engine.
+// Routes are always taken from the engine, so undocumented routes still show
+// up and documented routes that no longer exist do not.
+type Registry struct {
+	Title      string
+	Version    string
+	Groups     []Group
+	Operations map[string]Operation
+}
+
+// Lookup returns the operation documented for method and a gin path pattern,
+// keyed as "GET /api/v1/games/:id".
+func (r *Registry) Lookup(method, path string) (Operation, bool) {
+	op, ok := r.Operations[method+" "+path]
+	return op, ok
+}
+
+// Route is a registered route merged with its documentation.
+type Route struct {
+	Method    string
+	Path      string
+	Params    []string
+	Handler   string
+	Operation Operation
+}
+
+var methodOrder = map[string]int{
+	http.MethodGet:     0,
+	http.MethodHead:    1,
+	http.MethodPost:    2,
+	http.MethodPut:     3,
+	http.MethodPatch:   4,
+	http.MethodDelete:  5,
+	http.MethodOptions: 6,
+}
+
+// Routes merges the engine's routes with the registry, sorted by path and
+// then method. Undocumented routes get their group from the path.
+func (r *Registry) Routes(routes gin.RoutesInfo) []Route {
+	merged := make([]Route, 0, len(routes))
+	for _, ri := range routes {
+		op, _ := r.Lookup(ri.Method, ri.Path)
+		if op.Group == "" {
+			op.Group = groupFromPath(ri.Path)
+		}
+
+		merged = append(merged, Route{
+			Method:    ri.Method,
+			Path:      ri.Path,
+			Params:    pathParams(ri.Path),
+			Handler:   ri.Handler,
+			Operation: op,
+		})
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Path != merged[j].Path {
+			return merged[i].Path < merged[j].Path
+		}
+		return methodOrder[merged[i].Method] < methodOrder[merged[j].Method]
+	})
+
+	return merged
+}
+
+// Group returns the group registered under key, falling back to a
+// capitalised key for groups without metadata.
+func (r *Registry) Group(key string) Group {
+	for _, g := range r.Groups {
+		if g.Key == key {
+			return g
+		}
+	}
+	return Group{Key: key, Name: strings.ToUpper(key[:1]) + key[1:]}
+}
+
+// GroupKeys returns every group with at least one route, registered groups
+// first in registry order.
+func (r *Registry) GroupKeys(routes []Route) []string {
+	present := map[string]bool{}
+	for _, route := range routes {
+		present[route.Operation.Group] = true
+	}
+
+	var keys []string
+	for _, g := range r.Groups {
+		if present[g.Key] {
+			keys = append(keys, g.Key)
+			delete(present, g.Key)
+		}
+	}
+
+	var extra []string
+	for key := range present {
+		extra = append(extra, key)
+	}
+	sort.Strings(extra)
+
+	return append(keys, extra...)
+}
+
+func groupFromPath(path string) string {
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/v1"), "/"), "/")
+	if segments[0] == "" || strings.ContainsAny(segments[0], ":*") {
+		return "system"
+	}
+	return segments[0]
+}
+
+var pathParamPattern = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+func pathParams(path string) []string {
+	var params []string
+	for _, match := range pathParamPattern.FindAllStringSubmatch(path, -1) {
+		params = append(params, match[1])
+	}
+	return params
+}
+
+// openAPIPath rewrites gin's :id and *key segments as {id} and {key}.
+func openAPIPath(path string) string {
+	return pathParamPattern.ReplaceAllString(path, "{$1}")
+}
+
+// operationID derives an ID from the handler's method name, e.g.
+// "....(*CommentHandler).GetComments-fm" becomes "GetComments".
+func operationID(route Route) string {
+	name := strings.TrimSuffix(route.Handler, "-fm")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	if name == "" || strings.HasPrefix(name, "func") {
+		name = strings.ToLower(route.Method)
+		for _, segment := range strings.Split(openAPIPath(route.Path), "/") {
+			segment = strings.Trim(segment, "{}")
+			if segment == "" || segment == "api" || segment == "v1" {
+				continue
+			}
+			name += strings.ToUpper(segment[:1]) + segment[1:]
+		}
+		name = strings.NewReplacer("-", "", ".", "").Replace(name)
+	}
+
+	return name
+}
+
+func uniqueOperationID(id string, method string, seen map[string]bool) string {
+	if !seen[id] {
+		seen[id] = true
+		return id
+	}
+
+	id += strings.ToUpper(method[:1]) + strings.ToLower(method[1:])
+	for n := 2; seen[id]; n++ {
+		id = fmt.Sprintf("%s%d", strings.TrimRight(id, "0123456789"), n)
+	}
+	seen[id] = true
+	return id
+}
@@ -0,0 +1,259 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is the subset of JSON Schema (2020-12, as used by OpenAPI 3.1) the
+// generator emits.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaBuilder reflects Go types into schemas, registering exported named
+// structs as reusable components.
+type schemaBuilder struct {
+	components map[string]*Schema
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{components: map[string]*Schema{}}
+}
+
+func (b *schemaBuilder) schemaOf(v any) *Schema {
+	if v == nil {
+		return nil
+	}
+	return b.schema(reflect.TypeOf(v))
+}
+
+func (b *schemaBuilder) schema(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Pointer {
+		s := b.schema(t.Elem())
+		if s.Ref != "" {
+			return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+		}
+		if typ, ok := s.Type.(string); ok {
+			s.Type = []string{typ, "null"}
+		}
+		return s
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schema(t.Elem())}
+	case reflect.Struct:
+		return b.structSchema(t)
+	default:
+		return &Schema{}
+	}
+}
+
+func (b *schemaBuilder) structSchema(t reflect.Type) *Schema {
+	name := t.Name()
+	component := name != "" && unicode.IsUpper(rune(name[0])) && !strings.Contains(name, "[")
+	if !component {
+		return b.objectSchema(t)
+	}
+
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+	if _, ok := b.components[name]; ok {
+		return ref
+	}
+
+	// Reserve the name first so self-referencing types terminate.
+	b.components[name] = &Schema{}
+	*b.components[name] = *b.objectSchema(t)
+	return ref
+}
+
+func (b *schemaBuilder) objectSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	b.addFields(s, t)
+	return s
+}
+
+func (b *schemaBuilder) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				b.addFields(s, embedded)
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := b.schema(field.Type)
+		if applyBinding(prop, field.Tag.Get("binding")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// applyBinding translates validator tags into schema constraints and reports
+// whether the field is required.
+func applyBinding(s *Schema, binding string) bool {
+	if binding == "" {
+		return false
+	}
+
+	required := false
+	for _, rule := range strings.Split(binding, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "email":
+			s.Format = "email"
+		case "url":
+			s.Format = "uri"
+		case "oneof":
+			for _, option := range strings.Fields(value) {
+				if n, err := strconv.Atoi(option); err == nil && s.Type == "integer" {
+					s.Enum = append(s.Enum, n)
+					continue
+				}
+				s.Enum = append(s.Enum, option)
+			}
+		case "min", "max", "gte", "lte":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			setBound(s, key == "min" || key == "gte", n)
+		}
+	}
+
+	return required
+}
+
+func setBound(s *Schema, lower bool, n float64) {
+	typ, _ := s.Type.(string)
+	if types, ok := s.Type.([]string); ok && len(types) > 0 {
+		typ = types[0]
+	}
+
+	count := int(n)
+	switch typ {
+	case "string":
+		if lower {
+			s.MinLength = &count
+		} else {
+			s.MaxLength = &count
+		}
+	case "array":
+		if lower {
+			s.MinItems = &count
+		} else {
+			s.MaxItems = &count
+		}
+	default:
+		if lower {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+}
+
+// queryParameters turns a struct's `form` tags into query parameters.
+func (b *schemaBuilder) queryParameters(v any) []Parameter {
+	if v == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			params = append(params, b.queryParameters(reflect.New(field.Type).Elem().Interface())...)
+			continue
+		}
+		if name == "" || name == "-" {
+			continue
+		}
+
+		schema := b.schema(field.Type)
+		required := applyBinding(schema, field.Tag.Get("binding"))
+		params = append(params, Parameter{
+			Name:     name,
+			In:       "query",
+			Required: required,
+			Schema:   schema,
+		})
+	}
+	return params
+}
@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type bindingExample struct {
+	Name     string    `json:"name" binding:"required,min=3,max=50"`
+	Email    string    `json:"email" binding:"required,email"`
+	Website  string    `json:"website" binding:"omitempty,url"`
+	Status   string    `json:"status" binding:"omitempty,oneof=planned in_progress"`
+	Width    int       `json:"width" binding:"omitempty,oneof=160 320"`
+	Rating   int       `json:"rating" binding:"gte=0,lte=5"`
+	Progress *float64  `json:"progress" binding:"omitempty,min=0,max=100"`
+	Items    []int64   `json:"items" binding:"required,min=1,max=3"`
+	Note     *string   `json:"note" binding:"omitempty,max=10"`
+	When     time.Time `json:"when"`
+	Skipped  string    `json:"-"`
+	Untagged bool
+	private  string
+	bindingEmbedded
+}
+
+type bindingEmbedded struct {
+	Inner string `json:"inner" binding:"required"`
+}
+
+func TestStructSchema(t *testing.T) {
+	// Unexported types are inlined rather than made components.
+	got, _ := json.Marshal(newSchemaBuilder().schemaOf(bindingExample{}))
+	want := `{"type":"object","properties":{` +
+		`"Untagged":{"type":"boolean"},` +
+		`"email":{"type":"string","format":"email"},` +
+		`"inner":{"type":"string"},` +
+		`"items":{"type":"array","items":{"type":"integer","format":"int64"},"minItems":1,"maxItems":3},` +
+		`"name":{"type":"string","minLength":3,"maxLength":50},` +
+		`"note":{"type":["string","null"],"maxLength":10},` +
+		`"progress":{"type":["number","null"],"minimum":0,"maximum":100},` +
+		`"rating":{"type":"integer","minimum":0,"maximum":5},` +
+		`"status":{"type":"string","enum":["planned","in_progress"]},` +
+		`"website":{"type":"string","format":"uri"},` +
+		`"when":{"type":"string","format":"date-time"},` +
+		`"width":{"type":"integer","enum":[160,320]}` +
+		`},"required":["name","email","items","inner"]}`
+	if string(got) != want {
+		t.Errorf("schema =\n%s\nwant\n%s", got, want)
+	}
+}
+
+type Named struct {
+	ID     int64  `json:"id"`
+	Parent *Named `json:"parent"`
+}
+
+func TestComponents(t *testing.T) {
+	b := newSchemaBuilder()
+
+	// Exported named structs become components, referenced wherever they
+	// appear; pointers to them are nullable references.
+	got, _ := json.Marshal(b.schemaOf([]Named{}))
+	if want := `{"type":"array","items":{"$ref":"#/components/schemas/Named"}}`; string(got) != want {
+		t.Errorf("schema = %s, want %s", got, want)
+	}
+
+	component, _ := json.Marshal(b.components["Named"])
+	want := `{"type":"object","properties":{` +
+		`"id":{"type":"integer","format":"int64"},` +
+		`"parent":{"anyOf":[{"$ref":"#/components/schemas/Named"},{"type":"null"}]}}}`
+	if string(component) != want {
+		t.Errorf("component = %s, want %s", component, want)
+	}
+}
+
+func TestQueryParameters(t *testing.T) {
+	type page struct {
+		Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+		Offset int `form:"offset"`
+	}
+	type query struct {
+		Q      string `form:"q" binding:"required"`
+		Format string `form:"fmt" binding:"omitempty,oneof=jpeg png"`
+		Ignore string
+		page
+	}
+
+	got, _ := json.Marshal(newSchemaBuilder().queryParameters(query{}))
+	want := `[` +
+		`{"name":"q","in":"query","required":true,"schema":{"type":"string"}},` +
+		`{"name":"fmt","in":"query","schema":{"type":"string","enum":["jpeg","png"]}},` +
+		`{"name":"limit","in":"query","schema":{"type":"integer","minimum":1,"maximum":100}},` +
+		`{"name":"offset","in":"query","schema":{"type":"integer"}}` +
+		`]`
+	if string(got) != want {
+		t.Errorf("parameters =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// PutContentAddressed stores r under "<sha256><ext>" unless an object with
+// that key already exists, and reports which of the two happened.
+func PutContentAddressed(ctx context.Context, store Store, r io.ReadSeeker, size int64, ext string, contentType string) (string, bool, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", false, fmt.Errorf("seek error: %v", err)
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return "", false, fmt.Errorf("hash error: %v", err)
+	}
+	key := hex.EncodeToString(hasher.Sum(nil)) + ext
+
+	if _, err := store.Stat(ctx, key); err == nil {
+		return key, true, nil
+	} else if !errors.Is(err, ErrNotFound) {
+		return "", false, err
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", false, fmt.Errorf("seek error: %v", err)
+	}
+
+	if err := store.Put(ctx, key, r, size, contentType); err != nil {
+		return "", false, err
+	}
+
+	return key, false, nil
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "files"), "/files/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	data := []byte("not really a png")
+
+	if err := store.Put(ctx, "covers/a1.png", bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(dir, "files", "covers", "a1.png")); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("file = %q, %v", got, err)
+	}
+
+	info, err := store.Stat(ctx, "covers/a1.png")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if info.Key != "covers/a1.png" || info.Size != int64(len(data)) || info.ContentType != "image/png" {
+		t.Errorf("Stat = %+v", info)
+	}
+
+	body, _, err := store.Get(ctx, "covers/a1.png")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(body)
+	body.Close()
+	if !bytes.Equal(got, data) {
+		t.Errorf("Get = %q", got)
+	}
+
+	if url := store.URL("covers/a1.png"); url != "/files/covers/a1.png" {
+		t.Errorf("URL = %s", url)
+	}
+
+	if err := store.Delete(ctx, "covers/a1.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Stat(ctx, "covers/a1.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat after Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStoreNotFound(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Directories aren't objects.
+	if err := store.Put(ctx, "dir/a.png", strings.NewReader("x"), 1, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"missing.png", "dir"} {
+		if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%s) = %v, want ErrNotFound", key, err)
+		}
+		if _, err := store.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Stat(%s) = %v, want ErrNotFound", key, err)
+		}
+	}
+	if err := store.Delete(ctx, "missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalStoreKeys(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "files"), "/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// A file next to the store that no key may reach.
+	secret := filepath.Join(dir, "secret.txt")
+	if err := os.WriteFile(secret, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"", "../secret.txt", "/secret.txt", "a/../../secret.txt", "a//b.png", "./a.png", "a/"} {
+		t.Run(key, func(t *testing.T) {
+			if _, _, err := store.Get(ctx, key); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Get = %v, want ErrInvalidKey", err)
+			}
+			if _, err := store.Stat(ctx, key); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Stat = %v, want ErrInvalidKey", err)
+			}
+			if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Put = %v, want ErrInvalidKey", err)
+			}
+			if err := store.Delete(ctx, key); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("Delete = %v, want ErrInvalidKey", err)
+			}
+		})
+	}
+
+	if got, _ := os.ReadFile(secret); string(got) != "secret" {
+		t.Error("a key escaped the store")
+	}
+
+	// Characters that mean something in URLs are just part of the file name.
+	key := "a b/c?d#e%20.png"
+	if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err != nil {
+		t.Fatalf("Put(%q): %v", key, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "files", "a b", "c?d#e%20.png")); err != nil {
+		t.Errorf("Put(%q) wrote elsewhere: %v", key, err)
+	}
+}
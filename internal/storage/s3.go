@@ -0,0 +1,270 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+)
+
+// S3Store talks to any S3-compatible API (AWS, MinIO, R2, ...) using plain
+// net/http and AWS Signature Version 4.
+type S3Store struct {
+	endpoint  *url.URL
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	publicURL string
+	client    *http.Client
+}
+
+func NewS3Store(cfg config.S3Config, publicURL string) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT and S3_BUCKET are required for the s3 storage backend")
+	}
+
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT %q", cfg.Endpoint)
+	}
+
+	return &S3Store{
+		endpoint:  endpoint,
+		bucket:    cfg.Bucket,
+		region:    cfg.Region,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		pathStyle: cfg.PathStyle,
+		publicURL: publicURL,
+		client:    &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	body, ok := r.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("read error: %w", err)
+		}
+		body = bytes.NewReader(data)
+		size = int64(len(data))
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, body); err != nil {
+		return fmt.Errorf("hash error: %w", err)
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, body, hex.EncodeToString(hasher.Sum(nil)))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp.Body, objectInfoFromHeaders(key, resp.Header), nil
+}
+
+func (s *S3Store) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	req, err := s.newRequest(ctx, http.MethodHead, key, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return objectInfoFromHeaders(key, resp.Header), nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if _, err := s.Stat(ctx, key); err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (s *S3Store) URL(key string) string {
+	return joinURL(s.publicURL, key)
+}
+
+// do sends req and converts non-2xx responses into errors, mapping 404 to
+// ErrNotFound.
+func (s *S3Store) do(req *http.Request) (*http.Response, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s failed: %w", req.Method, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s returned %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	return resp, nil
+}
+
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	base := strings.TrimRight(s.endpoint.EscapedPath(), "/")
+
+	if s.pathStyle {
+		u.RawPath = base + "/" + escapeS3Path(s.bucket) + "/" + escapeS3Path(key)
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.RawPath = base + "/" + escapeS3Path(key)
+	}
+	u.Path, _ = url.PathUnescape(u.RawPath)
+
+	return &u
+}
+
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func (s *S3Store) newRequest(ctx context.Context, method string, key string, body io.Reader, payloadHash string) (*http.Request, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	u := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	s.sign(req, u.EscapedPath(), payloadHash, time.Now().UTC())
+	return req, nil
+}
+
+// sign adds an AWS SigV4 Authorization header covering host,
+// x-amz-content-sha256 and x-amz-date.
+func (s *S3Store) sign(req *http.Request, canonicalURI string, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// escapeS3Path URI-encodes every byte except unreserved characters and '/',
+// as SigV4 requires for S3 object keys.
+func escapeS3Path(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		ch := key[i]
+		if ch == '/' || ch == '-' || ch == '_' || ch == '.' || ch == '~' ||
+			('A' <= ch && ch <= 'Z') || ('a' <= ch && ch <= 'z') || ('0' <= ch && ch <= '9') {
+			b.WriteByte(ch)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", ch)
+	}
+	return b.String()
+}
+
+func objectInfoFromHeaders(key string, header http.Header) *ObjectInfo {
+	size, _ := strconv.ParseInt(header.Get("Content-Length"), 10, 64)
+	modTime, _ := http.ParseTime(header.Get("Last-Modified"))
+
+	return &ObjectInfo{
+		Key:         key,
+		Size:        size,
+		ContentType: header.Get("Content-Type"),
+		ModTime:     modTime,
+	}
+}
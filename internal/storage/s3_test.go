@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+)
+
+// s3StandIn stands in for a path-style S3 API holding a single bucket. It
+// checks every request's SigV4 signature against its own computation and
+// that PUT bodies match their x-amz-content-sha256.
+type s3StandIn struct {
+	server *httptest.Server
+
+	mu      sync.Mutex
+	objects map[string]s3Object
+	// paths records the escaped path of every request.
+	paths []string
+}
+
+type s3Object struct {
+	data        []byte
+	contentType string
+}
+
+const (
+	testBucket    = "covers"
+	testRegion    = "eu-west-1"
+	testAccessKey = "access"
+	testSecretKey = "secret"
+)
+
+func newS3StandIn(t *testing.T) *s3StandIn {
+	t.Helper()
+
+	s := &s3StandIn{objects: map[string]s3Object{}}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *s3StandIn) store(t *testing.T, secretKey string) *S3Store {
+	t.Helper()
+
+	store, err := NewS3Store(config.S3Config{
+		Endpoint:  s.server.URL,
+		Bucket:    testBucket,
+		Region:    testRegion,
+		AccessKey: testAccessKey,
+		SecretKey: secretKey,
+		PathStyle: true,
+	}, "https://cdn.example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func (s *s3StandIn) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := r.URL.EscapedPath()
+	s.paths = append(s.paths, path)
+
+	if err := checkSignature(r, path); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	key, ok := strings.CutPrefix(r.URL.Path, "/"+testBucket+"/")
+	if !ok {
+		http.Error(w, "no such bucket", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != r.Header.Get("X-Amz-Content-Sha256") {
+			http.Error(w, "XAmzContentSHA256Mismatch", http.StatusBadRequest)
+			return
+		}
+		s.objects[key] = s3Object{data: data, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet, http.MethodHead:
+		object, ok := s.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Header().Set("Content-Length", fmt.Sprint(len(object.data)))
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2026 07:28:00 GMT")
+		if r.Method == http.MethodGet {
+			w.Write(object.data)
+		}
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// checkSignature recomputes the SigV4 signature of r with the stand-in's
+// credentials, following the AWS documentation rather than S3Store.sign.
+func checkSignature(r *http.Request, canonicalURI string) error {
+	amzDate := r.Header.Get("X-Amz-Date")
+	when, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return fmt.Errorf("bad x-amz-date %q", amzDate)
+	}
+	date := when.Format("20060102")
+	payloadHash := r.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		return errors.New("missing x-amz-content-sha256")
+	}
+
+	canonicalRequest := r.Method + "\n" +
+		canonicalURI + "\n" +
+		r.URL.RawQuery + "\n" +
+		"host:" + r.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n" +
+		"\n" +
+		"host;x-amz-content-sha256;x-amz-date\n" +
+		payloadHash
+	scope := date + "/" + testRegion + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := []byte("AWS4" + testSecretKey)
+	for _, part := range []string{date, testRegion, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	want := "AWS4-HMAC-SHA256 Credential=" + testAccessKey + "/" + scope +
+		", SignedHeaders=host;x-amz-content-sha256;x-amz-date" +
+		", Signature=" + hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	if got := r.Header.Get("Authorization"); got != want {
+		return fmt.Errorf("SignatureDoesNotMatch: got %q, want %q", got, want)
+	}
+	return nil
+}
+
+func TestS3Store(t *testing.T) {
+	s := newS3StandIn(t)
+	store := s.store(t, testSecretKey)
+	ctx := context.Background()
+	data := []byte("not really a png")
+
+	if err := store.Put(ctx, "a1.png", bytes.NewReader(data), int64(len(data)), "image/png"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// Readers that can't seek are buffered to hash them.
+	if err := store.Put(ctx, "dir/b2.txt", io.MultiReader(strings.NewReader("hello")), -1, ""); err != nil {
+		t.Fatalf("Put without seeking: %v", err)
+	}
+	if got := string(s.objects["dir/b2.txt"].data); got != "hello" {
+		t.Errorf("stored %q, want hello", got)
+	}
+
+	info, err := store.Stat(ctx, "a1.png")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	want := ObjectInfo{
+		Key:         "a1.png",
+		Size:        int64(len(data)),
+		ContentType: "image/png",
+		ModTime:     time.Date(2026, 10, 21, 7, 28, 0, 0, time.UTC),
+	}
+	if *info != want {
+		t.Errorf("Stat = %+v, want %+v", *info, want)
+	}
+
+	body, info, err := store.Get(ctx, "a1.png")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	got, _ := io.ReadAll(body)
+	body.Close()
+	if !bytes.Equal(got, data) || *info != want {
+		t.Errorf("Get = %q, %+v", got, *info)
+	}
+
+	if err := store.Delete(ctx, "a1.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, ok := s.objects["a1.png"]; ok {
+		t.Error("Delete left the object behind")
+	}
+
+	if url := store.URL("a1.png"); url != "https://cdn.example.com/a1.png" {
+		t.Errorf("URL = %s", url)
+	}
+}
+
+func TestS3StoreNotFound(t *testing.T) {
+	s := newS3StandIn(t)
+	store := s.store(t, testSecretKey)
+	ctx := context.Background()
+
+	if _, _, err := store.Get(ctx, "missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Stat(ctx, "missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat = %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(ctx, "missing.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete = %v, want ErrNotFound", err)
+	}
+
+	// Other errors are reported with the response, not as missing objects.
+	_, err := s.store(t, "wrong").Stat(ctx, "missing.png")
+	if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Stat with the wrong secret = %v, want a 403 error", err)
+	}
+}
+
+func TestS3StoreKeys(t *testing.T) {
+	s := newS3StandIn(t)
+	store := s.store(t, testSecretKey)
+	ctx := context.Background()
+
+	// Keys are escaped the way SigV4 requires, and the signature covers the
+	// escaped path.
+	key := "a b/c+d=é.png"
+	if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := store.Stat(ctx, key); err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if got, want := s.paths[0], "/covers/a%20b/c%2Bd%3D%C3%A9.png"; got != want {
+		t.Errorf("path = %s, want %s", got, want)
+	}
+
+	sent := len(s.paths)
+	for _, key := range []string{"", "../x.png", "/x.png", "a/../x.png", "a//x.png", "a/"} {
+		if _, err := store.Stat(ctx, key); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Stat(%q) = %v, want ErrInvalidKey", key, err)
+		}
+	}
+	if len(s.paths) != sent {
+		t.Error("invalid keys were sent to S3")
+	}
+}
+
+func TestS3ObjectURL(t *testing.T) {
+	tests := []struct {
+		endpoint  string
+		pathStyle bool
+		want      string
+	}{
+		{"https://s3.example.com", true, "https://s3.example.com/covers/dir/a%20b.png"},
+		{"https://s3.example.com/prefix/", true, "https://s3.example.com/prefix/covers/dir/a%20b.png"},
+		{"https://s3.example.com", false, "https://covers.s3.example.com/dir/a%20b.png"},
+	}
+
+	for _, tt := range tests {
+		store, err := NewS3Store(config.S3Config{Endpoint: tt.endpoint, Bucket: testBucket, PathStyle: tt.pathStyle}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := store.objectURL("dir/a b.png").String(); got != tt.want {
+			t.Errorf("objectURL with %s, path style %v = %s, want %s", tt.endpoint, tt.pathStyle, got, tt.want)
+		}
+	}
+
+	for _, cfg := range []config.S3Config{{Bucket: testBucket}, {Endpoint: "https://s3.example.com"}, {Endpoint: "s3.example.com", Bucket: testBucket}} {
+		if _, err := NewS3Store(cfg, ""); err == nil {
+			t.Errorf("NewS3Store(%+v) succeeded", cfg)
+		}
+	}
+}
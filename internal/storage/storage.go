@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+)
+
+var (
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+)
+
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModTime     time.Time
+}
+
+// Store is where uploaded files and cover images live. Keys are slash
+// separated relative paths such as "<sha256>.png".
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// New builds the Store selected by STORAGE_BACKEND.
+func New(cfg config.StorageConfig, filesDir string) (Store, error) {
+	publicURL := strings.TrimRight(cfg.PublicBaseURL, "/") + "/files"
+
+	switch cfg.Backend {
+	case "local":
+		return NewLocalStore(filesDir, publicURL)
+	case "s3":
+		if cfg.S3.PublicURL != "" {
+			publicURL = cfg.S3.PublicURL
+		}
+		return NewS3Store(cfg.S3, publicURL)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
+
+// cleanKey rejects keys that are empty or would escape the store root.
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != key {
+		return "", fmt.Errorf("%w %q", ErrInvalidKey, key)
+	}
+	return cleaned, nil
+}
+
+// joinURL appends key to base, escaping each of its segments so keys with
+// characters such as '?' or '#' still link to the object.
+func joinURL(base, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.TrimRight(base, "/") + "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.StorageConfig
+		url  string
+	}{
+		{
+			name: "local",
+			cfg:  config.StorageConfig{Backend: "local", PublicBaseURL: "https://media.example.com/"},
+			url:  "https://media.example.com/files/a1.png",
+		},
+		{
+			name: "s3 served by us",
+			cfg: config.StorageConfig{Backend: "s3", PublicBaseURL: "https://media.example.com",
+				S3: config.S3Config{Endpoint: "https://s3.example.com", Bucket: "covers"}},
+			url: "https://media.example.com/files/a1.png",
+		},
+		{
+			name: "s3 with its own public URL",
+			cfg: config.StorageConfig{Backend: "s3", PublicBaseURL: "https://media.example.com",
+				S3: config.S3Config{Endpoint: "https://s3.example.com", Bucket: "covers", PublicURL: "https://cdn.example.com/covers/"}},
+			url: "https://cdn.example.com/covers/a1.png",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := New(tt.cfg, filepath.Join(t.TempDir(), "files"))
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if got := store.URL("a1.png"); got != tt.url {
+				t.Errorf("URL = %s, want %s", got, tt.url)
+			}
+		})
+	}
+
+	if _, err := New(config.StorageConfig{Backend: "ftp"}, t.TempDir()); err == nil {
+		t.Error("New accepted an unknown backend")
+	}
+}
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		base, key, want string
+	}{
+		{"/files", "a1.png", "/files/a1.png"},
+		{"/files/", "a1.png", "/files/a1.png"},
+		{"https://cdn.example.com/", "", "https://cdn.example.com/"},
+		{"/files", "dir/a b?c#d%.png", "/files/dir/a%20b%3Fc%23d%25.png"},
+	}
+
+	for _, tt := range tests {
+		if got := joinURL(tt.base, tt.key); got != tt.want {
+			t.Errorf("joinURL(%q, %q) = %q, want %q", tt.base, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestPutContentAddressed(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	data := []byte("hello")
+	const key = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824.txt"
+
+	for _, existed := range []bool{false, true} {
+		got, gotExisted, err := PutContentAddressed(ctx, store, bytes.NewReader(data), int64(len(data)), ".txt", "text/plain")
+		if err != nil {
+			t.Fatalf("PutContentAddressed: %v", err)
+		}
+		if got != key || gotExisted != existed {
+			t.Errorf("PutContentAddressed = %s, %v; want %s, %v", got, gotExisted, key, existed)
+		}
+	}
+}
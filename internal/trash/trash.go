@@ -0,0 +1,97 @@
+// Package trash knows which tables support soft deletion and permanently
+// removes entries once they have sat in the trash past the retention period.
+package trash
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/thebearodactyl/apiodactyl/internal/config"
+	"github.com/thebearodactyl/apiodactyl/internal/database"
+	"github.com/thebearodactyl/apiodactyl/internal/media"
+)
+
+// Kind is a trashable table. Name is the type used in API paths.
+type Kind struct {
+	Name        string
+	Label       string
+	Table       string
+	TitleColumn string
+}
+
+// Kinds holds every media type followed by resources.
+var Kinds = append(mediaKinds(), Kind{Name: "resource", Label: "Resource", Table: "resources", TitleColumn: "name"})
+
+func mediaKinds() []Kind {
+	kinds := make([]Kind, 0, len(media.Types)+1)
+	for _, t := range media.Types {
+		kinds = append(kinds, Kind{Name: t.Name, Label: t.Label, Table: t.Table, TitleColumn: "title"})
+	}
+	return kinds
+}
+
+func Lookup(name string) (Kind, bool) {
+	for _, kind := range Kinds {
+		if kind.Name == name {
+			return kind, true
+		}
+	}
+	return Kind{}, false
+}
+
+// Names lists the kind names, for error messages.
+func Names() string {
+	names := make([]string, len(Kinds))
+	for i, kind := range Kinds {
+		names[i] = kind.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// timestampLayout matches CURRENT_TIMESTAMP, which is what deleted_at holds.
+const timestampLayout = "2006-01-02 15:04:05"
+
+// Purge permanently deletes every entry trashed before cutoff. The per-type
+// AFTER DELETE triggers clean up what references entries by item_type and
+// item_id: comments, links, status history, taxonomy terms, collection
+// items, relations and series entries.
+func Purge(ctx context.Context, q database.Querier, cutoff time.Time) (int64, error) {
+	var total int64
+	for _, kind := range Kinds {
+		result, err := q.ExecContext(ctx,
+			"DELETE FROM "+kind.Table+" WHERE deleted_at IS NOT NULL AND deleted_at < ?",
+			cutoff.UTC().Format(timestampLayout))
+		if err != nil {
+			return total, fmt.Errorf("purge %s: %w", kind.Table, err)
+		}
+
+		n, _ := result.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
+
+// Run purges expired entries immediately and then every purge interval,
+// until ctx is cancelled.
+func Run(ctx context.Context, db *database.DB, cfg config.TrashConfig) {
+	ticker := time.NewTicker(cfg.PurgeInterval())
+	defer ticker.Stop()
+
+	for {
+		purged, err := Purge(ctx, db, time.Now().Add(-cfg.Retention()))
+		if err != nil {
+			log.Printf("Trash purge failed: %v", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d trashed entries older than %d days", purged, cfg.RetentionDays)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}